@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/grs/mcp-deployment/pkg/deployer"
+	"github.com/grs/mcp-deployment/pkg/kubeclient"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+func main() {
+	kubeconfig := flag.String("kubeconfig", "", "path to a kubeconfig file (default $KUBECONFIG or ~/.kube/config, or the in-cluster config when neither exists)")
+	kubeContext := flag.String("context", "", "kubeconfig context to use")
+	namespace := flag.String("namespace", "", "namespace to watch; all namespaces if empty")
+	workers := flag.Int("workers", 2, "number of MCPServer resources reconciled concurrently")
+	flag.Parse()
+
+	// Create Kubernetes clients
+	config, err := kubeclient.Load(kubeclient.Options{Kubeconfig: *kubeconfig, Context: *kubeContext})
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(config.REST)
+	if err != nil {
+		log.Fatalf("Failed to create clientset: %v", err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(config.REST)
+	if err != nil {
+		log.Fatalf("Failed to create dynamic client: %v", err)
+	}
+
+	controller, err := deployer.NewController(clientset, dynamicClient, *namespace)
+	if err != nil {
+		log.Fatalf("Failed to create controller: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Reconciling MCPServer resources with %d workers", *workers)
+	if err := controller.Run(ctx, *workers); err != nil {
+		log.Fatalf("Controller failed: %v", err)
+	}
+	log.Println("Controller stopped")
+}
@@ -0,0 +1,75 @@
+// Command mcp-bridge serves a stdio MCP server over streamable HTTP and SSE.
+// It runs as the entrypoint of stdio MCP server pods, where an init container
+// first copies it into a shared volume with "mcp-bridge install".
+//
+//	mcp-bridge [--port 8080] [--path /mcp] [--sessions per-session|shared] [--idle-timeout 30m] -- COMMAND [ARGS...]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/grs/mcp-deployment/pkg/bridge"
+	"github.com/grs/mcp-deployment/pkg/selfinstall"
+)
+
+func main() {
+	if len(os.Args) == 3 && os.Args[1] == "install" {
+		if err := selfinstall.Install(os.Args[2]); err != nil {
+			fmt.Fprintf(os.Stderr, "mcp-bridge: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	port := flag.Int("port", 8080, "port to listen on")
+	path := flag.String("path", bridge.DefaultPath, "path of the streamable HTTP endpoint")
+	sessions := flag.String("sessions", string(bridge.PerSession), "per-session to start a server for each session, shared to start one for all")
+	idleTimeout := flag.Duration("idle-timeout", 30*time.Minute, "end sessions without requests for this long, 0 to keep them")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] -- COMMAND [ARGS...]\n       %s install DEST\n\nFlags:\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "mcp-bridge: ", log.LstdFlags)
+	b, err := bridge.New(bridge.Options{
+		Command:     flag.Args(),
+		Mode:        bridge.SessionMode(*sessions),
+		Path:        *path,
+		IdleTimeout: *idleTimeout,
+		Logger:      logger,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mcp-bridge: %v\n", err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	server := &http.Server{Addr: ":" + strconv.Itoa(*port), Handler: b}
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+		<-signals
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		// Event streams stay open, so stop the sessions before waiting for
+		// requests to finish
+		b.Close()
+		server.Shutdown(ctx)
+	}()
+
+	logger.Printf("serving %s on :%d%s (%s sessions)", flag.Arg(0), *port, *path, *sessions)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Fatal(err)
+	}
+}
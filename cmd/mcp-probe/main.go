@@ -0,0 +1,40 @@
+// Command mcp-probe checks that an MCP server answers the initialize
+// handshake. It runs as an exec probe in MCP server pods, where an init
+// container first copies it into a shared volume with "mcp-probe install".
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/grs/mcp-deployment/pkg/mcpprobe"
+	"github.com/grs/mcp-deployment/pkg/selfinstall"
+)
+
+func main() {
+	if len(os.Args) == 3 && os.Args[1] == "install" {
+		if err := selfinstall.Install(os.Args[2]); err != nil {
+			fmt.Fprintf(os.Stderr, "mcp-probe: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	url := flag.String("url", "http://127.0.0.1:8080/mcp", "URL of the MCP endpoint")
+	timeout := flag.Duration("timeout", 5*time.Second, "how long to wait for the handshake")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	info, err := mcpprobe.Check(ctx, http.DefaultClient, *url)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mcp-probe: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s %s is serving MCP %s\n", info.Name, info.Version, info.ProtocolVersion)
+}
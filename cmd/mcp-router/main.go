@@ -0,0 +1,67 @@
+// Command mcp-router keeps the sessions of a replicated MCP server on the
+// replica that holds them. It runs as a sidecar in each pod of servers with
+// router session affinity, in front of the server of the pod, and finds the
+// other pods through a headless Service.
+//
+//	mcp-router --peers HOST [--port 8090] [--backend http://127.0.0.1:8080] [--pod-ip IP]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/grs/mcp-deployment/pkg/router"
+)
+
+func main() {
+	port := flag.Int("port", 8090, "port to listen on, the same in every pod")
+	backend := flag.String("backend", "http://127.0.0.1:8080", "URL of the MCP server of the pod")
+	peers := flag.String("peers", "", "host resolving to the addresses of every pod of the server")
+	podIP := flag.String("pod-ip", os.Getenv("POD_IP"), "address of the pod, $POD_IP by default")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "mcp-router: ", log.LstdFlags)
+	backendURL, err := url.Parse(*backend)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mcp-router: invalid backend URL: %v\n", err)
+		os.Exit(2)
+	}
+	rt, err := router.New(router.Options{
+		Backend: backendURL,
+		Self:    net.ParseIP(*podIP),
+		Peers:   *peers,
+		Port:    *port,
+		Logger:  logger,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mcp-router: %v\n", err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	server := &http.Server{Addr: ":" + strconv.Itoa(*port), Handler: rt}
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+		<-signals
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+
+	logger.Printf("routing sessions on :%d to %s and the pods of %s", *port, *backend, *peers)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Fatal(err)
+	}
+}
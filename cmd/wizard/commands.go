@@ -0,0 +1,353 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grs/mcp-deployment/pkg/deployer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// errFlags is returned when a flag set has already reported an invalid flag
+var errFlags = errors.New("invalid flags")
+
+// newFlagSet creates the flag set of a command with the shared client flags
+func newFlagSet(name, synopsis string, client *clientOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: wizard %s\n\nFlags:\n", synopsis)
+		fs.PrintDefaults()
+	}
+	client.addFlags(fs)
+	return fs
+}
+
+// parseArgs parses flags that may appear before, between or after the
+// positional arguments, and returns the positional arguments
+func parseArgs(fs *flag.FlagSet, args []string, want int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errFlags
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) != want {
+		fs.Usage()
+		return nil, errFlags
+	}
+	return positional, nil
+}
+
+// runInteractiveCommand runs the interactive menu
+func runInteractiveCommand(client *clientOptions, args []string) error {
+	fs := newFlagSet("interactive", "interactive [flags]", client)
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+
+	s, err := newSession(client)
+	if err != nil {
+		return err
+	}
+	runInteractive(s)
+	return nil
+}
+
+// runList lists MCP servers
+func runList(client *clientOptions, args []string) error {
+	fs := newFlagSet("list", "list [flags]", client)
+	allNamespaces := fs.Bool("all-namespaces", false, "list servers in all namespaces")
+	fs.BoolVar(allNamespaces, "A", false, "shorthand for --all-namespaces")
+	selector := fs.String("selector", "", "label selector, e.g. team=platform")
+	fs.StringVar(selector, "l", "", "shorthand for --selector")
+	image := fs.String("image", "", "only list servers running this image")
+	availability := fs.String("availability", "any", "any, available or unavailable")
+	sortBy := fs.String("sort-by", "name", "name, age or image")
+	output := fs.String("o", "table", "output format: "+outputFormats)
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	opts := deployer.ListOptions{LabelSelector: *selector, Image: *image}
+	switch *availability {
+	case "any":
+	case "available", "unavailable":
+		available := *availability == "available"
+		opts.Available = &available
+	default:
+		return &usageError{msg: fmt.Sprintf("invalid availability %q: must be any, available or unavailable", *availability)}
+	}
+	switch *sortBy {
+	case "name":
+		opts.SortBy = deployer.SortByName
+	case "age":
+		opts.SortBy = deployer.SortByCreationTimestamp
+	case "image":
+		opts.SortBy = deployer.SortByImage
+	default:
+		return &usageError{msg: fmt.Sprintf("invalid sort field %q: must be name, age or image", *sortBy)}
+	}
+	mcpDeployer, namespace, err := client.connect()
+	if err != nil {
+		return err
+	}
+	if !*allNamespaces {
+		opts.Namespace = namespace
+	}
+
+	// Everything is listed in one call so that the whole list is sorted
+	list, err := mcpDeployer.ListMCPServers(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+
+	// Tables go to a terminal, so an empty one is explained on stderr
+	if len(list.Items) == 0 && (format.name == "table" || format.name == "wide") {
+		if *allNamespaces {
+			fmt.Fprintln(os.Stderr, "No MCP servers found")
+		} else {
+			fmt.Fprintf(os.Stderr, "No MCP servers found in namespace '%s'\n", namespace)
+		}
+		return nil
+	}
+	return writeServers(os.Stdout, format, list, list.Items, *allNamespaces)
+}
+
+// runGet shows a single MCP server
+func runGet(client *clientOptions, args []string) error {
+	fs := newFlagSet("get", "get NAME [flags]", client)
+	output := fs.String("o", "table", "output format: "+outputFormats)
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	mcpDeployer, namespace, err := client.connect()
+	if err != nil {
+		return err
+	}
+	server, err := mcpDeployer.GetMCPServer(context.Background(), namespace, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return writeServers(os.Stdout, format, server, []deployer.MCPServerStatus{server}, false)
+}
+
+// runDescribe shows the full status of a single MCP server
+func runDescribe(client *clientOptions, args []string) error {
+	fs := newFlagSet("describe", "describe NAME [flags]", client)
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	mcpDeployer, namespace, err := client.connect()
+	if err != nil {
+		return err
+	}
+	server, err := mcpDeployer.GetMCPServer(context.Background(), namespace, positional[0])
+	if err != nil {
+		return err
+	}
+
+	printServer(server)
+	return nil
+}
+
+// runDeploy creates or updates the MCP servers in a spec file
+func runDeploy(client *clientOptions, args []string) error {
+	fs := newFlagSet("deploy", "deploy -f FILE [flags]", client)
+	file := fs.String("f", "", "spec file to deploy, or - for stdin")
+	dryRun := fs.Bool("dry-run", false, "check each server against the cluster without changing anything")
+	wait := fs.Bool("wait", false, "wait for each server to become ready")
+	timeout := fs.Duration("timeout", readyTimeout, "how long to wait for each server with --wait")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	if *file == "" {
+		return &usageError{msg: "a spec file is required: deploy -f FILE"}
+	}
+
+	mcpDeployer, namespace, err := client.connect()
+	if err != nil {
+		return err
+	}
+
+	var specs []*deployer.MCPServerSpec
+	if *file == "-" {
+		specs, err = deployer.LoadSpecs(os.Stdin, namespace)
+	} else {
+		specs, err = deployer.LoadSpecFile(*file, namespace)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Keep going after a failure so that one bad server does not block the rest
+	ctx := context.Background()
+	failed := 0
+	for _, spec := range specs {
+		ref := fmt.Sprintf("%s/%s", spec.Namespace, spec.Name)
+		if err := deploySpec(ctx, mcpDeployer, spec, *dryRun, *wait, *timeout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", ref, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d MCP servers failed", failed, len(specs))
+	}
+	return nil
+}
+
+// deploySpec applies, or dry runs, a single spec and reports the result
+func deploySpec(ctx context.Context, mcpDeployer *deployer.SimpleDeployer, spec *deployer.MCPServerSpec, dryRun, wait bool, timeout time.Duration) error {
+	ref := fmt.Sprintf("%s/%s", spec.Namespace, spec.Name)
+
+	_, err := mcpDeployer.GetMCPServer(ctx, spec.Namespace, spec.Name)
+	if err != nil && !errors.Is(err, deployer.ErrServerNotFound) {
+		return err
+	}
+	existed := err == nil
+
+	// A dry run goes through the same apply, so it previews updates too
+	apply, suffix := mcpDeployer.ApplyMCPServer, ""
+	if dryRun {
+		apply, suffix = mcpDeployer.DryRunApplyMCPServer, " (dry run)"
+	}
+	changed, err := apply(ctx, spec)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !existed:
+		fmt.Printf("mcpserver %s created%s\n", ref, suffix)
+	case changed:
+		fmt.Printf("mcpserver %s configured%s\n", ref, suffix)
+	default:
+		fmt.Printf("mcpserver %s unchanged%s\n", ref, suffix)
+	}
+
+	if !wait || dryRun {
+		return nil
+	}
+	if err := mcpDeployer.WaitForMCPServerReady(ctx, spec.Namespace, spec.Name, deployer.WaitOptions{Timeout: timeout}); err != nil {
+		return err
+	}
+	fmt.Printf("mcpserver %s ready\n", ref)
+	return nil
+}
+
+// runScale sets the number of replicas of an MCP server
+func runScale(client *clientOptions, args []string) error {
+	fs := newFlagSet("scale", "scale NAME --replicas N [flags]", client)
+	replicas := fs.Int("replicas", -1, "number of replicas")
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *replicas < 0 {
+		return &usageError{msg: "the number of replicas is required: scale NAME --replicas N"}
+	}
+
+	mcpDeployer, namespace, err := client.connect()
+	if err != nil {
+		return err
+	}
+	if err := mcpDeployer.ScaleMCPServer(context.Background(), namespace, positional[0], int32(*replicas)); err != nil {
+		return err
+	}
+
+	fmt.Printf("mcpserver %s/%s scaled to %d replicas\n", namespace, positional[0], *replicas)
+	return nil
+}
+
+// runDelete deletes an MCP server, asking for confirmation unless --yes is set
+func runDelete(client *clientOptions, args []string) error {
+	fs := newFlagSet("delete", "delete NAME [flags]", client)
+	yes := fs.Bool("yes", false, "delete without asking for confirmation")
+	fs.BoolVar(yes, "y", false, "shorthand for --yes")
+	wait := fs.Bool("wait", false, "wait for the pods to be removed before returning")
+	timeout := fs.Duration("timeout", readyTimeout, "how long to wait with --wait")
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	name := positional[0]
+	if !*yes && !isTerminal(os.Stdin) {
+		return &usageError{msg: "refusing to delete without confirmation; pass --yes"}
+	}
+
+	mcpDeployer, namespace, err := client.connect()
+	if err != nil {
+		return err
+	}
+
+	if !*yes {
+		fmt.Printf("Delete MCP server '%s' in namespace '%s'? (yes/no): ", name, namespace)
+		confirm, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		confirm = strings.ToLower(strings.TrimSpace(confirm))
+		if confirm != "yes" && confirm != "y" {
+			return errors.New("deletion cancelled")
+		}
+	}
+
+	opts := deployer.DeleteOptions{PropagationPolicy: metav1.DeletePropagationBackground}
+	if *wait {
+		opts.PropagationPolicy = metav1.DeletePropagationForeground
+	}
+	if err := mcpDeployer.DeleteMCPServer(context.Background(), namespace, name, opts); err != nil {
+		return err
+	}
+	if *wait {
+		if err := waitForDeletion(context.Background(), mcpDeployer, namespace, name, *timeout); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("mcpserver %s/%s deleted\n", namespace, name)
+	return nil
+}
+
+// waitForDeletion polls until the MCP server no longer exists. Foreground
+// deletion keeps the Deployment until its pods are gone.
+func waitForDeletion(ctx context.Context, mcpDeployer *deployer.SimpleDeployer, namespace, name string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		_, err := mcpDeployer.GetMCPServer(ctx, namespace, name)
+		if errors.Is(err, deployer.ErrServerNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return errors.New("timed out; the server is still being deleted")
+		}
+		time.Sleep(time.Second)
+	}
+}
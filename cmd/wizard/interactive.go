@@ -0,0 +1,1426 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grs/mcp-deployment/pkg/deployer"
+	"github.com/grs/mcp-deployment/pkg/kubeclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// session is the state of the interactive menu: the cluster it is connected
+// to and the namespace prompts default to
+type session struct {
+	client    *clientOptions
+	deployer  *deployer.SimpleDeployer
+	config    *kubeclient.Config
+	namespace string
+}
+
+// newSession connects to the cluster selected by the client options
+func newSession(client *clientOptions) (*session, error) {
+	config, mcpDeployer, err := client.load()
+	if err != nil {
+		return nil, err
+	}
+	return &session{client: client, deployer: mcpDeployer, config: config, namespace: config.Namespace}, nil
+}
+
+// readLine reads a line of input without surrounding whitespace. It returns
+// false once input has ended or cannot be read; a last line without a newline
+// is still returned.
+func readLine(reader *bufio.Reader) (string, bool) {
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", false
+	}
+	return strings.TrimSpace(line), true
+}
+
+// readNamespace prompts for a namespace, defaulting to the session namespace.
+// It returns false once input has ended.
+func (s *session) readNamespace(reader *bufio.Reader, prompt string) (string, bool) {
+	fmt.Print(prompt)
+	namespace, ok := readLine(reader)
+	if !ok {
+		return "", false
+	}
+	if namespace == "" {
+		namespace = s.namespace
+	}
+	return namespace, true
+}
+
+// runInteractive runs the interactive menu until the user exits or input ends
+func runInteractive(s *session) {
+	// Main menu
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Println("\n=== MCP Server Deployment Wizard ===")
+		if s.config.InCluster {
+			fmt.Printf("Context: (in-cluster)  Namespace: %s\n\n", s.namespace)
+		} else {
+			fmt.Printf("Context: %s  Namespace: %s\n\n", s.config.Context, s.namespace)
+		}
+		fmt.Println("1. List MCP servers")
+		fmt.Println("2. Deploy new MCP server")
+		fmt.Println("3. Delete MCP server")
+		fmt.Println("4. Describe MCP server")
+		fmt.Println("5. Scale MCP server")
+		fmt.Println("6. Switch context")
+		fmt.Println("7. Set default namespace")
+		fmt.Println("8. Exit")
+		fmt.Print("\nSelect an option: ")
+
+		choice, ok := readLine(reader)
+		if !ok {
+			fmt.Println()
+			return
+		}
+
+		switch choice {
+		case "1":
+			listServers(s, reader)
+		case "2":
+			deployServer(s, reader)
+		case "3":
+			deleteServer(s, reader)
+		case "4":
+			describeServer(s, reader)
+		case "5":
+			scaleServer(s, reader)
+		case "6":
+			switchContext(s, reader)
+		case "7":
+			setNamespace(s, reader)
+		case "8":
+			fmt.Println("Goodbye!")
+			return
+		default:
+			fmt.Println("Invalid option. Please try again.")
+		}
+	}
+}
+
+// switchContext connects to another context of the kubeconfig. The default
+// namespace becomes the one of the new context.
+func switchContext(s *session, reader *bufio.Reader) {
+	if s.config.InCluster {
+		fmt.Println("\nRunning in a cluster without a kubeconfig; there are no contexts to switch to")
+		return
+	}
+	contexts, current, err := kubeclient.Contexts(kubeclient.Options{Kubeconfig: s.client.kubeconfig, Context: s.config.Context})
+	if err != nil {
+		fmt.Printf("Error reading contexts: %v\n", err)
+		return
+	}
+
+	fmt.Println("\nAvailable contexts:")
+	for i, name := range contexts {
+		marker := " "
+		if name == current {
+			marker = "*"
+		}
+		fmt.Printf("  %s %d. %s\n", marker, i+1, name)
+	}
+	fmt.Print("\nSelect a context by number or name (leave empty to keep the current one): ")
+	choice, ok := readLine(reader)
+	if !ok {
+		return
+	}
+	if choice == "" {
+		return
+	}
+	name := choice
+	if i, err := strconv.Atoi(choice); err == nil {
+		if i < 1 || i > len(contexts) {
+			fmt.Println("Error: Invalid context number")
+			return
+		}
+		name = contexts[i-1]
+	}
+
+	config, err := kubeclient.Load(kubeclient.Options{Kubeconfig: s.client.kubeconfig, Context: name})
+	if err != nil {
+		fmt.Printf("Error switching context: %v\n", err)
+		return
+	}
+	mcpDeployer, err := newDeployer(config)
+	if err != nil {
+		fmt.Printf("Error switching context: %v\n", err)
+		return
+	}
+
+	s.config, s.deployer, s.namespace = config, mcpDeployer, config.Namespace
+	fmt.Printf("\n✓ Switched to context '%s', namespace '%s'\n", config.Context, config.Namespace)
+}
+
+// setNamespace changes the namespace that prompts default to
+func setNamespace(s *session, reader *bufio.Reader) {
+	fmt.Printf("\nEnter default namespace (%s): ", s.namespace)
+	namespace, ok := readLine(reader)
+	if !ok {
+		return
+	}
+	if namespace == "" {
+		return
+	}
+	if msgs := validation.IsDNS1123Label(namespace); len(msgs) > 0 {
+		fmt.Printf("Error: Invalid namespace: %s\n", strings.Join(msgs, "; "))
+		return
+	}
+
+	s.namespace = namespace
+	fmt.Printf("\n✓ Default namespace set to '%s'\n", namespace)
+}
+
+func listServers(s *session, reader *bufio.Reader) {
+	namespace, ok := s.readNamespace(reader, fmt.Sprintf("\nEnter namespace (%s, 'all' for all namespaces): ", s.namespace))
+	if !ok {
+		return
+	}
+
+	opts := deployer.ListOptions{Namespace: namespace, Limit: listPageSize}
+	scope := fmt.Sprintf("namespace '%s'", namespace)
+	if namespace == "all" {
+		opts.Namespace = ""
+		scope = "all namespaces"
+	}
+
+	fmt.Print("Filter by label selector, e.g. team=platform (optional): ")
+	if opts.LabelSelector, ok = readLine(reader); !ok {
+		return
+	}
+
+	fmt.Print("Filter by availability (any/available/unavailable, default any): ")
+	availability, ok := readLine(reader)
+	if !ok {
+		return
+	}
+	switch strings.ToLower(availability) {
+	case "", "any":
+	case "available":
+		available := true
+		opts.Available = &available
+	case "unavailable":
+		available := false
+		opts.Available = &available
+	default:
+		fmt.Println("Error: Availability must be any, available or unavailable")
+		return
+	}
+
+	fmt.Print("Filter by image (optional): ")
+	if opts.Image, ok = readLine(reader); !ok {
+		return
+	}
+
+	fmt.Print("Sort by (name/age/image, default name): ")
+	sortBy, ok := readLine(reader)
+	if !ok {
+		return
+	}
+	switch strings.ToLower(sortBy) {
+	case "", "name":
+		opts.SortBy = deployer.SortByName
+	case "age":
+		opts.SortBy = deployer.SortByCreationTimestamp
+	case "image":
+		opts.SortBy = deployer.SortByImage
+	default:
+		fmt.Println("Error: Sort must be name, age or image")
+		return
+	}
+	// Pages come in name order, so other orders need the whole list
+	if opts.SortBy != deployer.SortByName {
+		opts.Limit = 0
+	}
+
+	count := 0
+	for {
+		servers, err := s.deployer.ListMCPServers(context.Background(), opts)
+		if err != nil {
+			fmt.Printf("Error listing servers: %v\n", err)
+			return
+		}
+
+		if len(servers.Items) > 0 {
+			if count == 0 {
+				fmt.Printf("\n=== MCP Servers in %s ===\n\n", scope)
+			}
+			if err := writeTable(os.Stdout, servers.Items, opts.Namespace == "", false); err != nil {
+				fmt.Printf("Error printing servers: %v\n", err)
+				return
+			}
+			fmt.Println()
+			count += len(servers.Items)
+		}
+
+		if servers.Continue == "" {
+			break
+		}
+		fmt.Print("Show more? (yes/no): ")
+		more, ok := readLine(reader)
+		if !ok {
+			return
+		}
+		more = strings.ToLower(more)
+		if more != "yes" && more != "y" {
+			return
+		}
+		opts.Continue = servers.Continue
+	}
+
+	if count == 0 {
+		fmt.Printf("\nNo MCP servers found in %s\n", scope)
+	}
+}
+
+func describeServer(s *session, reader *bufio.Reader) {
+	namespace, ok := s.readNamespace(reader, fmt.Sprintf("\nEnter namespace (%s): ", s.namespace))
+	if !ok {
+		return
+	}
+
+	fmt.Print("Enter MCP server name: ")
+	name, ok := readLine(reader)
+	if !ok {
+		return
+	}
+	if name == "" {
+		fmt.Println("Error: Name is required")
+		return
+	}
+
+	server, err := s.deployer.GetMCPServer(context.Background(), namespace, name)
+	if errors.Is(err, deployer.ErrServerNotFound) {
+		fmt.Printf("\nNo MCP server '%s' found in namespace '%s'\n", name, namespace)
+		return
+	}
+	if err != nil {
+		fmt.Printf("Error getting server: %v\n", err)
+		return
+	}
+
+	fmt.Printf("\n=== MCP Server '%s' ===\n\n", name)
+	printServer(server)
+}
+
+func printServer(server deployer.MCPServerStatus) {
+	fmt.Printf("  Name:      %s\n", server.Name)
+	fmt.Printf("  Namespace: %s\n", server.Namespace)
+	fmt.Printf("  Image:     %s\n", server.Image)
+	if server.Package != "" {
+		fmt.Printf("  Package:   %s\n", server.Package)
+	}
+	fmt.Printf("  Phase:     %s\n", server.Phase)
+	fmt.Printf("  Replicas:  %d/%d ready, %d updated, %d available\n",
+		server.ReadyReplicas, server.DesiredReplicas, server.UpdatedReplicas, server.AvailableReplicas)
+	fmt.Printf("  Transport: %s\n", server.Transport)
+	fmt.Printf("  Endpoint:  %s\n", server.Endpoint)
+	fmt.Printf("  Created:   %s\n", server.CreationTimestamp.Format(time.RFC3339))
+	if server.RestartCount > 0 {
+		fmt.Printf("  Restarts:  %d (last termination: %s)\n", server.RestartCount, server.LastTerminationReason)
+	}
+
+	if len(server.Labels) > 0 {
+		fmt.Println("  Labels:")
+		for k, v := range server.Labels {
+			fmt.Printf("    %s: %s\n", k, v)
+		}
+	}
+
+	if len(server.Annotations) > 0 {
+		fmt.Println("  Annotations:")
+		for k, v := range server.Annotations {
+			fmt.Printf("    %s: %s\n", k, v)
+		}
+	}
+
+	if len(server.Conditions) > 0 {
+		fmt.Println("  Conditions:")
+		for _, condition := range server.Conditions {
+			fmt.Printf("    - %s: %s (%s) - %s\n", condition.Type, condition.Status, condition.Reason, condition.Message)
+		}
+	}
+}
+
+func deployServer(s *session, reader *bufio.Reader) {
+	fmt.Print("\n=== Deploy New MCP Server ===\n\n")
+
+	spec := &deployer.MCPServerSpec{
+		Labels:      make(map[string]string),
+		Annotations: make(map[string]string),
+	}
+
+	// Name
+	if !promptForField(reader, spec, "Enter MCP server name: ", "name", func(value string) error {
+		spec.Name = value
+		return nil
+	}) {
+		return
+	}
+
+	// Namespace
+	if !promptForField(reader, spec, fmt.Sprintf("Enter namespace (%s): ", s.namespace), "namespace", func(value string) error {
+		spec.Namespace = value
+		if spec.Namespace == "" {
+			spec.Namespace = s.namespace
+		}
+		return nil
+	}) {
+		return
+	}
+
+	// Image, or a package run in a generic runner image
+	fmt.Print("Deploy from image or package? (image/package, default image): ")
+	source, ok := readLine(reader)
+	if !ok {
+		return
+	}
+	if strings.ToLower(source) == "package" {
+		if !promptForPackage(reader, spec) {
+			return
+		}
+	} else if !promptForField(reader, spec, "Enter container image: ", "image", func(value string) error {
+		spec.Image = value
+		return nil
+	}) {
+		return
+	}
+
+	// Port
+	if !promptForField(reader, spec, "Enter port number (8080): ", "port", func(value string) error {
+		if value == "" {
+			spec.Port = 8080
+			return nil
+		}
+		port, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid port number: %w", err)
+		}
+		spec.Port = int32(port)
+		return nil
+	}) {
+		return
+	}
+
+	// Transport and endpoint path; packages speak stdio
+	if spec.Package != nil {
+		spec.Transport = deployer.TransportStdio
+	} else if !promptForField(reader, spec, "Enter transport (streamable-http/sse/stdio, default streamable-http): ", "transport", func(value string) error {
+		spec.Transport = deployer.Transport(strings.ToLower(value))
+		return nil
+	}) {
+		return
+	}
+	if !promptForField(reader, spec, fmt.Sprintf("Enter endpoint path (%s): ", deployer.DefaultPath(spec.Transport)), "path", func(value string) error {
+		spec.Path = value
+		return nil
+	}) {
+		return
+	}
+
+	// Stdio servers run behind mcp-bridge, which needs their command unless
+	// they come from a package
+	if spec.Transport == deployer.TransportStdio && !promptForBridge(reader, spec) {
+		return
+	}
+
+	// Command and working directory, so that generic base images can host
+	// any server
+	if spec.Package == nil && spec.Transport != deployer.TransportStdio {
+		if !promptForField(reader, spec, "Enter command to replace the image entrypoint (leave empty to keep it): ", "command", func(value string) error {
+			spec.Command = strings.Fields(value)
+			return nil
+		}) {
+			return
+		}
+	}
+	if !promptForField(reader, spec, "Enter working directory (leave empty for the image default): ", "workingDir", func(value string) error {
+		spec.WorkingDir = value
+		return nil
+	}) {
+		return
+	}
+
+	// Environment Variables
+	if spec.EnvVars, ok = promptForEnvVars(reader); !ok {
+		return
+	}
+
+	// Args
+	if spec.Args, ok = promptForArgs(reader); !ok {
+		return
+	}
+
+	// Secret Mounts
+	if spec.SecretMounts, ok = promptForSecretMounts(reader); !ok {
+		return
+	}
+
+	// Service Account
+	fmt.Print("Enter service account (leave empty for default): ")
+	if spec.ServiceAccount, ok = readLine(reader); !ok {
+		return
+	}
+
+	// Labels
+	if spec.Labels, ok = promptForKeyValuePairs(reader, "label"); !ok {
+		return
+	}
+
+	// Annotations
+	if spec.Annotations, ok = promptForKeyValuePairs(reader, "annotation"); !ok {
+		return
+	}
+
+	// Resource limits and requests
+	if spec.Resources, ok = promptForResources(reader); !ok {
+		return
+	}
+
+	// Replicas, or autoscaling on the resources requested above
+	if !promptForScaling(reader, spec) {
+		return
+	}
+
+	// Session affinity, once there can be more than one replica
+	if !promptForSessionAffinity(reader, spec) {
+		return
+	}
+
+	// Health probes
+	if !promptForProbes(reader, spec) {
+		return
+	}
+
+	// Validate the complete spec before showing the summary
+	if err := spec.Validate(); err != nil {
+		fmt.Println("\nThe MCP server spec is invalid:")
+		printValidationErrors(err)
+		return
+	}
+
+	// Confirm deployment
+	fmt.Println("\n=== Deployment Summary ===")
+	fmt.Printf("Name:           %s\n", spec.Name)
+	fmt.Printf("Namespace:      %s\n", spec.Namespace)
+	if spec.Package != nil {
+		fmt.Printf("Package:        %s\n", spec.Package)
+		fmt.Printf("Package Cache:  %s\n", describePackageCache(spec.Package))
+		if spec.Image != "" {
+			fmt.Printf("Runner Image:   %s\n", spec.Image)
+		}
+	} else {
+		fmt.Printf("Image:          %s\n", spec.Image)
+	}
+	fmt.Printf("Port:           %d\n", spec.Port)
+	fmt.Printf("Transport:      %s\n", describeTransport(spec))
+	if spec.Bridge != nil {
+		fmt.Printf("Bridge:         %s\n", describeSessions(spec.Bridge))
+	}
+	if len(spec.Command) > 0 {
+		fmt.Printf("Command:        %s\n", strings.Join(spec.Command, " "))
+	}
+	if spec.WorkingDir != "" {
+		fmt.Printf("Working Dir:    %s\n", spec.WorkingDir)
+	}
+	fmt.Printf("Service Account: %s\n", spec.ServiceAccount)
+	fmt.Printf("Env Vars:       %d\n", len(spec.EnvVars))
+	fmt.Printf("Args:           %d\n", len(spec.Args))
+	fmt.Printf("Secret Mounts:  %d\n", len(spec.SecretMounts))
+	fmt.Printf("Labels:         %d\n", len(spec.Labels))
+	fmt.Printf("Annotations:    %d\n", len(spec.Annotations))
+	if spec.Resources != nil {
+		fmt.Println("Resources:      configured")
+		if len(spec.Resources.Requests) > 0 {
+			fmt.Printf("  Requests:     %v\n", spec.Resources.Requests)
+		}
+		if len(spec.Resources.Limits) > 0 {
+			fmt.Printf("  Limits:       %v\n", spec.Resources.Limits)
+		}
+	}
+
+	fmt.Printf("Replicas:       %s\n", describeScaling(spec))
+	if spec.SessionAffinity != nil {
+		fmt.Printf("Session Affinity: %s\n", describeSessionAffinity(spec.SessionAffinity))
+	}
+	fmt.Printf("Probes:         %s\n", describeProbes(spec))
+
+	if !confirmDeployment(s.deployer, reader, spec) {
+		return
+	}
+
+	// Deploy
+	err := s.deployer.DeployMCPServer(context.Background(), spec)
+	if err != nil {
+		fmt.Printf("Error deploying server: %v\n", err)
+		return
+	}
+
+	fmt.Printf("\n✓ MCP server '%s' deployed in namespace '%s'\n", spec.Name, spec.Namespace)
+
+	// Wait for the rollout, redrawing a single progress line as it advances
+	fmt.Println("Waiting for MCP server to become ready...")
+	err = s.deployer.WaitForMCPServerReady(context.Background(), spec.Namespace, spec.Name, deployer.WaitOptions{
+		Timeout: readyTimeout,
+		OnProgress: func(event deployer.ProgressEvent) {
+			fmt.Printf("\r\033[K  [%d/%d ready] %s", event.AvailableReplicas, event.DesiredReplicas, event.Message)
+		},
+	})
+	fmt.Println()
+	if err != nil {
+		fmt.Printf("\n✗ MCP server '%s' is not ready: %v\n", spec.Name, err)
+		return
+	}
+
+	fmt.Printf("\n✓ MCP server '%s' is ready!\n", spec.Name)
+}
+
+// confirmDeployment offers to deploy the spec, show or export its manifests,
+// or check it with a server-side dry run. It returns true if the server should
+// be deployed.
+func confirmDeployment(mcpDeployer *deployer.SimpleDeployer, reader *bufio.Reader, spec *deployer.MCPServerSpec) bool {
+	for {
+		fmt.Println("\n1. Deploy")
+		fmt.Println("2. Show manifest")
+		fmt.Println("3. Write manifest to file instead of deploying")
+		fmt.Println("4. Dry run against the cluster")
+		fmt.Println("5. Cancel")
+		fmt.Print("\nSelect an option: ")
+
+		choice, ok := readLine(reader)
+		if !ok {
+			fmt.Println("\nDeployment cancelled.")
+			return false
+		}
+
+		switch choice {
+		case "1":
+			return true
+		case "2":
+			data, err := renderManifests(spec, deployer.ManifestYAML)
+			if err != nil {
+				fmt.Printf("Error rendering manifest: %v\n", err)
+				continue
+			}
+			fmt.Printf("\n%s", data)
+		case "3":
+			if writeManifests(reader, spec) {
+				return false
+			}
+		case "4":
+			if _, err := mcpDeployer.DryRunMCPServer(context.Background(), spec); err != nil {
+				fmt.Println("\n✗ The cluster rejected the MCP server:")
+				printValidationErrors(err)
+				continue
+			}
+			fmt.Println("\n✓ The cluster accepted the MCP server (nothing was created)")
+		case "5":
+			fmt.Println("Deployment cancelled.")
+			return false
+		default:
+			fmt.Println("Invalid option. Please try again.")
+		}
+	}
+}
+
+// writeManifests prompts for a file and writes the manifests of the spec to it,
+// as JSON if the file name ends in .json and YAML otherwise. It returns true
+// if the file was written.
+func writeManifests(reader *bufio.Reader, spec *deployer.MCPServerSpec) bool {
+	defaultPath := spec.Name + ".yaml"
+	fmt.Printf("Enter file name (%s): ", defaultPath)
+	path, ok := readLine(reader)
+	if !ok {
+		return false
+	}
+	if path == "" {
+		path = defaultPath
+	}
+
+	format := deployer.ManifestYAML
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		format = deployer.ManifestJSON
+	}
+	data, err := renderManifests(spec, format)
+	if err != nil {
+		fmt.Printf("Error rendering manifest: %v\n", err)
+		return false
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		fmt.Printf("Error writing manifest: %v\n", err)
+		return false
+	}
+
+	fmt.Printf("\n✓ Manifest for '%s' written to %s\n", spec.Name, path)
+	return true
+}
+
+// renderManifests renders and encodes the manifests of the spec
+func renderManifests(spec *deployer.MCPServerSpec, format deployer.ManifestFormat) ([]byte, error) {
+	objs, err := deployer.RenderManifests(spec)
+	if err != nil {
+		return nil, err
+	}
+	return deployer.EncodeManifests(objs, format)
+}
+
+// promptForField prompts for a value until the spec field it sets passes
+// validation. It returns false if input could not be read.
+func promptForField(reader *bufio.Reader, spec *deployer.MCPServerSpec, prompt, path string, set func(value string) error) bool {
+	for {
+		fmt.Print(prompt)
+		value, ok := readLine(reader)
+		if !ok {
+			fmt.Println("\nError: no input")
+			return false
+		}
+
+		if err := set(value); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			continue
+		}
+
+		fieldErrs := fieldErrors(spec, path)
+		if len(fieldErrs) == 0 {
+			return true
+		}
+		for _, fieldErr := range fieldErrs {
+			fmt.Printf("Error: %s\n", fieldErr.ErrorBody())
+		}
+	}
+}
+
+// fieldErrors returns the validation errors of spec for a single field
+func fieldErrors(spec *deployer.MCPServerSpec, path string) field.ErrorList {
+	var validationErr *deployer.ValidationError
+	if !errors.As(spec.Validate(), &validationErr) {
+		return nil
+	}
+
+	var fieldErrs field.ErrorList
+	for _, fieldErr := range validationErr.Errors {
+		if fieldErr.Field == path {
+			fieldErrs = append(fieldErrs, fieldErr)
+		}
+	}
+	return fieldErrs
+}
+
+// printValidationErrors prints each problem of a validation error on its own line
+func printValidationErrors(err error) {
+	var validationErr *deployer.ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Errors) == 0 {
+		fmt.Printf("  - %v\n", err)
+		return
+	}
+	for _, fieldErr := range validationErr.Errors {
+		fmt.Printf("  - %v\n", fieldErr)
+	}
+}
+
+func deleteServer(s *session, reader *bufio.Reader) {
+	fmt.Print("\n=== Delete MCP Server ===\n\n")
+
+	// Namespace
+	namespace, ok := s.readNamespace(reader, fmt.Sprintf("Enter namespace (%s): ", s.namespace))
+	if !ok {
+		return
+	}
+
+	// List servers first to help user choose
+	servers, err := s.deployer.ListMCPServers(context.Background(), deployer.ListOptions{Namespace: namespace})
+	if err != nil {
+		fmt.Printf("Error listing servers: %v\n", err)
+		return
+	}
+
+	if len(servers.Items) == 0 {
+		fmt.Printf("\nNo MCP servers found in namespace '%s'\n", namespace)
+		return
+	}
+
+	fmt.Printf("\nAvailable MCP servers in namespace '%s':\n", namespace)
+	for i, server := range servers.Items {
+		status := "unavailable"
+		if server.Available {
+			status = "available"
+		}
+		fmt.Printf("  %d. %s (%s) - %s\n", i+1, server.Name, server.Image, status)
+	}
+
+	// Name
+	fmt.Print("\nEnter MCP server name to delete: ")
+	name, ok := readLine(reader)
+	if !ok {
+		return
+	}
+	if name == "" {
+		fmt.Println("Error: Name is required")
+		return
+	}
+
+	// Confirm deletion
+	fmt.Printf("\n⚠️  WARNING: This will permanently delete the MCP server '%s' in namespace '%s'\n", name, namespace)
+	fmt.Printf("This includes the Deployment and Service resources.\n")
+	fmt.Print("\nAre you sure you want to proceed? (yes/no): ")
+	confirm, ok := readLine(reader)
+	if !ok {
+		return
+	}
+	confirm = strings.ToLower(confirm)
+
+	if confirm != "yes" && confirm != "y" {
+		fmt.Println("Deletion cancelled.")
+		return
+	}
+
+	// Propagation
+	opts := deployer.DeleteOptions{PropagationPolicy: metav1.DeletePropagationBackground}
+	fmt.Print("Wait for the pods to be removed before returning? (yes/no, default no): ")
+	wait, ok := readLine(reader)
+	if !ok {
+		return
+	}
+	wait = strings.ToLower(wait)
+	if wait == "yes" || wait == "y" {
+		opts.PropagationPolicy = metav1.DeletePropagationForeground
+	}
+
+	// Delete
+	err = s.deployer.DeleteMCPServer(context.Background(), namespace, name, opts)
+	if err != nil {
+		fmt.Printf("Error deleting server: %v\n", err)
+		return
+	}
+
+	// Foreground deletion keeps the Deployment until its pods are gone
+	if opts.PropagationPolicy == metav1.DeletePropagationForeground {
+		fmt.Print("Waiting for the pods to be removed...")
+		if err := waitForDeletion(context.Background(), s.deployer, namespace, name, readyTimeout); err != nil {
+			fmt.Printf(" %v\n", err)
+			return
+		}
+		fmt.Println(" done")
+	}
+
+	fmt.Printf("\n✓ MCP server '%s' deleted successfully from namespace '%s'!\n", name, namespace)
+}
+
+// scaleServer sets the number of replicas of an MCP server
+func scaleServer(s *session, reader *bufio.Reader) {
+	namespace, ok := s.readNamespace(reader, fmt.Sprintf("\nEnter namespace (%s): ", s.namespace))
+	if !ok {
+		return
+	}
+
+	fmt.Print("Enter MCP server name: ")
+	name, ok := readLine(reader)
+	if !ok {
+		return
+	}
+	if name == "" {
+		fmt.Println("Error: Name is required")
+		return
+	}
+
+	fmt.Print("Enter number of replicas: ")
+	value, ok := readLine(reader)
+	if !ok {
+		return
+	}
+	replicas, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		fmt.Printf("Error: invalid number of replicas: %v\n", err)
+		return
+	}
+
+	err = s.deployer.ScaleMCPServer(context.Background(), namespace, name, int32(replicas))
+	switch {
+	case errors.Is(err, deployer.ErrServerNotFound):
+		fmt.Printf("\nNo MCP server '%s' found in namespace '%s'\n", name, namespace)
+		return
+	case errors.Is(err, deployer.ErrAutoscaled):
+		fmt.Printf("\nMCP server '%s' is autoscaled; change its autoscaling bounds instead\n", name)
+		return
+	case err != nil:
+		fmt.Printf("Error scaling server: %v\n", err)
+		return
+	}
+
+	fmt.Printf("\n✓ MCP server '%s' scaled to %d replicas\n", name, replicas)
+}
+
+// promptForScaling asks for a fixed number of replicas, or for the bounds and
+// targets of autoscaling. The targets are percentages of the resources the
+// pods request, so only requested resources are accepted.
+func promptForScaling(reader *bufio.Reader, spec *deployer.MCPServerSpec) bool {
+	fmt.Print("Autoscale with a HorizontalPodAutoscaler? (yes/no, default no): ")
+	response, ok := readLine(reader)
+	if !ok {
+		return false
+	}
+	response = strings.ToLower(response)
+	if response != "yes" && response != "y" {
+		return promptForField(reader, spec, "Enter number of replicas (1): ", "replicas", func(value string) error {
+			spec.Replicas = nil
+			if value == "" {
+				return nil
+			}
+			var replicas int32
+			if err := parseInt32(value, &replicas); err != nil {
+				return fmt.Errorf("invalid number of replicas: %w", err)
+			}
+			spec.Replicas = &replicas
+			return nil
+		})
+	}
+
+	autoscaling := &deployer.Autoscaling{}
+	spec.Autoscaling = autoscaling
+	if !promptForField(reader, spec, "Enter minimum replicas (1): ", "autoscaling.min", func(value string) error {
+		return parseInt32(value, &autoscaling.Min)
+	}) {
+		return false
+	}
+	if !promptForField(reader, spec, "Enter maximum replicas: ", "autoscaling.max", func(value string) error {
+		return parseInt32(value, &autoscaling.Max)
+	}) {
+		return false
+	}
+
+	targets := []struct {
+		prompt  string
+		path    string
+		request string
+		value   *int32
+	}{
+		{"Enter target CPU utilization in percent of the request (leave empty to not scale on CPU): ", "autoscaling.targetCPU", "resources.requests[cpu]", &autoscaling.TargetCPU},
+		{"Enter target memory utilization in percent of the request (leave empty to not scale on memory): ", "autoscaling.targetMemory", "resources.requests[memory]", &autoscaling.TargetMemory},
+	}
+	for {
+		for _, target := range targets {
+			if !promptForField(reader, spec, target.prompt, target.path, func(value string) error {
+				if err := parseInt32(value, target.value); err != nil {
+					return err
+				}
+				if requestErrs := fieldErrors(spec, target.request); len(requestErrs) > 0 {
+					*target.value = 0
+					return errors.New(requestErrs[0].ErrorBody())
+				}
+				return nil
+			}) {
+				return false
+			}
+		}
+
+		targetErrs := fieldErrors(spec, "autoscaling")
+		if len(targetErrs) == 0 {
+			return true
+		}
+		for _, targetErr := range targetErrs {
+			fmt.Printf("Error: %s\n", targetErr.ErrorBody())
+		}
+	}
+}
+
+// parseInt32 parses a number entered at a prompt, leaving zero for empty input
+func parseInt32(value string, out *int32) error {
+	*out = 0
+	if value == "" {
+		return nil
+	}
+	n, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return err
+	}
+	*out = int32(n)
+	return nil
+}
+
+// describeScaling summarizes the replicas or autoscaling of a spec
+func describeScaling(spec *deployer.MCPServerSpec) string {
+	autoscaling := spec.Autoscaling
+	if autoscaling == nil {
+		replicas := int32(1)
+		if spec.Replicas != nil {
+			replicas = *spec.Replicas
+		}
+		return strconv.Itoa(int(replicas))
+	}
+
+	minReplicas := autoscaling.Min
+	if minReplicas == 0 {
+		minReplicas = 1
+	}
+	description := fmt.Sprintf("autoscaled %d-%d", minReplicas, autoscaling.Max)
+	if autoscaling.TargetCPU > 0 {
+		description += fmt.Sprintf(", CPU %d%%", autoscaling.TargetCPU)
+	}
+	if autoscaling.TargetMemory > 0 {
+		description += fmt.Sprintf(", memory %d%%", autoscaling.TargetMemory)
+	}
+	return description
+}
+
+// promptForSessionAffinity asks how sessions stay on one replica, unless the
+// server runs a single replica. The router needs a port of its own and a
+// name short enough for its Service, so those are checked with the mode.
+func promptForSessionAffinity(reader *bufio.Reader, spec *deployer.MCPServerSpec) bool {
+	spec.SessionAffinity = nil
+	if spec.Autoscaling == nil && (spec.Replicas == nil || *spec.Replicas <= 1) {
+		return true
+	}
+
+	affinity := &deployer.SessionAffinity{}
+	if !promptForField(reader, spec, "Session affinity across replicas (none/client-ip/router, default none): ", "sessionAffinity.mode", func(value string) error {
+		affinity.Mode = deployer.SessionAffinityMode(strings.ToLower(value))
+		if affinity.Mode == "" || affinity.Mode == deployer.AffinityNone {
+			spec.SessionAffinity = nil
+			return nil
+		}
+		spec.SessionAffinity = affinity
+		for _, path := range []string{"port", "name"} {
+			if routerErrs := fieldErrors(spec, path); len(routerErrs) > 0 {
+				spec.SessionAffinity = nil
+				return fmt.Errorf("%s %s", path, routerErrs[0].ErrorBody())
+			}
+		}
+		return nil
+	}) {
+		return false
+	}
+
+	if affinity.Mode != deployer.AffinityClientIP {
+		return true
+	}
+	return promptForField(reader, spec, "Enter session affinity timeout in seconds (10800): ", "sessionAffinity.timeoutSeconds", func(value string) error {
+		return parseInt32(value, &affinity.TimeoutSeconds)
+	})
+}
+
+// describeSessionAffinity summarizes session affinity, with the defaults
+// applied
+func describeSessionAffinity(affinity *deployer.SessionAffinity) string {
+	switch affinity.Mode {
+	case deployer.AffinityClientIP:
+		timeout := affinity.TimeoutSeconds
+		if timeout == 0 {
+			timeout = 10800
+		}
+		return fmt.Sprintf("client-ip, %ds timeout", timeout)
+	case deployer.AffinityRouter:
+		return "router on Mcp-Session-Id"
+	}
+	return string(affinity.Mode)
+}
+
+// promptForProbes asks for the type of the readiness, liveness and startup
+// probes. MCP probes check the endpoint path of the server.
+func promptForProbes(reader *bufio.Reader, spec *deployer.MCPServerSpec) bool {
+	var probe deployer.Probe
+	if !promptForField(reader, spec, "Health probe type (tcp/mcp/none, default tcp): ", "readinessProbe.type", func(value string) error {
+		probe.Type = deployer.ProbeType(strings.ToLower(value))
+		if probe.Type == "" || probe.Type == deployer.ProbeTCP {
+			// Nil probes are TCP probes with the defaults
+			spec.ReadinessProbe, spec.LivenessProbe, spec.StartupProbe = nil, nil, nil
+			return nil
+		}
+		spec.ReadinessProbe, spec.LivenessProbe, spec.StartupProbe = &probe, &probe, &probe
+		return nil
+	}) {
+		return false
+	}
+	return true
+}
+
+// promptForPackage asks for the runtime, name and version of a packaged server
+// and where to cache it
+func promptForPackage(reader *bufio.Reader, spec *deployer.MCPServerSpec) bool {
+	spec.Package = &deployer.Package{}
+	if !promptForField(reader, spec, "Enter package runtime (npx/uvx): ", "package.runtime", func(value string) error {
+		spec.Package.Runtime = deployer.PackageRuntime(strings.ToLower(value))
+		return nil
+	}) {
+		return false
+	}
+	if !promptForField(reader, spec, "Enter package name: ", "package.name", func(value string) error {
+		spec.Package.Name = value
+		return nil
+	}) {
+		return false
+	}
+	if !promptForField(reader, spec, "Enter package version (latest): ", "package.version", func(value string) error {
+		spec.Package.Version = value
+		return nil
+	}) {
+		return false
+	}
+	return promptForField(reader, spec, "Enter a PersistentVolumeClaim to cache packages in (leave empty for an emptyDir): ", "package.cacheClaim", func(value string) error {
+		spec.Package.CacheClaim = value
+		return nil
+	})
+}
+
+// describePackageCache returns where the packages of a server are cached
+func describePackageCache(pkg *deployer.Package) string {
+	if pkg.CacheClaim == "" {
+		return "emptyDir"
+	}
+	return "claim " + pkg.CacheClaim
+}
+
+// promptForBridge asks for the command of a stdio server, unless it comes from
+// a package, and how the bridge maps client sessions to server processes
+func promptForBridge(reader *bufio.Reader, spec *deployer.MCPServerSpec) bool {
+	spec.Bridge = &deployer.StdioBridge{}
+	if spec.Package == nil && !promptForField(reader, spec, "Enter the command of the stdio server (e.g. npx -y @modelcontextprotocol/server-everything): ", "command", func(value string) error {
+		spec.Command = strings.Fields(value)
+		return nil
+	}) {
+		return false
+	}
+	return promptForField(reader, spec, "Server process per client session or shared (per-session/shared, default per-session): ", "bridge.sessions", func(value string) error {
+		spec.Bridge.Sessions = deployer.BridgeSessionMode(strings.ToLower(value))
+		return nil
+	})
+}
+
+// describeSessions returns the session mode of a bridge, with the default
+// applied
+func describeSessions(bridge *deployer.StdioBridge) string {
+	sessions := bridge.Sessions
+	if sessions == "" {
+		sessions = deployer.BridgePerSession
+	}
+	return string(sessions) + " sessions"
+}
+
+// describeTransport returns the transport and endpoint path of a spec, with
+// the defaults applied
+func describeTransport(spec *deployer.MCPServerSpec) string {
+	transport := spec.Transport
+	if transport == "" {
+		transport = deployer.TransportStreamableHTTP
+	}
+	path := spec.Path
+	if path == "" {
+		path = deployer.DefaultPath(transport)
+	}
+	return fmt.Sprintf("%s %s", transport, path)
+}
+
+// describeProbes summarizes the probes of a spec for the deployment summary
+func describeProbes(spec *deployer.MCPServerSpec) string {
+	describe := func(probe *deployer.Probe) string {
+		if probe == nil || probe.Type == "" {
+			return string(deployer.ProbeTCP)
+		}
+		return string(probe.Type)
+	}
+	return fmt.Sprintf("readiness %s, liveness %s, startup %s",
+		describe(spec.ReadinessProbe), describe(spec.LivenessProbe), describe(spec.StartupProbe))
+}
+
+// promptForEnvVars asks for environment variables. It returns false once
+// input has ended.
+func promptForEnvVars(reader *bufio.Reader) ([]corev1.EnvVar, bool) {
+	var envVars []corev1.EnvVar
+
+	fmt.Print("\nAdd environment variables? (yes/no): ")
+	response, ok := readLine(reader)
+	if !ok {
+		return nil, false
+	}
+	response = strings.ToLower(response)
+
+	if response != "yes" && response != "y" {
+		return envVars, true
+	}
+
+	fmt.Println("\nEntering environment variables (press Enter with empty name to finish):")
+	for {
+		fmt.Print("\nEnv var name: ")
+		name, ok := readLine(reader)
+		if !ok {
+			return nil, false
+		}
+		if name == "" {
+			break
+		}
+
+		fmt.Print("Is this a simple value or from a secret? (value/secret): ")
+		varType, ok := readLine(reader)
+		if !ok {
+			return nil, false
+		}
+		varType = strings.ToLower(varType)
+
+		var envVar corev1.EnvVar
+		envVar.Name = name
+
+		if varType == "secret" {
+			fmt.Print("Secret name: ")
+			secretName, ok := readLine(reader)
+			if !ok {
+				return nil, false
+			}
+
+			fmt.Print("Secret key: ")
+			secretKey, ok := readLine(reader)
+			if !ok {
+				return nil, false
+			}
+
+			envVar.ValueFrom = &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secretName,
+					},
+					Key: secretKey,
+				},
+			}
+		} else {
+			fmt.Print("Value: ")
+			value, ok := readLine(reader)
+			if !ok {
+				return nil, false
+			}
+			envVar.Value = value
+		}
+
+		envVars = append(envVars, envVar)
+		fmt.Printf("✓ Added environment variable: %s\n", name)
+	}
+
+	return envVars, true
+}
+
+// promptForArgs asks for command-line arguments. It returns false once input
+// has ended.
+func promptForArgs(reader *bufio.Reader) ([]string, bool) {
+	var args []string
+
+	fmt.Print("\nAdd command-line arguments? (yes/no): ")
+	response, ok := readLine(reader)
+	if !ok {
+		return nil, false
+	}
+	response = strings.ToLower(response)
+
+	if response != "yes" && response != "y" {
+		return args, true
+	}
+
+	fmt.Println("\nEntering arguments (press Enter with empty value to finish):")
+	for {
+		fmt.Print("Argument: ")
+		arg, ok := readLine(reader)
+		if !ok {
+			return nil, false
+		}
+		if arg == "" {
+			break
+		}
+		args = append(args, arg)
+		fmt.Printf("✓ Added argument: %s\n", arg)
+	}
+
+	return args, true
+}
+
+// promptForSecretMounts asks for secrets to mount. It returns false once input
+// has ended.
+func promptForSecretMounts(reader *bufio.Reader) ([]deployer.SecretMount, bool) {
+	var secretMounts []deployer.SecretMount
+
+	fmt.Print("\nAdd secret mounts? (yes/no): ")
+	response, ok := readLine(reader)
+	if !ok {
+		return nil, false
+	}
+	response = strings.ToLower(response)
+
+	if response != "yes" && response != "y" {
+		return secretMounts, true
+	}
+
+	fmt.Println("\nEntering secret mounts (press Enter with empty secret name to finish):")
+	for {
+		fmt.Print("\nSecret name: ")
+		secretName, ok := readLine(reader)
+		if !ok {
+			return nil, false
+		}
+		if secretName == "" {
+			break
+		}
+
+		fmt.Print("Mount path: ")
+		mountPath, ok := readLine(reader)
+		if !ok {
+			return nil, false
+		}
+		if mountPath == "" {
+			fmt.Println("Error: Mount path is required")
+			continue
+		}
+
+		secretMounts = append(secretMounts, deployer.SecretMount{
+			SecretName: secretName,
+			MountPath:  mountPath,
+		})
+		fmt.Printf("✓ Added secret mount: %s -> %s\n", secretName, mountPath)
+	}
+
+	return secretMounts, true
+}
+
+// promptForKeyValuePairs asks for labels or annotations. It returns false
+// once input has ended.
+func promptForKeyValuePairs(reader *bufio.Reader, pairType string) (map[string]string, bool) {
+	pairs := make(map[string]string)
+
+	fmt.Printf("\nAdd %ss? (yes/no): ", pairType)
+	response, ok := readLine(reader)
+	if !ok {
+		return nil, false
+	}
+	response = strings.ToLower(response)
+
+	if response != "yes" && response != "y" {
+		return pairs, true
+	}
+
+	fmt.Printf("\nEntering %ss (press Enter with empty key to finish):\n", pairType)
+	for {
+		fmt.Printf("\n%s key: ", strings.Title(pairType))
+		key, ok := readLine(reader)
+		if !ok {
+			return nil, false
+		}
+		if key == "" {
+			break
+		}
+
+		fmt.Printf("%s value: ", strings.Title(pairType))
+		value, ok := readLine(reader)
+		if !ok {
+			return nil, false
+		}
+
+		pairs[key] = value
+		fmt.Printf("✓ Added %s: %s=%s\n", pairType, key, value)
+	}
+
+	return pairs, true
+}
+
+// promptForResources asks for resource requests and limits. It returns false
+// once input has ended.
+func promptForResources(reader *bufio.Reader) (*corev1.ResourceRequirements, bool) {
+	fmt.Print("\nSet resource limits and requests? (yes/no): ")
+	response, ok := readLine(reader)
+	if !ok {
+		return nil, false
+	}
+	response = strings.ToLower(response)
+
+	if response != "yes" && response != "y" {
+		return nil, true
+	}
+
+	resources := &corev1.ResourceRequirements{
+		Requests: make(corev1.ResourceList),
+		Limits:   make(corev1.ResourceList),
+	}
+
+	// CPU Request
+	fmt.Print("\nCPU request (e.g., '100m', '0.5', '1') [leave empty to skip]: ")
+	cpuRequest, ok := readLine(reader)
+	if !ok {
+		return nil, false
+	}
+	if cpuRequest != "" {
+		quantity, err := parseResourceQuantity(cpuRequest)
+		if err != nil {
+			fmt.Printf("Warning: Invalid CPU request format, skipping: %v\n", err)
+		} else {
+			resources.Requests[corev1.ResourceCPU] = quantity
+		}
+	}
+
+	// Memory Request
+	fmt.Print("Memory request (e.g., '128Mi', '1Gi', '512M') [leave empty to skip]: ")
+	memRequest, ok := readLine(reader)
+	if !ok {
+		return nil, false
+	}
+	if memRequest != "" {
+		quantity, err := parseResourceQuantity(memRequest)
+		if err != nil {
+			fmt.Printf("Warning: Invalid memory request format, skipping: %v\n", err)
+		} else {
+			resources.Requests[corev1.ResourceMemory] = quantity
+		}
+	}
+
+	// CPU Limit
+	fmt.Print("CPU limit (e.g., '500m', '1', '2') [leave empty to skip]: ")
+	cpuLimit, ok := readLine(reader)
+	if !ok {
+		return nil, false
+	}
+	if cpuLimit != "" {
+		quantity, err := parseResourceQuantity(cpuLimit)
+		if err != nil {
+			fmt.Printf("Warning: Invalid CPU limit format, skipping: %v\n", err)
+		} else {
+			resources.Limits[corev1.ResourceCPU] = quantity
+		}
+	}
+
+	// Memory Limit
+	fmt.Print("Memory limit (e.g., '256Mi', '2Gi', '1G') [leave empty to skip]: ")
+	memLimit, ok := readLine(reader)
+	if !ok {
+		return nil, false
+	}
+	if memLimit != "" {
+		quantity, err := parseResourceQuantity(memLimit)
+		if err != nil {
+			fmt.Printf("Warning: Invalid memory limit format, skipping: %v\n", err)
+		} else {
+			resources.Limits[corev1.ResourceMemory] = quantity
+		}
+	}
+
+	// If no resources were set, return nil
+	if len(resources.Requests) == 0 && len(resources.Limits) == 0 {
+		return nil, true
+	}
+
+	return resources, true
+}
+
+func parseResourceQuantity(value string) (resource.Quantity, error) {
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("invalid quantity: %w", err)
+	}
+	return quantity, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadLine(t *testing.T) {
+	reader := bufio.NewReader(strings.NewReader("  first \n\nlast"))
+
+	want := []string{"first", "", "last"}
+	for _, w := range want {
+		got, ok := readLine(reader)
+		if !ok || got != w {
+			t.Fatalf("readLine() = %q, %v, want %q, true", got, ok, w)
+		}
+	}
+	if got, ok := readLine(reader); ok {
+		t.Errorf("readLine() at end of input = %q, true, want false", got)
+	}
+}
+
+func TestPromptsStopAtEndOfInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		prompt func(reader *bufio.Reader) (interface{}, bool)
+		want   interface{}
+		wantOK bool
+	}{
+		{
+			name:  "labels",
+			input: "yes\nteam\nplatform\n\n",
+			prompt: func(reader *bufio.Reader) (interface{}, bool) {
+				return promptForKeyValuePairs(reader, "label")
+			},
+			want:   map[string]string{"team": "platform"},
+			wantOK: true,
+		},
+		{
+			name:  "labels cut short",
+			input: "yes\nteam\n",
+			prompt: func(reader *bufio.Reader) (interface{}, bool) {
+				return promptForKeyValuePairs(reader, "label")
+			},
+			want: map[string]string(nil),
+		},
+		{
+			name:  "args cut short",
+			input: "yes\n--verbose\n",
+			prompt: func(reader *bufio.Reader) (interface{}, bool) {
+				return promptForArgs(reader)
+			},
+			want: []string(nil),
+		},
+		{
+			name: "no resources",
+			prompt: func(reader *bufio.Reader) (interface{}, bool) {
+				return promptForResources(reader)
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.prompt(bufio.NewReader(strings.NewReader(tt.input)))
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
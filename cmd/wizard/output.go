@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grs/mcp-deployment/pkg/deployer"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+// outputFormats lists the values accepted by the -o flag
+const outputFormats = "table, wide, json, yaml, jsonpath=TEMPLATE or name"
+
+// outputFormat is a parsed -o flag
+type outputFormat struct {
+	// name is one of table, wide, json, yaml, jsonpath or name
+	name string
+
+	// path is the parsed template of the jsonpath format
+	path *jsonpath.JSONPath
+}
+
+// parseOutput parses the value of an -o flag
+func parseOutput(value string) (outputFormat, error) {
+	name, template, _ := strings.Cut(value, "=")
+	switch name {
+	case "table", "wide", "json", "yaml", "name":
+		if template != "" {
+			break
+		}
+		return outputFormat{name: name}, nil
+	case "jsonpath":
+		template = strings.Trim(template, `'"`)
+		if template == "" {
+			return outputFormat{}, &usageError{msg: "jsonpath output requires a template, e.g. -o jsonpath='{.items[*].name}'"}
+		}
+		// Accept bare expressions such as .items[0].name, like kubectl
+		if !strings.Contains(template, "{") {
+			template = "{" + template + "}"
+		}
+		path := jsonpath.New("output")
+		if err := path.Parse(template); err != nil {
+			return outputFormat{}, &usageError{msg: fmt.Sprintf("invalid jsonpath template: %v", err)}
+		}
+		return outputFormat{name: name, path: path}, nil
+	}
+	return outputFormat{}, &usageError{msg: fmt.Sprintf("invalid output format %q: must be %s", value, outputFormats)}
+}
+
+// writeServers writes servers in the output format. Structured formats encode
+// obj, which is either the list or the single server requested, so that the
+// output of get is an object and the output of list is a list.
+func writeServers(w io.Writer, format outputFormat, obj interface{}, servers []deployer.MCPServerStatus, withNamespace bool) error {
+	switch format.name {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(obj)
+	case "yaml":
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	case "jsonpath":
+		// The template is evaluated on the JSON form, so field names match -o json
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+		if err := format.path.Execute(w, value); err != nil {
+			return fmt.Errorf("failed to execute jsonpath template: %w", err)
+		}
+		_, err = fmt.Fprintln(w)
+		return err
+	case "name":
+		for _, server := range servers {
+			if _, err := fmt.Fprintf(w, "mcpserver/%s\n", server.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return writeTable(w, servers, withNamespace, format.name == "wide")
+}
+
+// writeTable writes servers as a table, with a namespace column if requested
+// and the phase, restart count and labels in wide mode
+func writeTable(w io.Writer, servers []deployer.MCPServerStatus, withNamespace, wide bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+
+	columns := []string{"NAME", "IMAGE", "READY", "ENDPOINT", "AGE"}
+	if withNamespace {
+		columns = append([]string{"NAMESPACE"}, columns...)
+	}
+	if wide {
+		columns = append(columns, "PHASE", "RESTARTS", "LABELS")
+	}
+	fmt.Fprintln(tw, strings.Join(columns, "\t"))
+
+	now := time.Now()
+	for _, server := range servers {
+		row := []string{
+			server.Name,
+			imageOrPackage(server),
+			fmt.Sprintf("%d/%d", server.ReadyReplicas, server.DesiredReplicas),
+			valueOrNone(server.Endpoint),
+			age(server, now),
+		}
+		if withNamespace {
+			row = append([]string{server.Namespace}, row...)
+		}
+		if wide {
+			row = append(row, string(server.Phase), fmt.Sprint(server.RestartCount), formatLabels(server.Labels))
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// imageOrPackage returns the package of a server deployed from one, which says
+// more than its runner image, and the image otherwise
+func imageOrPackage(server deployer.MCPServerStatus) string {
+	if server.Package != "" {
+		return server.Package
+	}
+	return server.Image
+}
+
+// age returns the age of a server in the short form used by kubectl, e.g. 5m
+func age(server deployer.MCPServerStatus, now time.Time) string {
+	if server.CreationTimestamp.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(now.Sub(server.CreationTimestamp.Time))
+}
+
+// formatLabels returns labels as sorted key=value pairs, omitting the labels
+// every MCP server carries
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		if key == deployer.MCPServerLabel || key == deployer.InstanceLabel {
+			continue
+		}
+		pairs = append(pairs, key+"="+value)
+	}
+	if len(pairs) == 0 {
+		return "<none>"
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// valueOrNone returns the value, or <none> if it is empty
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grs/mcp-deployment/pkg/deployer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testServers returns two servers in different namespaces, one deployed from
+// a package
+func testServers() []deployer.MCPServerStatus {
+	return []deployer.MCPServerStatus{
+		{
+			Name:              "alpha",
+			Namespace:         "team-a",
+			Image:             "example/alpha:1.0",
+			Endpoint:          "http://alpha.team-a.svc.cluster.local:8080/mcp",
+			Labels:            map[string]string{deployer.MCPServerLabel: "true", deployer.InstanceLabel: "alpha", "team": "platform"},
+			Phase:             deployer.MCPServerReady,
+			DesiredReplicas:   2,
+			ReadyReplicas:     2,
+			RestartCount:      3,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-5 * time.Minute)),
+		},
+		{
+			Name:            "bravo",
+			Namespace:       "team-b",
+			Image:           "example/runner:latest",
+			Package:         "npx:@example/bravo",
+			Phase:           deployer.MCPServerPending,
+			DesiredReplicas: 1,
+		},
+	}
+}
+
+func TestParseOutput(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantName string
+		wantErr  bool
+	}{
+		{value: "table", wantName: "table"},
+		{value: "wide", wantName: "wide"},
+		{value: "json", wantName: "json"},
+		{value: "yaml", wantName: "yaml"},
+		{value: "name", wantName: "name"},
+		{value: "jsonpath={.items[*].name}", wantName: "jsonpath"},
+		{value: "jsonpath='{.items[*].name}'", wantName: "jsonpath"},
+		{value: "jsonpath=.items[0].name", wantName: "jsonpath"},
+		{value: "xml", wantErr: true},
+		{value: "", wantErr: true},
+		{value: "json=pretty", wantErr: true},
+		{value: "jsonpath", wantErr: true},
+		{value: "jsonpath=''", wantErr: true},
+		{value: "jsonpath={.items[", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			format, err := parseOutput(tt.value)
+			if tt.wantErr {
+				var usage *usageError
+				if !errors.As(err, &usage) {
+					t.Errorf("parseOutput() error = %v, want a usage error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseOutput() error = %v", err)
+			}
+			if format.name != tt.wantName {
+				t.Errorf("parseOutput() name = %q, want %q", format.name, tt.wantName)
+			}
+			if (format.path != nil) != (tt.wantName == "jsonpath") {
+				t.Errorf("parseOutput() path = %v, want one only for jsonpath", format.path)
+			}
+		})
+	}
+}
+
+func TestWriteServers(t *testing.T) {
+	servers := testServers()
+	list := &deployer.MCPServerList{Items: servers}
+
+	tests := []struct {
+		name   string
+		format string
+		obj    interface{}
+		check  func(t *testing.T, out string)
+	}{
+		{
+			name:   "json list",
+			format: "json",
+			obj:    list,
+			check: func(t *testing.T, out string) {
+				var got deployer.MCPServerList
+				if err := json.Unmarshal([]byte(out), &got); err != nil {
+					t.Fatalf("invalid json %q: %v", out, err)
+				}
+				if len(got.Items) != 2 || got.Items[0].Name != "alpha" || got.Items[1].Package != "npx:@example/bravo" {
+					t.Errorf("decoded list = %+v, want both servers", got.Items)
+				}
+			},
+		},
+		{
+			name:   "json server",
+			format: "json",
+			obj:    servers[0],
+			check: func(t *testing.T, out string) {
+				var got deployer.MCPServerStatus
+				if err := json.Unmarshal([]byte(out), &got); err != nil || got.Name != "alpha" {
+					t.Errorf("decoded server = %+v, %v, want alpha", got, err)
+				}
+			},
+		},
+		{
+			name:   "yaml",
+			format: "yaml",
+			obj:    list,
+			check: func(t *testing.T, out string) {
+				if !strings.HasPrefix(out, "items:\n") || !strings.Contains(out, "name: alpha") || !strings.Contains(out, "package: npx:@example/bravo") {
+					t.Errorf("yaml output = %q, want the list", out)
+				}
+			},
+		},
+		{
+			name:   "jsonpath",
+			format: "jsonpath={.items[*].name}",
+			obj:    list,
+			check: func(t *testing.T, out string) {
+				if out != "alpha bravo\n" {
+					t.Errorf("jsonpath output = %q, want %q", out, "alpha bravo\n")
+				}
+			},
+		},
+		{
+			name:   "jsonpath on json field names",
+			format: "jsonpath={.restartCount}",
+			obj:    servers[0],
+			check: func(t *testing.T, out string) {
+				if out != "3\n" {
+					t.Errorf("jsonpath output = %q, want %q", out, "3\n")
+				}
+			},
+		},
+		{
+			name:   "name",
+			format: "name",
+			obj:    list,
+			check: func(t *testing.T, out string) {
+				if out != "mcpserver/alpha\nmcpserver/bravo\n" {
+					t.Errorf("name output = %q", out)
+				}
+			},
+		},
+		{
+			name:   "table",
+			format: "table",
+			obj:    list,
+			check: func(t *testing.T, out string) {
+				lines := strings.Split(strings.TrimSpace(out), "\n")
+				if len(lines) != 3 || strings.Fields(lines[0])[0] != "NAME" || strings.Contains(lines[0], "PHASE") {
+					t.Errorf("table output = %q, want a header and two rows", out)
+				}
+			},
+		},
+		{
+			name:   "wide",
+			format: "wide",
+			obj:    list,
+			check: func(t *testing.T, out string) {
+				lines := strings.Split(strings.TrimSpace(out), "\n")
+				if len(lines) != 3 || !strings.HasSuffix(strings.Join(strings.Fields(lines[0]), " "), "PHASE RESTARTS LABELS") {
+					t.Fatalf("wide output = %q, want the phase, restarts and labels", out)
+				}
+				if fields := strings.Fields(lines[1]); fields[len(fields)-1] != "team=platform" || fields[len(fields)-2] != "3" {
+					t.Errorf("wide row = %q, want 3 restarts and only the custom labels", lines[1])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := parseOutput(tt.format)
+			if err != nil {
+				t.Fatalf("parseOutput() error = %v", err)
+			}
+			var out bytes.Buffer
+			if err := writeServers(&out, format, tt.obj, servers, false); err != nil {
+				t.Fatalf("writeServers() error = %v", err)
+			}
+			tt.check(t, out.String())
+		})
+	}
+}
+
+func TestWriteServersJSONPathError(t *testing.T) {
+	format, err := parseOutput("jsonpath={.missing}")
+	if err != nil {
+		t.Fatalf("parseOutput() error = %v", err)
+	}
+	if err := writeServers(&bytes.Buffer{}, format, testServers()[0], nil, false); err == nil {
+		t.Error("writeServers() with a missing field succeeded, want an error")
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	tests := []struct {
+		name          string
+		withNamespace bool
+		wide          bool
+		wantHeader    []string
+		wantRows      [][]string
+	}{
+		{
+			name:       "default",
+			wantHeader: []string{"NAME", "IMAGE", "READY", "ENDPOINT", "AGE"},
+			wantRows: [][]string{
+				{"alpha", "example/alpha:1.0", "2/2", "http://alpha.team-a.svc.cluster.local:8080/mcp", "5m"},
+				{"bravo", "npx:@example/bravo", "0/1", "<none>", "<unknown>"},
+			},
+		},
+		{
+			name:          "with namespace",
+			withNamespace: true,
+			wantHeader:    []string{"NAMESPACE", "NAME", "IMAGE", "READY", "ENDPOINT", "AGE"},
+			wantRows: [][]string{
+				{"team-a", "alpha", "example/alpha:1.0", "2/2", "http://alpha.team-a.svc.cluster.local:8080/mcp", "5m"},
+				{"team-b", "bravo", "npx:@example/bravo", "0/1", "<none>", "<unknown>"},
+			},
+		},
+		{
+			name:       "wide",
+			wide:       true,
+			wantHeader: []string{"NAME", "IMAGE", "READY", "ENDPOINT", "AGE", "PHASE", "RESTARTS", "LABELS"},
+			wantRows: [][]string{
+				{"alpha", "example/alpha:1.0", "2/2", "http://alpha.team-a.svc.cluster.local:8080/mcp", "5m", "Ready", "3", "team=platform"},
+				{"bravo", "npx:@example/bravo", "0/1", "<none>", "<unknown>", "Pending", "0", "<none>"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := writeTable(&out, testServers(), tt.withNamespace, tt.wide); err != nil {
+				t.Fatalf("writeTable() error = %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			want := append([][]string{tt.wantHeader}, tt.wantRows...)
+			if len(lines) != len(want) {
+				t.Fatalf("writeTable() = %q, want %d lines", out.String(), len(want))
+			}
+			for i, line := range lines {
+				if got := strings.Fields(line); strings.Join(got, " ") != strings.Join(want[i], " ") {
+					t.Errorf("line %d = %q, want %q", i, got, want[i])
+				}
+			}
+		})
+	}
+}
@@ -4,26 +4,23 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
-	"path/filepath"
 
 	"github.com/grs/mcp-deployment/pkg/deployer"
+	"github.com/grs/mcp-deployment/pkg/kubeclient"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 func main() {
-	// Build Kubernetes config from kubeconfig file
-	kubeconfig := filepath.Join(os.Getenv("HOME"), ".kube", "config")
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	// Load the Kubernetes config the way kubectl does
+	config, err := kubeclient.Load(kubeclient.Options{})
 	if err != nil {
-		log.Fatalf("Failed to build config: %v", err)
+		log.Fatalf("Failed to load config: %v", err)
 	}
 
 	// Create Kubernetes clientset
-	clientset, err := kubernetes.NewForConfig(config)
+	clientset, err := kubernetes.NewForConfig(config.REST)
 	if err != nil {
 		log.Fatalf("Failed to create clientset: %v", err)
 	}
@@ -93,18 +90,20 @@ func main() {
 
 	// Example 2: List all MCP servers
 	fmt.Println("\nListing MCP servers in namespace 'default'...")
-	servers, err := mcpDeployer.ListMCPServers(context.Background(), "default")
+	servers, err := mcpDeployer.ListMCPServers(context.Background(), deployer.ListOptions{Namespace: "default"})
 	if err != nil {
 		log.Fatalf("Failed to list MCP servers: %v", err)
 	}
 
-	fmt.Printf("\nFound %d MCP server(s):\n\n", len(servers))
-	for i, server := range servers {
+	fmt.Printf("\nFound %d MCP server(s):\n\n", len(servers.Items))
+	for i, server := range servers.Items {
 		fmt.Printf("Server %d:\n", i+1)
 		fmt.Printf("  Name: %s\n", server.Name)
 		fmt.Printf("  Namespace: %s\n", server.Namespace)
 		fmt.Printf("  Image: %s\n", server.Image)
 		fmt.Printf("  Available: %t\n", server.Available)
+		fmt.Printf("  Phase: %s\n", server.Phase)
+		fmt.Printf("  Replicas: %d/%d ready\n", server.ReadyReplicas, server.DesiredReplicas)
 
 		fmt.Println("  Labels:")
 		for k, v := range server.Labels {
@@ -121,7 +120,7 @@ func main() {
 		if len(server.Conditions) > 0 {
 			fmt.Println("  Conditions:")
 			for _, condition := range server.Conditions {
-				fmt.Printf("    - %s\n", condition)
+				fmt.Printf("    - %s: %s - %s\n", condition.Type, condition.Status, condition.Message)
 			}
 		}
 		fmt.Println()
@@ -131,7 +130,7 @@ func main() {
 	// Uncomment to test deletion
 	/*
 		fmt.Println("\nDeleting MCP server 'example-mcp-server'...")
-		err = mcpDeployer.DeleteMCPServer(context.Background(), "default", "example-mcp-server")
+		err = mcpDeployer.DeleteMCPServer(context.Background(), "default", "example-mcp-server", deployer.DeleteOptions{})
 		if err != nil {
 			log.Fatalf("Failed to delete MCP server: %v", err)
 		}
@@ -0,0 +1,160 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto copies the MCPServer into out
+func (in *MCPServer) DeepCopyInto(out *MCPServer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy returns a deep copy of the MCPServer
+func (in *MCPServer) DeepCopy() *MCPServer {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object
+func (in *MCPServer) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the MCPServerSpec into out
+func (in *MCPServerSpec) DeepCopyInto(out *MCPServerSpec) {
+	*out = *in
+	if in.EnvVars != nil {
+		out.EnvVars = make([]corev1.EnvVar, len(in.EnvVars))
+		for i := range in.EnvVars {
+			in.EnvVars[i].DeepCopyInto(&out.EnvVars[i])
+		}
+	}
+	if in.Command != nil {
+		out.Command = append([]string(nil), in.Command...)
+	}
+	if in.Args != nil {
+		out.Args = append([]string(nil), in.Args...)
+	}
+	if in.SecretMounts != nil {
+		out.SecretMounts = append([]SecretMount(nil), in.SecretMounts...)
+	}
+	out.Labels = copyStringMap(in.Labels)
+	out.Annotations = copyStringMap(in.Annotations)
+	if in.Resources != nil {
+		out.Resources = in.Resources.DeepCopy()
+	}
+	out.ReadinessProbe = copyProbe(in.ReadinessProbe)
+	out.LivenessProbe = copyProbe(in.LivenessProbe)
+	out.StartupProbe = copyProbe(in.StartupProbe)
+	if in.Bridge != nil {
+		bridge := *in.Bridge
+		bridge.Command = append([]string(nil), in.Bridge.Command...)
+		out.Bridge = &bridge
+	}
+	if in.Package != nil {
+		pkg := *in.Package
+		out.Package = &pkg
+	}
+	if in.Replicas != nil {
+		replicas := *in.Replicas
+		out.Replicas = &replicas
+	}
+	if in.Autoscaling != nil {
+		autoscaling := *in.Autoscaling
+		out.Autoscaling = &autoscaling
+	}
+	if in.SessionAffinity != nil {
+		affinity := *in.SessionAffinity
+		out.SessionAffinity = &affinity
+	}
+}
+
+// copyProbe returns a copy of a probe, or nil
+func copyProbe(in *Probe) *Probe {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	return &out
+}
+
+// DeepCopy returns a deep copy of the MCPServerSpec
+func (in *MCPServerSpec) DeepCopy() *MCPServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the MCPServerStatus into out
+func (in *MCPServerStatus) DeepCopyInto(out *MCPServerStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		out.Conditions = make([]metav1.Condition, len(in.Conditions))
+		for i := range in.Conditions {
+			in.Conditions[i].DeepCopyInto(&out.Conditions[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the MCPServerStatus
+func (in *MCPServerStatus) DeepCopy() *MCPServerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the MCPServerList into out
+func (in *MCPServerList) DeepCopyInto(out *MCPServerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]MCPServer, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the MCPServerList
+func (in *MCPServerList) DeepCopy() *MCPServerList {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object
+func (in *MCPServerList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// copyStringMap returns a copy of a map, or nil if it is nil
+func copyStringMap(in map[string]string) map[string]string {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]string, len(in))
+	for key, value := range in {
+		out[key] = value
+	}
+	return out
+}
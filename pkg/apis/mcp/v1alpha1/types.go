@@ -0,0 +1,166 @@
+// Package v1alpha1 contains the MCPServer custom resource, which declares an
+// MCP server for the controller to reconcile into a Deployment and Service.
+// The types are read and written through the dynamic client, converting to
+// and from unstructured objects.
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// GroupName is the API group of the MCPServer resource
+	GroupName = "mcp.opendatahub.io"
+
+	// Kind is the kind of the MCPServer resource
+	Kind = "MCPServer"
+
+	// ListKind is the kind of a list of MCPServer resources
+	ListKind = "MCPServerList"
+)
+
+var (
+	// SchemeGroupVersion is the group version of the MCPServer resource
+	SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha1"}
+
+	// Resource is the group version resource used with the dynamic client
+	Resource = SchemeGroupVersion.WithResource("mcpservers")
+)
+
+// MCPServer declares an MCP server running in the cluster
+type MCPServer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MCPServerSpec   `json:"spec"`
+	Status MCPServerStatus `json:"status,omitempty"`
+}
+
+// MCPServerSpec mirrors the deployer's MCPServerSpec. The name and namespace
+// of the server are those of the resource.
+type MCPServerSpec struct {
+	Image           string                       `json:"image,omitempty"`
+	Port            int32                        `json:"port"`
+	Transport       string                       `json:"transport,omitempty"`
+	Path            string                       `json:"path,omitempty"`
+	EnvVars         []corev1.EnvVar              `json:"envVars,omitempty"`
+	Command         []string                     `json:"command,omitempty"`
+	Args            []string                     `json:"args,omitempty"`
+	WorkingDir      string                       `json:"workingDir,omitempty"`
+	SecretMounts    []SecretMount                `json:"secretMounts,omitempty"`
+	ServiceAccount  string                       `json:"serviceAccount,omitempty"`
+	Labels          map[string]string            `json:"labels,omitempty"`
+	Annotations     map[string]string            `json:"annotations,omitempty"`
+	Resources       *corev1.ResourceRequirements `json:"resources,omitempty"`
+	ReadinessProbe  *Probe                       `json:"readinessProbe,omitempty"`
+	LivenessProbe   *Probe                       `json:"livenessProbe,omitempty"`
+	StartupProbe    *Probe                       `json:"startupProbe,omitempty"`
+	ProbeImage      string                       `json:"probeImage,omitempty"`
+	Bridge          *StdioBridge                 `json:"bridge,omitempty"`
+	Package         *Package                     `json:"package,omitempty"`
+	Replicas        *int32                       `json:"replicas,omitempty"`
+	Autoscaling     *Autoscaling                 `json:"autoscaling,omitempty"`
+	SessionAffinity *SessionAffinity             `json:"sessionAffinity,omitempty"`
+}
+
+// Autoscaling scales an MCP server with a HorizontalPodAutoscaler
+type Autoscaling struct {
+	Min int32 `json:"min,omitempty"`
+	Max int32 `json:"max"`
+
+	// TargetCPU and TargetMemory are average utilizations in percent of the
+	// resource requests
+	TargetCPU    int32 `json:"targetCPU,omitempty"`
+	TargetMemory int32 `json:"targetMemory,omitempty"`
+}
+
+// SessionAffinity keeps the requests of an MCP session on one replica
+type SessionAffinity struct {
+	// Mode is one of none, client-ip or router
+	Mode string `json:"mode"`
+
+	// TimeoutSeconds applies to client-ip and RouterImage to router
+	TimeoutSeconds int32  `json:"timeoutSeconds,omitempty"`
+	RouterImage    string `json:"routerImage,omitempty"`
+}
+
+// Package runs an MCP server published to npm or PyPI
+type Package struct {
+	// Runtime is one of npx or uvx
+	Runtime    string `json:"runtime"`
+	Name       string `json:"name"`
+	Version    string `json:"version,omitempty"`
+	CacheClaim string `json:"cacheClaim,omitempty"`
+}
+
+// StdioBridge configures the bridge in front of a stdio MCP server
+type StdioBridge struct {
+	Command []string `json:"command,omitempty"`
+
+	// Sessions is one of per-session or shared
+	Sessions string `json:"sessions,omitempty"`
+	Image    string `json:"image,omitempty"`
+}
+
+// Probe configures a readiness, liveness or startup probe of the MCP server
+type Probe struct {
+	// Type is one of tcp, mcp or none
+	Type string `json:"type,omitempty"`
+	Path string `json:"path,omitempty"`
+
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+	PeriodSeconds       int32 `json:"periodSeconds,omitempty"`
+	TimeoutSeconds      int32 `json:"timeoutSeconds,omitempty"`
+	FailureThreshold    int32 `json:"failureThreshold,omitempty"`
+}
+
+// SecretMount mounts a secret into the MCP server container
+type SecretMount struct {
+	SecretName string `json:"secretName"`
+	MountPath  string `json:"mountPath"`
+}
+
+// MCPServerStatus is the status of an MCP server as observed by the controller
+type MCPServerStatus struct {
+	// Phase is one of Pending, Progressing, Ready, Degraded or Failed
+	Phase string `json:"phase,omitempty"`
+
+	Image    string `json:"image,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+
+	DesiredReplicas   int32 `json:"desiredReplicas,omitempty"`
+	ReadyReplicas     int32 `json:"readyReplicas,omitempty"`
+	UpdatedReplicas   int32 `json:"updatedReplicas,omitempty"`
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+
+	RestartCount          int32  `json:"restartCount,omitempty"`
+	LastTerminationReason string `json:"lastTerminationReason,omitempty"`
+
+	// ObservedGeneration is the generation of the MCPServer last reconciled
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions include Ready along with the conditions of the Deployment
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// MCPServerList is a list of MCPServer resources
+type MCPServerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []MCPServer `json:"items"`
+}
+
+const (
+	// ConditionReady reports whether the MCP server is ready to serve
+	ConditionReady = "Ready"
+
+	// ReasonInvalidSpec is the Ready reason when the spec fails validation
+	ReasonInvalidSpec = "InvalidSpec"
+
+	// ReasonApplyFailed is the Ready reason when the Deployment or Service
+	// could not be applied
+	ReasonApplyFailed = "ApplyFailed"
+)
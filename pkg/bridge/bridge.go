@@ -0,0 +1,510 @@
+// Package bridge serves an MCP server that speaks stdio over HTTP. It runs the
+// server as a subprocess and exposes it with the streamable HTTP transport, and
+// with the older SSE transport for clients that have not moved on yet.
+package bridge
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SessionMode selects how client sessions map to server processes
+type SessionMode string
+
+const (
+	// PerSession starts a server process for each session and stops it when
+	// the session ends. It is the default, and isolates clients completely.
+	PerSession SessionMode = "per-session"
+
+	// Shared runs a single server process for all sessions. The server is
+	// initialized once, notifications from it go to every session, and
+	// requests from it go to the session that sent a request last.
+	Shared SessionMode = "shared"
+)
+
+const (
+	// DefaultPath is the path of the streamable HTTP endpoint
+	DefaultPath = "/mcp"
+
+	// SSEPath is the event stream endpoint of the SSE transport
+	SSEPath = "/sse"
+
+	// MessagePath is where SSE clients post their messages
+	MessagePath = "/message"
+
+	// sessionHeader carries the session id in the streamable HTTP transport
+	sessionHeader = "Mcp-Session-Id"
+
+	// maxBodySize bounds the body of a POST
+	maxBodySize = 16 * 1024 * 1024
+
+	// streamBuffer is the number of messages a stream holds before the server
+	// waits for the client to read them
+	streamBuffer = 64
+)
+
+// Options configures a Bridge
+type Options struct {
+	// Command is the stdio MCP server and its arguments
+	Command []string
+
+	// Mode is PerSession if empty
+	Mode SessionMode
+
+	// Path is the streamable HTTP endpoint, DefaultPath if empty
+	Path string
+
+	// IdleTimeout ends sessions that have had no requests and no open
+	// streams for the duration, stopping their server in PerSession mode, so
+	// that clients that go away without ending their session do not leave it
+	// running. Zero keeps idle sessions.
+	IdleTimeout time.Duration
+
+	// Stderr receives the standard error of the server processes, os.Stderr
+	// if nil
+	Stderr io.Writer
+
+	// Logger reports problems with the server processes, the standard logger
+	// if nil
+	Logger *log.Logger
+}
+
+// Bridge is an http.Handler serving a stdio MCP server
+type Bridge struct {
+	opts Options
+
+	mu       sync.Mutex
+	sessions map[string]*session
+	shared   *process
+	closed   bool
+
+	// stop ends the reaping of idle sessions
+	stop chan struct{}
+}
+
+// New returns a bridge for the options. Server processes are started as
+// clients connect.
+func New(opts Options) (*Bridge, error) {
+	if len(opts.Command) == 0 {
+		return nil, errors.New("a server command is required")
+	}
+	switch opts.Mode {
+	case "":
+		opts.Mode = PerSession
+	case PerSession, Shared:
+	default:
+		return nil, fmt.Errorf("invalid session mode %q: must be %s or %s", opts.Mode, PerSession, Shared)
+	}
+	if opts.Path == "" {
+		opts.Path = DefaultPath
+	}
+	if opts.IdleTimeout < 0 {
+		return nil, errors.New("the idle timeout must not be negative")
+	}
+	if opts.Stderr == nil {
+		opts.Stderr = os.Stderr
+	}
+	if opts.Logger == nil {
+		opts.Logger = log.Default()
+	}
+
+	b := &Bridge{opts: opts, sessions: make(map[string]*session), stop: make(chan struct{})}
+	if opts.IdleTimeout > 0 {
+		go b.reapIdleSessions()
+	}
+	return b, nil
+}
+
+// Close ends every session and stops the server processes
+func (b *Bridge) Close() {
+	b.mu.Lock()
+	if !b.closed {
+		close(b.stop)
+	}
+	b.closed = true
+	sessions := b.sessions
+	b.sessions = make(map[string]*session)
+	shared := b.shared
+	b.shared = nil
+	b.mu.Unlock()
+
+	for _, s := range sessions {
+		s.close()
+		if b.opts.Mode == PerSession {
+			s.proc.kill()
+		}
+	}
+	if shared != nil {
+		shared.kill()
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (b *Bridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == b.opts.Path && r.Method == http.MethodPost:
+		b.handlePost(w, r)
+	case r.URL.Path == b.opts.Path && r.Method == http.MethodGet:
+		b.handleListen(w, r)
+	case r.URL.Path == b.opts.Path && r.Method == http.MethodDelete:
+		b.handleDelete(w, r)
+	case r.URL.Path == SSEPath && r.Method == http.MethodGet:
+		b.handleSSE(w, r)
+	case r.URL.Path == MessagePath && r.Method == http.MethodPost:
+		b.handleMessage(w, r)
+	case r.URL.Path == b.opts.Path || r.URL.Path == SSEPath || r.URL.Path == MessagePath:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handlePost forwards the messages of a streamable HTTP POST. An initialize
+// request starts a new session. Responses are returned as an event stream, or
+// as JSON if the client does not accept event streams.
+func (b *Bridge) handlePost(w http.ResponseWriter, r *http.Request) {
+	msgs, ok := readMessages(w, r)
+	if !ok {
+		return
+	}
+
+	var s *session
+	if msgs[0].method() == "initialize" {
+		if len(msgs) != 1 {
+			writeError(w, http.StatusBadRequest, codeInvalidReq, "initialize must not be part of a batch")
+			return
+		}
+		var err error
+		if s, err = b.newSession(); err != nil {
+			writeError(w, http.StatusInternalServerError, codeInternalError, err.Error())
+			return
+		}
+		w.Header().Set(sessionHeader, s.id)
+	} else if s, ok = b.session(w, r.Header.Get(sessionHeader)); !ok {
+		return
+	}
+
+	pending := make(map[string]bool)
+	for _, msg := range msgs {
+		if msg.isRequest() {
+			pending[string(msg["id"])] = true
+		}
+	}
+	if len(pending) == 0 {
+		for _, msg := range msgs {
+			s.proc.forward(s, msg, nil)
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	st := s.openStream()
+	defer s.closeStream(st)
+	for _, msg := range msgs {
+		s.proc.forward(s, msg, st)
+	}
+
+	if acceptsEventStream(r) {
+		// Messages from the server related to the requests are streamed too
+		flusher := startEventStream(w)
+		for len(pending) > 0 {
+			msg, ok := st.next(r, s)
+			if !ok {
+				return
+			}
+			if msg.isResponse() {
+				delete(pending, string(msg["id"]))
+			}
+			writeEvent(w, "message", msg)
+			flusher.Flush()
+		}
+		return
+	}
+
+	var responses []message
+	for len(pending) > 0 {
+		msg, ok := st.next(r, s)
+		if !ok {
+			return
+		}
+		if msg.isResponse() && pending[string(msg["id"])] {
+			delete(pending, string(msg["id"]))
+			responses = append(responses, msg)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if len(msgs) == 1 {
+		json.NewEncoder(w).Encode(responses[0])
+	} else {
+		json.NewEncoder(w).Encode(responses)
+	}
+}
+
+// handleListen streams the messages the server sends outside of a request
+func (b *Bridge) handleListen(w http.ResponseWriter, r *http.Request) {
+	if !acceptsEventStream(r) {
+		http.Error(w, "text/event-stream must be accepted", http.StatusNotAcceptable)
+		return
+	}
+	s, ok := b.session(w, r.Header.Get(sessionHeader))
+	if !ok {
+		return
+	}
+	st, ok := s.listen()
+	if !ok {
+		http.Error(w, "the session already has an event stream", http.StatusConflict)
+		return
+	}
+	defer s.stopListening(st)
+
+	flusher := startEventStream(w)
+	for {
+		msg, ok := st.next(r, s)
+		if !ok {
+			return
+		}
+		writeEvent(w, "message", msg)
+		flusher.Flush()
+	}
+}
+
+// handleDelete ends a streamable HTTP session
+func (b *Bridge) handleDelete(w http.ResponseWriter, r *http.Request) {
+	s, ok := b.session(w, r.Header.Get(sessionHeader))
+	if !ok {
+		return
+	}
+	b.endSession(s)
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleSSE serves a session of the SSE transport, which lasts as long as the
+// event stream. The first event tells the client where to post messages.
+func (b *Bridge) handleSSE(w http.ResponseWriter, r *http.Request) {
+	s, err := b.newSession()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer b.endSession(s)
+	st, _ := s.listen()
+
+	flusher := startEventStream(w)
+	fmt.Fprintf(w, "event: endpoint\ndata: %s?sessionId=%s\n\n", MessagePath, s.id)
+	flusher.Flush()
+	for {
+		msg, ok := st.next(r, s)
+		if !ok {
+			return
+		}
+		writeEvent(w, "message", msg)
+		flusher.Flush()
+	}
+}
+
+// handleMessage forwards messages posted by an SSE client. Responses are sent
+// on the event stream of the session.
+func (b *Bridge) handleMessage(w http.ResponseWriter, r *http.Request) {
+	s, ok := b.session(w, r.URL.Query().Get("sessionId"))
+	if !ok {
+		return
+	}
+	msgs, ok := readMessages(w, r)
+	if !ok {
+		return
+	}
+
+	st := s.listener()
+	for _, msg := range msgs {
+		s.proc.forward(s, msg, st)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// newSession starts a session, with its own server process in PerSession mode
+func (b *Bridge) newSession() (*session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, errors.New("bridge is closed")
+	}
+
+	proc := b.shared
+	if proc == nil || b.opts.Mode == PerSession {
+		if proc, err = startProcess(b.opts.Command, b.opts.Stderr, b.opts.Logger, b.processExited); err != nil {
+			return nil, err
+		}
+		if b.opts.Mode == Shared {
+			b.shared = proc
+		}
+	}
+
+	s := newSession(id, proc)
+	if !proc.attach(s) {
+		// The shared server has just exited; the next session starts a new one
+		b.shared = nil
+		return nil, errors.New("MCP server process exited")
+	}
+	b.sessions[id] = s
+	return s, nil
+}
+
+// session looks up the session of a request, reporting a missing or unknown
+// session to the client
+func (b *Bridge) session(w http.ResponseWriter, id string) (*session, bool) {
+	if id == "" {
+		writeError(w, http.StatusBadRequest, codeInvalidReq, "missing session id")
+		return nil, false
+	}
+	b.mu.Lock()
+	s, ok := b.sessions[id]
+	b.mu.Unlock()
+	if !ok {
+		// Clients start a new session when they see 404
+		writeError(w, http.StatusNotFound, codeInvalidReq, "unknown session")
+		return nil, false
+	}
+	s.touch()
+	return s, true
+}
+
+// endSession removes a session, stopping its server in PerSession mode
+func (b *Bridge) endSession(s *session) {
+	b.mu.Lock()
+	_, ok := b.sessions[s.id]
+	delete(b.sessions, s.id)
+	b.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	s.close()
+	s.proc.detach(s)
+	if b.opts.Mode == PerSession {
+		s.proc.kill()
+	}
+}
+
+// reapIdleSessions ends the sessions that have been idle for the idle timeout
+// until the bridge is closed. Sessions are checked twice per timeout, so an
+// idle session ends within one and a half timeouts.
+func (b *Bridge) reapIdleSessions() {
+	ticker := time.NewTicker(b.opts.IdleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+		}
+
+		var idle []*session
+		b.mu.Lock()
+		for _, s := range b.sessions {
+			if s.idle(b.opts.IdleTimeout) {
+				idle = append(idle, s)
+			}
+		}
+		b.mu.Unlock()
+
+		for _, s := range idle {
+			b.opts.Logger.Printf("ending session %s, idle for %s", s.id, b.opts.IdleTimeout)
+			b.endSession(s)
+		}
+	}
+}
+
+// processExited removes the sessions of a server process that has exited
+func (b *Bridge) processExited(sessions []*session) {
+	b.mu.Lock()
+	for _, s := range sessions {
+		delete(b.sessions, s.id)
+		if b.shared == s.proc {
+			b.shared = nil
+		}
+	}
+	b.mu.Unlock()
+
+	for _, s := range sessions {
+		s.close()
+	}
+}
+
+// readMessages reads the JSON-RPC messages of a POST body, reporting invalid
+// bodies to the client
+func readMessages(w http.ResponseWriter, r *http.Request) ([]message, bool) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeParseError, fmt.Sprintf("failed to read body: %v", err))
+		return nil, false
+	}
+	msgs, err := parseMessages(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeParseError, err.Error())
+		return nil, false
+	}
+	return msgs, true
+}
+
+// writeError writes an HTTP error with a JSON-RPC error body
+func writeError(w http.ResponseWriter, status, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse(nil, code, msg))
+}
+
+// acceptsEventStream reports whether the client accepts an event stream
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// startEventStream writes the headers of an event stream
+func startEventStream(w http.ResponseWriter) http.Flusher {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return noFlush{}
+	}
+	flusher.Flush()
+	return flusher
+}
+
+// noFlush is used for writers that cannot flush
+type noFlush struct{}
+
+func (noFlush) Flush() {}
+
+// writeEvent writes a message as a server-sent event
+func writeEvent(w io.Writer, event string, msg message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
+
+// newSessionID returns a random session id
+func newSessionID() (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", fmt.Errorf("failed to create session id: %w", err)
+	}
+	return hex.EncodeToString(id[:]), nil
+}
@@ -0,0 +1,480 @@
+package bridge
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/grs/mcp-deployment/pkg/mcpprobe"
+)
+
+// toyServerEnv makes the test binary run as a stdio MCP server
+const toyServerEnv = "BRIDGE_TOY_SERVER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(toyServerEnv) == "1" {
+		runToyServer()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// runToyServer is a stdio MCP server with a few methods for the tests:
+// echo returns its params, pid the process id, initializations the number of
+// initialize requests seen, notify sends a notification before answering and
+// exit exits without answering.
+func runToyServer() {
+	out := json.NewEncoder(os.Stdout)
+	initializations := 0
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil || req.ID == nil {
+			continue
+		}
+
+		var result interface{}
+		switch req.Method {
+		case "initialize":
+			initializations++
+			fmt.Println("not json: servers sometimes log to stdout")
+			result = map[string]interface{}{
+				"protocolVersion": mcpprobe.ProtocolVersion,
+				"capabilities":    map[string]interface{}{},
+				"serverInfo":      map[string]string{"name": "toy", "version": "1.0.0"},
+			}
+		case "echo":
+			result = req.Params
+		case "pid":
+			result = os.Getpid()
+		case "initializations":
+			result = initializations
+		case "notify":
+			out.Encode(map[string]interface{}{"jsonrpc": "2.0", "method": "notifications/message", "params": map[string]string{"data": "hello"}})
+			result = "notified"
+		case "exit":
+			os.Exit(3)
+		}
+		out.Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}
+}
+
+// newTestBridge serves a bridge to the toy server
+func newTestBridge(t *testing.T, opts Options) *httptest.Server {
+	t.Helper()
+	t.Setenv(toyServerEnv, "1")
+	opts.Command = []string{os.Args[0]}
+	opts.Logger = log.New(io.Discard, "", 0)
+	b, err := New(opts)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	server := httptest.NewServer(b)
+	t.Cleanup(func() {
+		server.Close()
+		b.Close()
+	})
+	return server
+}
+
+// post sends a streamable HTTP POST
+func post(t *testing.T, url, sessionID, accept, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url+DefaultPath, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", accept)
+	if sessionID != "" {
+		req.Header.Set(sessionHeader, sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// initialize starts a session and returns its id
+func initialize(t *testing.T, url string) string {
+	t.Helper()
+	resp := post(t, url, "", "application/json", `{"jsonrpc":"2.0","id":0,"method":"initialize","params":{}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("initialize status = %d, want 200", resp.StatusCode)
+	}
+	id := resp.Header.Get(sessionHeader)
+	if id == "" {
+		t.Fatal("initialize returned no session id")
+	}
+	post(t, url, id, "application/json", `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	return id
+}
+
+// send sends a request and decodes the JSON response
+func send(url, sessionID, body string) (map[string]json.RawMessage, error) {
+	req, err := http.NewRequest(http.MethodPost, url+DefaultPath, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(sessionHeader, sessionID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	var msg map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return msg, nil
+}
+
+// callJSON sends a request and decodes the JSON response, failing the test on
+// errors
+func callJSON(t *testing.T, url, sessionID, body string) map[string]json.RawMessage {
+	t.Helper()
+	msg, err := send(url, sessionID, body)
+	if err != nil {
+		t.Fatalf("POST %s: %v", body, err)
+	}
+	return msg
+}
+
+// readEvent reads a server-sent event
+func readEvent(t *testing.T, r *bufio.Reader) (event, data string) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read event: %v", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "" && data != "":
+			return event, data
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestSessionModes(t *testing.T) {
+	tests := []struct {
+		mode        SessionMode
+		wantSamePID bool
+	}{
+		{mode: PerSession, wantSamePID: false},
+		{mode: Shared, wantSamePID: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			server := newTestBridge(t, Options{Mode: tt.mode})
+			first := initialize(t, server.URL)
+			second := initialize(t, server.URL)
+
+			pid1 := callJSON(t, server.URL, first, `{"jsonrpc":"2.0","id":1,"method":"pid"}`)["result"]
+			pid2 := callJSON(t, server.URL, second, `{"jsonrpc":"2.0","id":1,"method":"pid"}`)["result"]
+			if (string(pid1) == string(pid2)) != tt.wantSamePID {
+				t.Errorf("pids = %s and %s, want same = %v", pid1, pid2, tt.wantSamePID)
+			}
+
+			// A shared server is initialized once, whatever the number of sessions
+			got := callJSON(t, server.URL, second, `{"jsonrpc":"2.0","id":2,"method":"initializations"}`)["result"]
+			if string(got) != "1" {
+				t.Errorf("initializations = %s, want 1", got)
+			}
+		})
+	}
+}
+
+func TestSharedSessionsKeepTheirRequestIDs(t *testing.T) {
+	server := newTestBridge(t, Options{Mode: Shared})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		sessionID := initialize(t, server.URL)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				body := fmt.Sprintf(`{"jsonrpc":"2.0","id":"req","method":"echo","params":{"session":%d}}`, i)
+				msg, err := send(server.URL, sessionID, body)
+				if err != nil {
+					t.Errorf("session %d: %v", i, err)
+					return
+				}
+				if string(msg["id"]) != `"req"` || string(msg["result"]) != fmt.Sprintf(`{"session":%d}`, i) {
+					t.Errorf("session %d got %s %s", i, msg["id"], msg["result"])
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestBatch(t *testing.T) {
+	server := newTestBridge(t, Options{Mode: PerSession})
+	sessionID := initialize(t, server.URL)
+
+	resp := post(t, server.URL, sessionID, "application/json",
+		`[{"jsonrpc":"2.0","id":1,"method":"echo","params":{"n":1}},{"jsonrpc":"2.0","method":"notifications/progress"},{"jsonrpc":"2.0","id":2,"method":"echo","params":{"n":2}}]`)
+	var msgs []map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&msgs); err != nil {
+		t.Fatalf("failed to decode responses: %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("got %d responses, want 2", len(msgs))
+	}
+	for _, msg := range msgs {
+		if want := fmt.Sprintf(`{"n":%s}`, msg["id"]); string(msg["result"]) != want {
+			t.Errorf("response %s result = %s, want %s", msg["id"], msg["result"], want)
+		}
+	}
+}
+
+func TestEventStreamResponse(t *testing.T) {
+	server := newTestBridge(t, Options{Mode: PerSession})
+	sessionID := initialize(t, server.URL)
+
+	resp := post(t, server.URL, sessionID, "application/json, text/event-stream", `{"jsonrpc":"2.0","id":1,"method":"notify"}`)
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+	r := bufio.NewReader(resp.Body)
+	if _, data := readEvent(t, r); !strings.Contains(data, `"notifications/message"`) {
+		t.Errorf("first event = %s, want the notification", data)
+	}
+	if _, data := readEvent(t, r); !strings.Contains(data, `"result":"notified"`) {
+		t.Errorf("second event = %s, want the response", data)
+	}
+	if _, err := r.ReadString('\n'); err != io.EOF {
+		t.Errorf("stream was not closed after the response: %v", err)
+	}
+}
+
+func TestListeningStream(t *testing.T) {
+	server := newTestBridge(t, Options{Mode: Shared})
+	listener := initialize(t, server.URL)
+	other := initialize(t, server.URL)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+DefaultPath, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(sessionHeader, listener)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	second, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	second.Body.Close()
+	if second.StatusCode != http.StatusConflict {
+		t.Errorf("second GET status = %d, want 409", second.StatusCode)
+	}
+
+	// Notifications of a shared server reach every session
+	callJSON(t, server.URL, other, `{"jsonrpc":"2.0","id":1,"method":"notify"}`)
+	if _, data := readEvent(t, bufio.NewReader(resp.Body)); !strings.Contains(data, `"notifications/message"`) {
+		t.Errorf("event = %s, want the notification", data)
+	}
+}
+
+func TestSessionErrors(t *testing.T) {
+	server := newTestBridge(t, Options{Mode: PerSession})
+	sessionID := initialize(t, server.URL)
+
+	tests := []struct {
+		name      string
+		sessionID string
+		body      string
+		want      int
+	}{
+		{name: "missing session", body: `{"jsonrpc":"2.0","id":1,"method":"pid"}`, want: http.StatusBadRequest},
+		{name: "unknown session", sessionID: "unknown", body: `{"jsonrpc":"2.0","id":1,"method":"pid"}`, want: http.StatusNotFound},
+		{name: "invalid json", sessionID: sessionID, body: `{`, want: http.StatusBadRequest},
+		{name: "batched initialize", body: `[{"jsonrpc":"2.0","id":1,"method":"initialize"},{"jsonrpc":"2.0","id":2,"method":"pid"}]`, want: http.StatusBadRequest},
+		{name: "notification", sessionID: sessionID, body: `{"jsonrpc":"2.0","method":"notifications/progress"}`, want: http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp := post(t, server.URL, tt.sessionID, "application/json", tt.body); resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteSession(t *testing.T) {
+	server := newTestBridge(t, Options{Mode: PerSession})
+	sessionID := initialize(t, server.URL)
+
+	req, _ := http.NewRequest(http.MethodDelete, server.URL+DefaultPath, nil)
+	req.Header.Set(sessionHeader, sessionID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("DELETE status = %d, want 200", resp.StatusCode)
+	}
+
+	if resp := post(t, server.URL, sessionID, "application/json", `{"jsonrpc":"2.0","id":1,"method":"pid"}`); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status after DELETE = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestIdleTimeout(t *testing.T) {
+	server := newTestBridge(t, Options{Mode: PerSession, IdleTimeout: 200 * time.Millisecond})
+	idle := initialize(t, server.URL)
+	var pid int
+	if err := json.Unmarshal(callJSON(t, server.URL, idle, `{"jsonrpc":"2.0","id":1,"method":"pid"}`)["result"], &pid); err != nil {
+		t.Fatalf("invalid pid: %v", err)
+	}
+
+	// A session with an open stream is not idle
+	listening := initialize(t, server.URL)
+	req, _ := http.NewRequest(http.MethodGet, server.URL+DefaultPath, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(sessionHeader, listening)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Requests would keep the session alive, so watch its server instead
+	deadline := time.Now().Add(5 * time.Second)
+	for syscall.Kill(pid, 0) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("server of the idle session is still running")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if resp := post(t, server.URL, idle, "application/json", `{"jsonrpc":"2.0","id":2,"method":"pid"}`); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status of the idle session = %d, want 404", resp.StatusCode)
+	}
+
+	if _, err := send(server.URL, listening, `{"jsonrpc":"2.0","id":1,"method":"pid"}`); err != nil {
+		t.Errorf("session with an open stream was ended: %v", err)
+	}
+}
+
+func TestProcessExit(t *testing.T) {
+	server := newTestBridge(t, Options{Mode: Shared})
+	sessionID := initialize(t, server.URL)
+	pid := callJSON(t, server.URL, sessionID, `{"jsonrpc":"2.0","id":1,"method":"pid"}`)["result"]
+
+	msg := callJSON(t, server.URL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"exit"}`)
+	if msg["error"] == nil {
+		t.Errorf("exit response = %v, want an error", msg)
+	}
+
+	// The session ends with the server, and a new session starts a new server
+	deadline := time.Now().Add(5 * time.Second)
+	for post(t, server.URL, sessionID, "application/json", `{"jsonrpc":"2.0","id":3,"method":"pid"}`).StatusCode != http.StatusNotFound {
+		if time.Now().After(deadline) {
+			t.Fatal("session outlived its server")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	restarted := callJSON(t, server.URL, initialize(t, server.URL), `{"jsonrpc":"2.0","id":1,"method":"pid"}`)["result"]
+	if string(restarted) == string(pid) {
+		t.Errorf("pid after exit = %s, want a new server", restarted)
+	}
+}
+
+func TestSSETransport(t *testing.T) {
+	server := newTestBridge(t, Options{Mode: PerSession})
+
+	resp, err := http.Get(server.URL + SSEPath)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+	r := bufio.NewReader(resp.Body)
+
+	event, endpoint := readEvent(t, r)
+	if event != "endpoint" || !strings.HasPrefix(endpoint, MessagePath+"?sessionId=") {
+		t.Fatalf("first event = %s %s, want the endpoint", event, endpoint)
+	}
+
+	for i, body := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"echo","params":{"hello":"world"}}`,
+	} {
+		resp, err := http.Post(server.URL+endpoint, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("POST error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusAccepted {
+			t.Fatalf("POST status = %d, want 202", resp.StatusCode)
+		}
+
+		_, data := readEvent(t, r)
+		var msg map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			t.Fatalf("invalid event data %s: %v", data, err)
+		}
+		if string(msg["id"]) != fmt.Sprint(i+1) || msg["result"] == nil {
+			t.Errorf("event = %s, want the response to request %d", data, i+1)
+		}
+	}
+}
+
+func TestMCPProbeAgainstBridge(t *testing.T) {
+	server := newTestBridge(t, Options{Mode: PerSession})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	info, err := mcpprobe.Check(ctx, http.DefaultClient, server.URL+DefaultPath)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if info.Name != "toy" || info.ProtocolVersion != mcpprobe.ProtocolVersion {
+		t.Errorf("Check() = %+v", info)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Options{}); err == nil {
+		t.Error("New() without a command succeeded")
+	}
+	if _, err := New(Options{Command: []string{"server"}, Mode: "pooled"}); err == nil {
+		t.Error("New() with an invalid mode succeeded")
+	}
+	if _, err := New(Options{Command: []string{"server"}, IdleTimeout: -time.Second}); err == nil {
+		t.Error("New() with a negative idle timeout succeeded")
+	}
+}
@@ -0,0 +1,100 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// message is a JSON-RPC message. Fields are kept raw so that messages are
+// forwarded unchanged apart from the ids the bridge rewrites.
+type message map[string]json.RawMessage
+
+// method returns the method of a request or notification, or empty for a
+// response
+func (m message) method() string {
+	var method string
+	_ = json.Unmarshal(m["method"], &method)
+	return method
+}
+
+// hasID reports whether the message carries a non-null id
+func (m message) hasID() bool {
+	id, ok := m["id"]
+	return ok && string(id) != "null"
+}
+
+// isRequest reports whether the message is a request, which expects a response
+func (m message) isRequest() bool {
+	return m.method() != "" && m.hasID()
+}
+
+// isResponse reports whether the message is a response to a request
+func (m message) isResponse() bool {
+	return m.method() == "" && m.hasID()
+}
+
+// clone returns a shallow copy of the message
+func (m message) clone() message {
+	out := make(message, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// withID returns a copy of the message with its id replaced
+func (m message) withID(id json.RawMessage) message {
+	out := m.clone()
+	out["id"] = id
+	return out
+}
+
+// errorResponse returns a JSON-RPC error response for the request id
+func errorResponse(id json.RawMessage, code int, msg string) message {
+	errData, _ := json.Marshal(map[string]interface{}{"code": code, "message": msg})
+	return message{
+		"jsonrpc": json.RawMessage(`"2.0"`),
+		"id":      id,
+		"error":   errData,
+	}
+}
+
+// JSON-RPC error codes used by the bridge
+const (
+	codeParseError    = -32700
+	codeInvalidReq    = -32600
+	codeInternalError = -32603
+)
+
+// parseMessages parses the body of a POST, which is a single message or a
+// batch of messages
+func parseMessages(body []byte) ([]message, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, errors.New("empty body")
+	}
+
+	var msgs []message
+	if body[0] == '[' {
+		if err := json.Unmarshal(body, &msgs); err != nil {
+			return nil, err
+		}
+		if len(msgs) == 0 {
+			return nil, errors.New("empty batch")
+		}
+	} else {
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return nil, err
+		}
+		msgs = []message{msg}
+	}
+
+	for _, msg := range msgs {
+		if msg.method() == "" && !msg.hasID() {
+			return nil, errors.New("message is neither a request, a notification nor a response")
+		}
+	}
+	return msgs, nil
+}
@@ -0,0 +1,346 @@
+package bridge
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"strconv"
+	"sync"
+)
+
+// maxMessageSize bounds a single line written by the server
+const maxMessageSize = 16 * 1024 * 1024
+
+// call is a request forwarded to the server and waiting for its response
+type call struct {
+	session *session
+
+	// id is the id the client gave the request; the server sees a bridge id
+	id json.RawMessage
+
+	// stream receives the response
+	stream *stream
+
+	// onResponse, if set, sees the response before it is delivered
+	onResponse func(message)
+}
+
+// process is a running stdio MCP server and the sessions attached to it. Every
+// request is given an id unique to the process, so that sessions sharing a
+// process cannot confuse each other's responses.
+type process struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	logger *log.Logger
+
+	writeMu sync.Mutex
+
+	mu         sync.Mutex
+	nextID     int64
+	calls      map[int64]*call
+	sessions   map[*session]bool
+	lastActive *session
+	exited     bool
+
+	// The server is initialized once; later sessions get the same answer
+	initStarted     bool
+	initOnce        sync.Once
+	initDone        chan struct{}
+	initResponse    message
+	initializedSent bool
+
+	// onExit is called with the sessions attached when the server exits
+	onExit func(sessions []*session)
+}
+
+// startProcess starts the server command with its standard error passed
+// through to stderr
+func startProcess(command []string, stderr io.Writer, logger *log.Logger, onExit func([]*session)) (*process, error) {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stderr = stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", command[0], err)
+	}
+
+	p := &process{
+		cmd:      cmd,
+		stdin:    stdin,
+		logger:   logger,
+		nextID:   1,
+		calls:    make(map[int64]*call),
+		sessions: make(map[*session]bool),
+		initDone: make(chan struct{}),
+		onExit:   onExit,
+	}
+	go p.readLoop(stdout)
+	return p, nil
+}
+
+// attach adds a session to the process, or returns false if it has exited
+func (p *process) attach(s *session) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.exited {
+		return false
+	}
+	p.sessions[s] = true
+	return true
+}
+
+// detach removes a session and returns the number of sessions left
+func (p *process) detach(s *session) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.sessions, s)
+	for id, c := range p.calls {
+		if c.session == s {
+			delete(p.calls, id)
+		}
+	}
+	if p.lastActive == s {
+		p.lastActive = nil
+	}
+	return len(p.sessions)
+}
+
+// kill stops the server
+func (p *process) kill() {
+	p.stdin.Close()
+	if p.cmd.Process != nil {
+		_ = p.cmd.Process.Kill()
+	}
+}
+
+// forward sends a message from a session to the server. Responses to requests
+// are delivered to the stream.
+func (p *process) forward(s *session, msg message, st *stream) {
+	switch {
+	case msg.isRequest() && msg.method() == "initialize":
+		p.initialize(s, msg, st)
+	case msg.isRequest():
+		p.call(s, msg, st, nil)
+	case msg.method() == "notifications/initialized":
+		p.mu.Lock()
+		sent := p.initializedSent
+		p.initializedSent = true
+		p.mu.Unlock()
+		if !sent {
+			p.write(msg)
+		}
+	case msg.method() == "notifications/cancelled":
+		p.write(p.cancelled(s, msg))
+	default:
+		p.write(msg)
+	}
+}
+
+// initialize forwards the first initialize request and answers later ones
+// with the same result, since a server can only be initialized once
+func (p *process) initialize(s *session, msg message, st *stream) {
+	p.mu.Lock()
+	if !p.initStarted {
+		p.initStarted = true
+		p.mu.Unlock()
+		p.call(s, msg, st, p.finishInit)
+		return
+	}
+	p.mu.Unlock()
+	if st == nil {
+		return
+	}
+
+	go func() {
+		select {
+		case <-p.initDone:
+		case <-st.done:
+			return
+		}
+		p.mu.Lock()
+		resp := p.initResponse
+		p.mu.Unlock()
+		st.send(resp.withID(msg["id"]))
+	}()
+}
+
+// finishInit records the answer to the first initialize request and releases
+// the sessions waiting for it
+func (p *process) finishInit(resp message) {
+	p.initOnce.Do(func() {
+		p.mu.Lock()
+		p.initResponse = resp
+		p.mu.Unlock()
+		close(p.initDone)
+	})
+}
+
+// call forwards a request under a bridge id
+func (p *process) call(s *session, msg message, st *stream, onResponse func(message)) {
+	p.mu.Lock()
+	if p.exited {
+		p.mu.Unlock()
+		st.send(errorResponse(msg["id"], codeInternalError, "MCP server process has exited"))
+		return
+	}
+	id := p.nextID
+	p.nextID++
+	p.calls[id] = &call{session: s, id: msg["id"], stream: st, onResponse: onResponse}
+	s.inflight[string(msg["id"])] = id
+	p.lastActive = s
+	p.mu.Unlock()
+
+	if err := p.write(msg.withID(json.RawMessage(strconv.FormatInt(id, 10)))); err != nil {
+		p.mu.Lock()
+		delete(p.calls, id)
+		delete(s.inflight, string(msg["id"]))
+		p.mu.Unlock()
+		st.send(errorResponse(msg["id"], codeInternalError, fmt.Sprintf("failed to send request: %v", err)))
+	}
+}
+
+// cancelled rewrites the request id of a cancellation to the bridge id
+func (p *process) cancelled(s *session, msg message) message {
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(msg["params"], &params); err != nil {
+		return msg
+	}
+	p.mu.Lock()
+	id, ok := s.inflight[string(params["requestId"])]
+	p.mu.Unlock()
+	if !ok {
+		return msg
+	}
+
+	params["requestId"] = json.RawMessage(strconv.FormatInt(id, 10))
+	data, err := json.Marshal(params)
+	if err != nil {
+		return msg
+	}
+	out := msg.clone()
+	out["params"] = data
+	return out
+}
+
+// write sends a message to the server as a single line
+func (p *process) write(msg message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	_, err = p.stdin.Write(append(data, '\n'))
+	return err
+}
+
+// readLoop routes the messages the server writes until it exits
+func (p *process) readLoop(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	for scanner.Scan() {
+		var msg message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			// Servers sometimes log to stdout; pass it on rather than fail
+			p.logger.Printf("server: %s", scanner.Bytes())
+			continue
+		}
+		if msg.isResponse() {
+			p.respond(msg)
+		} else {
+			p.route(msg)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		p.logger.Printf("failed to read from server: %v", err)
+	}
+	p.exit()
+}
+
+// respond delivers a response to the session that made the request, under the
+// id the client gave it
+func (p *process) respond(msg message) {
+	var id int64
+	if err := json.Unmarshal(msg["id"], &id); err != nil {
+		p.logger.Printf("response with unknown id %s", msg["id"])
+		return
+	}
+
+	p.mu.Lock()
+	c, ok := p.calls[id]
+	if ok {
+		delete(p.calls, id)
+		delete(c.session.inflight, string(c.id))
+	}
+	p.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	resp := msg.withID(c.id)
+	if c.onResponse != nil {
+		c.onResponse(resp)
+	}
+	c.stream.send(resp)
+}
+
+// route delivers a request or notification from the server. Notifications go
+// to every session; requests go to the session that was active last.
+func (p *process) route(msg message) {
+	p.mu.Lock()
+	var targets []*session
+	if msg.hasID() {
+		if p.lastActive != nil {
+			targets = append(targets, p.lastActive)
+		}
+	} else {
+		for s := range p.sessions {
+			targets = append(targets, s)
+		}
+	}
+	p.mu.Unlock()
+
+	if len(targets) == 0 && msg.hasID() {
+		p.write(errorResponse(msg["id"], codeInternalError, "no client is connected"))
+		return
+	}
+	for _, s := range targets {
+		s.push(msg)
+	}
+}
+
+// exit fails the requests in flight and detaches every session once the
+// server has exited
+func (p *process) exit() {
+	err := p.cmd.Wait()
+
+	p.mu.Lock()
+	p.exited = true
+	calls := p.calls
+	p.calls = make(map[int64]*call)
+	sessions := make([]*session, 0, len(p.sessions))
+	for s := range p.sessions {
+		sessions = append(sessions, s)
+	}
+	p.mu.Unlock()
+
+	if err != nil {
+		p.logger.Printf("server exited: %v", err)
+	}
+	for _, c := range calls {
+		c.stream.send(errorResponse(c.id, codeInternalError, "MCP server process exited"))
+	}
+	p.finishInit(errorResponse(nil, codeInternalError, "MCP server process exited"))
+	if p.onExit != nil {
+		p.onExit(sessions)
+	}
+}
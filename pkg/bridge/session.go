@@ -0,0 +1,174 @@
+package bridge
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// session is a client session, attached to a server process
+type session struct {
+	id   string
+	proc *process
+
+	mu        sync.Mutex
+	listening *stream
+	streams   []*stream
+
+	// lastActive is when the client last made a request or closed a stream
+	lastActive time.Time
+
+	// inflight maps the ids of the requests the client has in flight to
+	// bridge ids; it is guarded by the mutex of the process
+	inflight map[string]int64
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newSession(id string, proc *process) *session {
+	return &session{
+		id:         id,
+		proc:       proc,
+		inflight:   make(map[string]int64),
+		done:       make(chan struct{}),
+		lastActive: time.Now(),
+	}
+}
+
+// touch records a request of the client
+func (s *session) touch() {
+	s.mu.Lock()
+	s.lastActive = time.Now()
+	s.mu.Unlock()
+}
+
+// idle reports whether the session has had no requests and no open streams
+// for the timeout
+func (s *session) idle(timeout time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listening == nil && len(s.streams) == 0 && time.Since(s.lastActive) >= timeout
+}
+
+// close ends the streams of the session
+func (s *session) close() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+// openStream returns a stream for the responses to a POST
+func (s *session) openStream() *stream {
+	st := newStream()
+	s.mu.Lock()
+	s.streams = append(s.streams, st)
+	s.mu.Unlock()
+	return st
+}
+
+// closeStream closes a stream returned by openStream
+func (s *session) closeStream(st *stream) {
+	s.mu.Lock()
+	for i, other := range s.streams {
+		if other == st {
+			s.streams = append(s.streams[:i], s.streams[i+1:]...)
+			break
+		}
+	}
+	s.lastActive = time.Now()
+	s.mu.Unlock()
+	st.close()
+}
+
+// listen opens the stream for messages sent outside of a request, or returns
+// false if it is already open
+func (s *session) listen() (*stream, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listening != nil {
+		return nil, false
+	}
+	s.listening = newStream()
+	return s.listening, true
+}
+
+// stopListening closes a stream returned by listen
+func (s *session) stopListening(st *stream) {
+	s.mu.Lock()
+	if s.listening == st {
+		s.listening = nil
+	}
+	s.lastActive = time.Now()
+	s.mu.Unlock()
+	st.close()
+}
+
+// listener returns the open listening stream, or nil
+func (s *session) listener() *stream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listening
+}
+
+// push delivers a message the server sent outside of a response. It goes to
+// the listening stream, or else to the stream of the latest POST; without
+// either the client cannot receive it, and it is dropped.
+func (s *session) push(msg message) {
+	s.mu.Lock()
+	st := s.listening
+	if st == nil && len(s.streams) > 0 {
+		st = s.streams[len(s.streams)-1]
+	}
+	s.mu.Unlock()
+	st.send(msg)
+}
+
+// stream carries messages from the server to an HTTP response
+type stream struct {
+	ch        chan message
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newStream() *stream {
+	return &stream{
+		ch:   make(chan message, streamBuffer),
+		done: make(chan struct{}),
+	}
+}
+
+// send queues a message, waiting while the stream is full. Sending to a nil
+// or closed stream does nothing.
+func (st *stream) send(msg message) {
+	if st == nil {
+		return
+	}
+	select {
+	case st.ch <- msg:
+	case <-st.done:
+	}
+}
+
+// next returns the next message, or false once the request is cancelled or
+// the session ends
+func (st *stream) next(r *http.Request, s *session) (message, bool) {
+	select {
+	case msg := <-st.ch:
+		return msg, true
+	case <-r.Context().Done():
+		return nil, false
+	case <-s.done:
+		// Deliver what was sent before the session ended, such as the errors
+		// for requests in flight when the server exited
+		select {
+		case msg := <-st.ch:
+			return msg, true
+		default:
+			return nil, false
+		}
+	}
+}
+
+// close stops the stream from accepting messages
+func (st *stream) close() {
+	st.closeOnce.Do(func() { close(st.done) })
+}
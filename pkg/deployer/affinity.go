@@ -0,0 +1,246 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// DefaultRouterImage is the image providing the mcp-router binary, built
+	// from cmd/mcp-router
+	DefaultRouterImage = "ghcr.io/grs/mcp-deployment/mcp-router:latest"
+
+	// RouterPort is the port mcp-router listens on in the pods of servers
+	// with AffinityRouter. Their Service targets it instead of the mcp port.
+	RouterPort = 8090
+
+	// peersSuffix is appended to the name of a server with AffinityRouter for
+	// the headless Service through which the routers find each other
+	peersSuffix = "-peers"
+
+	// defaultAffinityTimeout is the client IP affinity timeout used when the
+	// spec sets none, the default of Kubernetes
+	defaultAffinityTimeout = 10800
+
+	// maxAffinityTimeout is the longest client IP affinity timeout Kubernetes
+	// accepts
+	maxAffinityTimeout = 86400
+)
+
+// affinityMode returns the session affinity mode of the spec
+func affinityMode(spec *MCPServerSpec) SessionAffinityMode {
+	if spec.SessionAffinity == nil || spec.SessionAffinity.Mode == "" {
+		return AffinityNone
+	}
+	return spec.SessionAffinity.Mode
+}
+
+// usesRouter reports whether the server runs behind mcp-router
+func usesRouter(spec *MCPServerSpec) bool {
+	return affinityMode(spec) == AffinityRouter
+}
+
+// peersServiceName returns the name of the headless Service of a server with
+// AffinityRouter
+func peersServiceName(name string) string {
+	return name + peersSuffix
+}
+
+// serviceServer returns the name of the MCP server a Service belongs to, from
+// its instance label or else its controller, or "" if it belongs to none
+func serviceServer(service *corev1.Service) string {
+	if name := service.Labels[InstanceLabel]; name != "" {
+		return name
+	}
+	if owner := metav1.GetControllerOf(service); owner != nil {
+		return owner.Name
+	}
+	return ""
+}
+
+// checkServiceNames fails with ErrServerExists if the Service of the server,
+// or its headless Service if it uses AffinityRouter, already belongs to
+// another server. A routed server "x" and a server named "x-peers" would
+// otherwise take over each other's Service.
+func (d *SimpleDeployer) checkServiceNames(ctx context.Context, spec *MCPServerSpec) error {
+	names := []string{spec.Name}
+	if usesRouter(spec) {
+		names = append(names, peersServiceName(spec.Name))
+	}
+
+	for _, name := range names {
+		existing, err := d.clientset.CoreV1().Services(spec.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get service: %w", mapAPIError(err, spec.Namespace, name))
+		}
+		if err := checkServiceServer(existing, spec.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkServiceServer fails with ErrServerExists if the Service belongs to an
+// MCP server other than the named one
+func checkServiceServer(service *corev1.Service, server string) error {
+	if other := serviceServer(service); other != "" && other != server {
+		return fmt.Errorf("service %s/%s belongs to MCP server %q: %w", service.Namespace, service.Name, other, ErrServerExists)
+	}
+	return nil
+}
+
+// serviceAffinity returns the session affinity of the Service of a server
+func serviceAffinity(spec *MCPServerSpec) (corev1.ServiceAffinity, *corev1.SessionAffinityConfig) {
+	if affinityMode(spec) != AffinityClientIP {
+		return "", nil
+	}
+	timeout := valueOrDefault(spec.SessionAffinity.TimeoutSeconds, defaultAffinityTimeout)
+	return corev1.ServiceAffinityClientIP, &corev1.SessionAffinityConfig{
+		ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeout},
+	}
+}
+
+// serviceTargetPort returns the port the Service of a server targets: the
+// router if there is one, otherwise the server
+func serviceTargetPort(spec *MCPServerSpec) intstr.IntOrString {
+	if usesRouter(spec) {
+		return intstr.FromString("mcp-router")
+	}
+	return intstr.FromInt(int(spec.Port))
+}
+
+// routerContainer returns the mcp-router sidecar of a server with
+// AffinityRouter. The router has requests of its own, since autoscaling on
+// utilization needs every container of the pod to request what is measured.
+func routerContainer(spec *MCPServerSpec) corev1.Container {
+	image := spec.SessionAffinity.RouterImage
+	if image == "" {
+		image = DefaultRouterImage
+	}
+	return corev1.Container{
+		Name:  "mcp-router",
+		Image: image,
+		Args: []string{
+			"--port", strconv.Itoa(RouterPort),
+			"--backend", fmt.Sprintf("http://127.0.0.1:%d", spec.Port),
+			"--peers", peersServiceName(spec.Name),
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name: "POD_IP",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+				},
+			},
+		},
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "mcp-router",
+				ContainerPort: RouterPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+				corev1.ResourceMemory: resource.MustParse("16Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromString("mcp-router")},
+			},
+			PeriodSeconds:    readinessDefaults.periodSeconds,
+			TimeoutSeconds:   1,
+			FailureThreshold: readinessDefaults.failureThreshold,
+		},
+	}
+}
+
+// buildPeersService builds the headless Service listing the pods of a server
+// with AffinityRouter to mcp-router. It selects them by their instance label,
+// so that sessions are never routed to the pods of another server. Pods that
+// are not ready are listed too, so that their sessions are still routed to
+// them.
+func (d *SimpleDeployer) buildPeersService(spec *MCPServerSpec) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        peersServiceName(spec.Name),
+			Namespace:   spec.Namespace,
+			Labels:      d.mergeLabels(spec),
+			Annotations: d.mergeAnnotations(spec),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector:  selectorLabels(spec),
+			ClusterIP: corev1.ClusterIPNone,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "mcp-router",
+					Port:       RouterPort,
+					TargetPort: intstr.FromString("mcp-router"),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+			PublishNotReadyAddresses: true,
+		},
+	}
+}
+
+// createPeersService creates the headless Service of a server with
+// AffinityRouter, owned by its Deployment
+func (d *SimpleDeployer) createPeersService(ctx context.Context, spec *MCPServerSpec, deployment *appsv1.Deployment, opts metav1.CreateOptions) (*corev1.Service, error) {
+	service := d.buildPeersService(spec)
+	service.OwnerReferences = d.ownerReferences(deployment)
+
+	created, err := d.clientset.CoreV1().Services(spec.Namespace).Create(ctx, service, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create peers service: %w", mapAPIError(err, spec.Namespace, service.Name))
+	}
+
+	return created, nil
+}
+
+// removePeersService deletes the headless Service of an MCP server that no
+// longer uses AffinityRouter, and reports whether there was one. Services that
+// do not carry the instance label of the server or are not controlled by its
+// owner, such as the Service of a server named like a peers Service, are left
+// alone.
+func (d *SimpleDeployer) removePeersService(ctx context.Context, namespace, server string, owner metav1.Object, opts metav1.DeleteOptions) (bool, error) {
+	services := d.clientset.CoreV1().Services(namespace)
+	name := peersServiceName(server)
+
+	existing, err := services.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get peers service: %w", mapAPIError(err, namespace, name))
+	}
+	if existing.Labels[InstanceLabel] != server || !metav1.IsControlledBy(existing, owner) {
+		return false, nil
+	}
+
+	err = services.Delete(ctx, name, opts)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to delete peers service: %w", mapAPIError(err, namespace, name))
+	}
+	return err == nil, nil
+}
@@ -0,0 +1,236 @@
+package deployer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// routedSpec returns the test spec with router session affinity
+func routedSpec() *MCPServerSpec {
+	spec := testSpec()
+	spec.Replicas = int32Ptr(3)
+	spec.SessionAffinity = &SessionAffinity{Mode: AffinityRouter}
+	return spec
+}
+
+func TestBuildServiceSessionAffinity(t *testing.T) {
+	tests := []struct {
+		name        string
+		affinity    *SessionAffinity
+		wantType    corev1.ServiceAffinity
+		wantTimeout int32
+		wantTarget  intstr.IntOrString
+	}{
+		{name: "default", wantTarget: intstr.FromInt(8080)},
+		{name: "none", affinity: &SessionAffinity{Mode: AffinityNone}, wantTarget: intstr.FromInt(8080)},
+		{
+			name:        "client ip",
+			affinity:    &SessionAffinity{Mode: AffinityClientIP},
+			wantType:    corev1.ServiceAffinityClientIP,
+			wantTimeout: 10800,
+			wantTarget:  intstr.FromInt(8080),
+		},
+		{
+			name:        "client ip with a timeout",
+			affinity:    &SessionAffinity{Mode: AffinityClientIP, TimeoutSeconds: 600},
+			wantType:    corev1.ServiceAffinityClientIP,
+			wantTimeout: 600,
+			wantTarget:  intstr.FromInt(8080),
+		},
+		{name: "router", affinity: &SessionAffinity{Mode: AffinityRouter}, wantTarget: intstr.FromString("mcp-router")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := testSpec()
+			spec.SessionAffinity = tt.affinity
+
+			var d SimpleDeployer
+			service := d.buildService(spec)
+			if service.Spec.SessionAffinity != tt.wantType {
+				t.Errorf("session affinity = %q, want %q", service.Spec.SessionAffinity, tt.wantType)
+			}
+			config := service.Spec.SessionAffinityConfig
+			switch {
+			case tt.wantTimeout == 0 && config != nil:
+				t.Errorf("session affinity config = %+v, want none", config)
+			case tt.wantTimeout != 0 && (config == nil || config.ClientIP == nil || config.ClientIP.TimeoutSeconds == nil):
+				t.Errorf("session affinity config = %+v, want a client IP timeout", config)
+			case tt.wantTimeout != 0 && *config.ClientIP.TimeoutSeconds != tt.wantTimeout:
+				t.Errorf("timeout = %d, want %d", *config.ClientIP.TimeoutSeconds, tt.wantTimeout)
+			}
+			if got := service.Spec.Ports[0]; got.Port != 8080 || got.TargetPort != tt.wantTarget {
+				t.Errorf("port = %d -> %s, want 8080 -> %s", got.Port, got.TargetPort.String(), tt.wantTarget.String())
+			}
+		})
+	}
+}
+
+func TestBuildDeploymentRouter(t *testing.T) {
+	spec := routedSpec()
+	spec.SessionAffinity.RouterImage = "example/mcp-router:1.0"
+
+	var d SimpleDeployer
+	containers := d.buildDeployment(spec).Spec.Template.Spec.Containers
+	if len(containers) != 2 || containers[0].Name != "mcp-server" {
+		t.Fatalf("containers = %+v, want the server and the router", containers)
+	}
+
+	router := containers[1]
+	if router.Name != "mcp-router" || router.Image != "example/mcp-router:1.0" {
+		t.Errorf("router = %s %s, want mcp-router example/mcp-router:1.0", router.Name, router.Image)
+	}
+	wantArgs := []string{"--port", "8090", "--backend", "http://127.0.0.1:8080", "--peers", "test-server-peers"}
+	if len(router.Args) != len(wantArgs) {
+		t.Fatalf("router args = %v, want %v", router.Args, wantArgs)
+	}
+	for i := range wantArgs {
+		if router.Args[i] != wantArgs[i] {
+			t.Errorf("router args = %v, want %v", router.Args, wantArgs)
+			break
+		}
+	}
+	if len(router.Env) != 1 || router.Env[0].ValueFrom == nil || router.Env[0].ValueFrom.FieldRef.FieldPath != "status.podIP" {
+		t.Errorf("router env = %+v, want POD_IP from status.podIP", router.Env)
+	}
+	if router.Resources.Requests.Cpu().IsZero() || router.Resources.Requests.Memory().IsZero() {
+		t.Errorf("router requests = %v, want cpu and memory", router.Resources.Requests)
+	}
+
+	spec.SessionAffinity = &SessionAffinity{Mode: AffinityClientIP}
+	if containers := d.buildDeployment(spec).Spec.Template.Spec.Containers; len(containers) != 1 {
+		t.Errorf("containers with client ip affinity = %d, want only the server", len(containers))
+	}
+}
+
+func TestDeployMCPServerRouted(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	d := NewSimpleDeployer(clientset)
+	spec := routedSpec()
+
+	if err := d.DeployMCPServer(context.Background(), spec); err != nil {
+		t.Fatalf("DeployMCPServer() error = %v", err)
+	}
+
+	deployment, err := clientset.AppsV1().Deployments(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	peers, err := clientset.CoreV1().Services(spec.Namespace).Get(context.Background(), "test-server-peers", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get peers service: %v", err)
+	}
+	assertOwnedByDeployment(t, peers, deployment)
+	if peers.Spec.ClusterIP != corev1.ClusterIPNone || !peers.Spec.PublishNotReadyAddresses {
+		t.Errorf("peers service spec = %+v, want headless and listing pods that are not ready", peers.Spec)
+	}
+	if peers.Spec.Selector[InstanceLabel] != spec.Name || len(peers.Spec.Ports) != 1 || peers.Spec.Ports[0].Port != RouterPort {
+		t.Errorf("peers service spec = %+v, want the router port of the server pods", peers.Spec)
+	}
+}
+
+func TestPeersServiceSelectsOwnPods(t *testing.T) {
+	var d SimpleDeployer
+	a, b := routedSpec(), routedSpec()
+	a.Name, b.Name = "a", "b"
+
+	selector := labels.SelectorFromSet(d.buildPeersService(a).Spec.Selector)
+	if !selector.Matches(labels.Set(d.buildDeployment(a).Spec.Template.Labels)) {
+		t.Error("peers service of a does not select the pods of a")
+	}
+	if selector.Matches(labels.Set(d.buildDeployment(b).Spec.Template.Labels)) {
+		t.Error("peers service of a selects the pods of b, which has the same labels")
+	}
+}
+
+func TestApplyMCPServerSessionAffinity(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("patch", "*", applyReactor(clientset))
+	d := NewSimpleDeployer(clientset)
+	spec := routedSpec()
+	services := clientset.CoreV1().Services(spec.Namespace)
+
+	if _, err := d.ApplyMCPServer(context.Background(), spec); err != nil {
+		t.Fatalf("ApplyMCPServer() error = %v", err)
+	}
+	if _, err := services.Get(context.Background(), "test-server-peers", metav1.GetOptions{}); err != nil {
+		t.Fatalf("failed to get peers service: %v", err)
+	}
+
+	changed, err := d.ApplyMCPServer(context.Background(), spec)
+	if err != nil || changed {
+		t.Errorf("repeated ApplyMCPServer() = %v, %v, want unchanged", changed, err)
+	}
+
+	spec.SessionAffinity = &SessionAffinity{Mode: AffinityClientIP}
+	changed, err = d.ApplyMCPServer(context.Background(), spec)
+	if err != nil || !changed {
+		t.Fatalf("ApplyMCPServer() with client ip affinity = %v, %v, want changed", changed, err)
+	}
+	if _, err := services.Get(context.Background(), "test-server-peers", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("peers service still present: %v", err)
+	}
+	service, err := services.Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get service: %v", err)
+	}
+	if service.Spec.SessionAffinity != corev1.ServiceAffinityClientIP || service.Spec.Ports[0].TargetPort != intstr.FromInt(8080) {
+		t.Errorf("service spec = %+v, want client ip affinity targeting the server", service.Spec)
+	}
+}
+
+func TestApplyMCPServerPeersServiceNameCollision(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("patch", "*", applyReactor(clientset))
+	d := NewSimpleDeployer(clientset)
+	services := clientset.CoreV1().Services("test-ns")
+
+	other := testSpec()
+	other.Name = "x-peers"
+	if _, err := d.ApplyMCPServer(context.Background(), other); err != nil {
+		t.Fatalf("ApplyMCPServer(x-peers) error = %v", err)
+	}
+
+	spec := testSpec()
+	spec.Name = "x"
+	if _, err := d.ApplyMCPServer(context.Background(), spec); err != nil {
+		t.Fatalf("ApplyMCPServer(x) error = %v", err)
+	}
+	if _, err := services.Get(context.Background(), "x-peers", metav1.GetOptions{}); err != nil {
+		t.Fatalf("service of x-peers removed with x: %v", err)
+	}
+
+	spec.SessionAffinity = &SessionAffinity{Mode: AffinityRouter}
+	if _, err := d.ApplyMCPServer(context.Background(), spec); !errors.Is(err, ErrServerExists) {
+		t.Errorf("ApplyMCPServer(x) with router affinity error = %v, want ErrServerExists", err)
+	}
+	service, err := services.Get(context.Background(), "x-peers", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get service of x-peers: %v", err)
+	}
+	if service.Spec.Selector[InstanceLabel] != "x-peers" || service.Spec.ClusterIP == corev1.ClusterIPNone {
+		t.Errorf("service of x-peers = %+v, want it unchanged", service.Spec)
+	}
+
+	routed := routedSpec()
+	routed.Name = "y"
+	if _, err := d.ApplyMCPServer(context.Background(), routed); err != nil {
+		t.Fatalf("ApplyMCPServer(y) error = %v", err)
+	}
+	peers := testSpec()
+	peers.Name = "y-peers"
+	if _, err := d.ApplyMCPServer(context.Background(), peers); !errors.Is(err, ErrServerExists) {
+		t.Errorf("ApplyMCPServer(y-peers) error = %v, want ErrServerExists", err)
+	}
+	if _, err := clientset.AppsV1().Deployments("test-ns").Get(context.Background(), "y-peers", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("deployment y-peers created: %v", err)
+	}
+}
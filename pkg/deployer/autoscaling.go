@@ -0,0 +1,168 @@
+package deployer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// deploymentReplicas returns the replicas of the Deployment of an MCP server.
+// Autoscaled servers leave them unset, so that applying the Deployment does
+// not undo the scaling done by the HorizontalPodAutoscaler.
+func deploymentReplicas(spec *MCPServerSpec) *int32 {
+	if spec.Autoscaling != nil {
+		return nil
+	}
+	replicas := int32(1)
+	if spec.Replicas != nil {
+		replicas = *spec.Replicas
+	}
+	return &replicas
+}
+
+// minReplicas returns the minimum number of replicas of an autoscaled server
+func (a *Autoscaling) minReplicas() int32 {
+	if a.Min == 0 {
+		return 1
+	}
+	return a.Min
+}
+
+// buildHorizontalPodAutoscaler builds the HorizontalPodAutoscaler scaling the
+// Deployment of an autoscaled MCP server
+func (d *SimpleDeployer) buildHorizontalPodAutoscaler(spec *MCPServerSpec) *autoscalingv2.HorizontalPodAutoscaler {
+	autoscaling := spec.Autoscaling
+	minReplicas := autoscaling.minReplicas()
+
+	var metrics []autoscalingv2.MetricSpec
+	for _, target := range []struct {
+		resource    corev1.ResourceName
+		utilization int32
+	}{
+		{corev1.ResourceCPU, autoscaling.TargetCPU},
+		{corev1.ResourceMemory, autoscaling.TargetMemory},
+	} {
+		if target.utilization == 0 {
+			continue
+		}
+		utilization := target.utilization
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: target.resource,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: &utilization,
+				},
+			},
+		})
+	}
+
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "autoscaling/v2",
+			Kind:       "HorizontalPodAutoscaler",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        spec.Name,
+			Namespace:   spec.Namespace,
+			Labels:      d.mergeLabels(spec),
+			Annotations: d.mergeAnnotations(spec),
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       spec.Name,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: autoscaling.Max,
+			Metrics:     metrics,
+		},
+	}
+}
+
+// createHorizontalPodAutoscaler creates the HorizontalPodAutoscaler for an
+// autoscaled MCP server, owned by its Deployment
+func (d *SimpleDeployer) createHorizontalPodAutoscaler(ctx context.Context, spec *MCPServerSpec, deployment *appsv1.Deployment, opts metav1.CreateOptions) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	hpa := d.buildHorizontalPodAutoscaler(spec)
+	hpa.OwnerReferences = d.ownerReferences(deployment)
+
+	created, err := d.clientset.AutoscalingV2().HorizontalPodAutoscalers(spec.Namespace).Create(ctx, hpa, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create horizontal pod autoscaler: %w", mapAPIError(err, spec.Namespace, spec.Name))
+	}
+
+	return created, nil
+}
+
+// applyHorizontalPodAutoscaler server-side applies a HorizontalPodAutoscaler
+// built for the MCP server and reports whether the stored object changed
+func (d *SimpleDeployer) applyHorizontalPodAutoscaler(ctx context.Context, hpa *autoscalingv2.HorizontalPodAutoscaler, opts metav1.PatchOptions) (bool, error) {
+	data, err := json.Marshal(hpa)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode horizontal pod autoscaler: %w", err)
+	}
+
+	namespace, name := hpa.Namespace, hpa.Name
+	hpas := d.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace)
+
+	var resourceVersion string
+	existing, err := hpas.Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		resourceVersion = existing.ResourceVersion
+	} else if !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get horizontal pod autoscaler: %w", mapAPIError(err, namespace, name))
+	}
+
+	applied, err := hpas.Patch(ctx, name, types.ApplyPatchType, data, opts)
+	if err != nil {
+		return false, mapAPIError(err, namespace, name)
+	}
+
+	return applyChanged(existing, applied, resourceVersion, opts), nil
+}
+
+// removeHorizontalPodAutoscaler deletes the HorizontalPodAutoscaler of an MCP
+// server that is no longer autoscaled, and reports whether there was one.
+// HorizontalPodAutoscalers without the MCP server label are left alone.
+func (d *SimpleDeployer) removeHorizontalPodAutoscaler(ctx context.Context, namespace, name string, opts metav1.DeleteOptions) (bool, error) {
+	hpas := d.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace)
+
+	existing, err := hpas.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get horizontal pod autoscaler: %w", mapAPIError(err, namespace, name))
+	}
+	if existing.Labels[MCPServerLabel] != "true" {
+		return false, nil
+	}
+
+	err = hpas.Delete(ctx, name, opts)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to delete horizontal pod autoscaler: %w", mapAPIError(err, namespace, name))
+	}
+	return err == nil, nil
+}
+
+// autoscaled reports whether the replicas of an MCP server are managed by the
+// HorizontalPodAutoscaler of the server
+func (d *SimpleDeployer) autoscaled(ctx context.Context, namespace, name string) (bool, error) {
+	hpa, err := d.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get horizontal pod autoscaler: %w", mapAPIError(err, namespace, name))
+	}
+	return hpa.Labels[MCPServerLabel] == "true", nil
+}
@@ -0,0 +1,258 @@
+package deployer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+// autoscaledSpec returns the test spec scaled on CPU and memory
+func autoscaledSpec() *MCPServerSpec {
+	spec := testSpec()
+	spec.Resources.Requests = corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("100m"),
+	}
+	spec.Autoscaling = &Autoscaling{Max: 5, TargetCPU: 70, TargetMemory: 80}
+	return spec
+}
+
+func TestDeploymentReplicas(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(spec *MCPServerSpec)
+		want   *int32
+	}{
+		{name: "default", modify: func(spec *MCPServerSpec) {}, want: int32Ptr(1)},
+		{name: "replicas", modify: func(spec *MCPServerSpec) { spec.Replicas = int32Ptr(3) }, want: int32Ptr(3)},
+		{name: "scaled to zero", modify: func(spec *MCPServerSpec) { spec.Replicas = int32Ptr(0) }, want: int32Ptr(0)},
+		{name: "autoscaled", modify: func(spec *MCPServerSpec) { spec.Autoscaling = &Autoscaling{Max: 3} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := testSpec()
+			tt.modify(spec)
+
+			var d SimpleDeployer
+			got := d.buildDeployment(spec).Spec.Replicas
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("replicas = %d, want unset", *got)
+			case tt.want != nil && (got == nil || *got != *tt.want):
+				t.Errorf("replicas = %v, want %d", got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildHorizontalPodAutoscaler(t *testing.T) {
+	var d SimpleDeployer
+	hpa := d.buildHorizontalPodAutoscaler(autoscaledSpec())
+
+	if hpa.Name != "test-server" || hpa.Namespace != "test-ns" || hpa.Labels[MCPServerLabel] != "true" {
+		t.Errorf("metadata = %+v", hpa.ObjectMeta)
+	}
+	target := hpa.Spec.ScaleTargetRef
+	if target.APIVersion != "apps/v1" || target.Kind != "Deployment" || target.Name != "test-server" {
+		t.Errorf("scale target = %+v, want the Deployment", target)
+	}
+	if hpa.Spec.MinReplicas == nil || *hpa.Spec.MinReplicas != 1 || hpa.Spec.MaxReplicas != 5 {
+		t.Errorf("replicas = %v..%d, want 1..5", hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas)
+	}
+
+	want := map[corev1.ResourceName]int32{corev1.ResourceCPU: 70, corev1.ResourceMemory: 80}
+	if len(hpa.Spec.Metrics) != len(want) {
+		t.Fatalf("metrics = %+v, want %v", hpa.Spec.Metrics, want)
+	}
+	for _, metric := range hpa.Spec.Metrics {
+		if metric.Type != autoscalingv2.ResourceMetricSourceType || metric.Resource == nil {
+			t.Fatalf("metric = %+v, want a resource metric", metric)
+		}
+		got := metric.Resource.Target
+		if got.Type != autoscalingv2.UtilizationMetricType || got.AverageUtilization == nil || *got.AverageUtilization != want[metric.Resource.Name] {
+			t.Errorf("%s target = %+v, want %d%% utilization", metric.Resource.Name, got, want[metric.Resource.Name])
+		}
+	}
+}
+
+func TestDeployMCPServerAutoscaled(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	d := NewSimpleDeployer(clientset)
+	spec := autoscaledSpec()
+
+	if err := d.DeployMCPServer(context.Background(), spec); err != nil {
+		t.Fatalf("DeployMCPServer() error = %v", err)
+	}
+
+	deployment, err := clientset.AppsV1().Deployments(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if deployment.Spec.Replicas != nil {
+		t.Errorf("deployment replicas = %d, want unset", *deployment.Spec.Replicas)
+	}
+
+	hpa, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get horizontal pod autoscaler: %v", err)
+	}
+	assertOwnedByDeployment(t, hpa, deployment)
+}
+
+func TestDeployMCPServerRollsBackOnAutoscalerFailure(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "horizontalpodautoscalers", failOn(apierrors.NewForbidden(hpasResource.GroupResource(), "test-server", fmt.Errorf("not allowed"))))
+	d := NewSimpleDeployer(clientset)
+
+	err := d.DeployMCPServer(context.Background(), autoscaledSpec())
+	if !errors.Is(err, ErrForbidden) {
+		t.Fatalf("DeployMCPServer() error = %v, want ErrForbidden", err)
+	}
+	if !strings.Contains(err.Error(), "rolled back service test-ns/test-server, deployment test-ns/test-server") {
+		t.Errorf("DeployMCPServer() error = %q, want service and deployment rolled back", err)
+	}
+
+	_, err = clientset.CoreV1().Services("test-ns").Get(context.Background(), "test-server", metav1.GetOptions{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("service still present after rollback: %v", err)
+	}
+}
+
+func TestApplyMCPServerAutoscaling(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("patch", "*", applyReactor(clientset))
+	d := NewSimpleDeployer(clientset)
+	spec := autoscaledSpec()
+	hpas := clientset.AutoscalingV2().HorizontalPodAutoscalers(spec.Namespace)
+
+	if _, err := d.ApplyMCPServer(context.Background(), spec); err != nil {
+		t.Fatalf("ApplyMCPServer() error = %v", err)
+	}
+	hpa, err := hpas.Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get horizontal pod autoscaler: %v", err)
+	}
+	if hpa.Spec.MaxReplicas != 5 {
+		t.Errorf("max replicas = %d, want 5", hpa.Spec.MaxReplicas)
+	}
+
+	changed, err := d.ApplyMCPServer(context.Background(), spec)
+	if err != nil || changed {
+		t.Errorf("repeated ApplyMCPServer() = %v, %v, want unchanged", changed, err)
+	}
+
+	spec.Autoscaling = nil
+	spec.Replicas = int32Ptr(2)
+	changed, err = d.ApplyMCPServer(context.Background(), spec)
+	if err != nil || !changed {
+		t.Fatalf("ApplyMCPServer() without autoscaling = %v, %v, want changed", changed, err)
+	}
+	if _, err := hpas.Get(context.Background(), spec.Name, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("horizontal pod autoscaler still present: %v", err)
+	}
+	deployment, err := clientset.AppsV1().Deployments(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 2 {
+		t.Errorf("deployment replicas = %v, want 2", deployment.Spec.Replicas)
+	}
+}
+
+func TestApplyMCPServerKeepsForeignAutoscaler(t *testing.T) {
+	foreign := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-server", Namespace: "test-ns"},
+	}
+	clientset := fake.NewSimpleClientset(foreign)
+	clientset.PrependReactor("patch", "*", applyReactor(clientset))
+	d := NewSimpleDeployer(clientset)
+
+	if _, err := d.ApplyMCPServer(context.Background(), testSpec()); err != nil {
+		t.Fatalf("ApplyMCPServer() error = %v", err)
+	}
+	if _, err := clientset.AutoscalingV2().HorizontalPodAutoscalers("test-ns").Get(context.Background(), "test-server", metav1.GetOptions{}); err != nil {
+		t.Errorf("unlabeled horizontal pod autoscaler was deleted: %v", err)
+	}
+}
+
+func TestScaleMCPServer(t *testing.T) {
+	autoscaler := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "autoscaled",
+			Namespace: "test-ns",
+			Labels:    map[string]string{MCPServerLabel: "true"},
+		},
+	}
+	unlabeled := testDeployment("test-ns", "plain", 1)
+	unlabeled.Labels = nil
+
+	tests := []struct {
+		name     string
+		server   string
+		replicas int32
+		wantErr  error
+	}{
+		{name: "scales the deployment", server: "test-server", replicas: 3},
+		{name: "scales to zero", server: "test-server", replicas: 0},
+		{name: "missing server", server: "missing", replicas: 1, wantErr: ErrServerNotFound},
+		{name: "unlabeled deployment", server: "plain", replicas: 1, wantErr: ErrServerNotFound},
+		{name: "autoscaled server", server: "autoscaled", replicas: 1, wantErr: ErrAutoscaled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(
+				testDeployment("test-ns", "test-server", 1),
+				testDeployment("test-ns", "autoscaled", 1),
+				unlabeled,
+				autoscaler,
+			)
+			d := NewSimpleDeployer(clientset)
+
+			err := d.ScaleMCPServer(context.Background(), "test-ns", tt.server, tt.replicas)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ScaleMCPServer() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ScaleMCPServer() error = %v", err)
+			}
+
+			deployment, err := clientset.AppsV1().Deployments("test-ns").Get(context.Background(), tt.server, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get deployment: %v", err)
+			}
+			if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != tt.replicas {
+				t.Errorf("replicas = %v, want %d", deployment.Spec.Replicas, tt.replicas)
+			}
+		})
+	}
+}
+
+func TestScaleMCPServerNegativeReplicas(t *testing.T) {
+	clientset := fake.NewSimpleClientset(testDeployment("test-ns", "test-server", 1))
+	d := NewSimpleDeployer(clientset)
+
+	var validationErr *ValidationError
+	if err := d.ScaleMCPServer(context.Background(), "test-ns", "test-server", -1); !errors.As(err, &validationErr) {
+		t.Fatalf("ScaleMCPServer() error = %v, want *ValidationError", err)
+	}
+	if len(clientset.Actions()) != 0 {
+		t.Errorf("actions = %v, want none", clientset.Actions())
+	}
+}
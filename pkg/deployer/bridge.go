@@ -0,0 +1,77 @@
+package deployer
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DefaultBridgeImage is the image providing the mcp-bridge binary, built
+	// from cmd/mcp-bridge
+	DefaultBridgeImage = "ghcr.io/grs/mcp-deployment/mcp-bridge:latest"
+
+	// bridgeVolume is the emptyDir the init container copies mcp-bridge into
+	bridgeVolume = "mcp-bridge"
+
+	// bridgeDir is where bridgeVolume is mounted in both containers
+	bridgeDir = "/var/run/mcp-bridge"
+)
+
+// usesBridge reports whether the server runs behind mcp-bridge
+func usesBridge(spec *MCPServerSpec) bool {
+	transport, _ := spec.endpoint()
+	return transport == TransportStdio
+}
+
+// containerCommand returns the command and arguments of the server container.
+// A stdio server is started by mcp-bridge, which serves it on the mcp port,
+// with the command of the package if there is one.
+func containerCommand(spec *MCPServerSpec) ([]string, []string) {
+	if !usesBridge(spec) {
+		return spec.Command, spec.Args
+	}
+
+	var bridge StdioBridge
+	if spec.Bridge != nil {
+		bridge = *spec.Bridge
+	}
+	sessions := bridge.Sessions
+	if sessions == "" {
+		sessions = BridgePerSession
+	}
+	_, path := spec.endpoint()
+
+	command := []string{
+		bridgeDir + "/mcp-bridge",
+		"--port", strconv.Itoa(int(spec.Port)),
+		"--path", path,
+		"--sessions", string(sessions),
+		"--",
+	}
+	switch {
+	case spec.Package != nil:
+		bridge.Command = packageCommand(spec.Package)
+	case len(bridge.Command) == 0:
+		bridge.Command = spec.Command
+	}
+	args := append(append([]string{}, bridge.Command...), spec.Args...)
+	return command, args
+}
+
+// bridgeInitContainer returns the init container that installs mcp-bridge
+// into the shared volume
+func bridgeInitContainer(spec *MCPServerSpec) corev1.Container {
+	image := DefaultBridgeImage
+	if spec.Bridge != nil && spec.Bridge.Image != "" {
+		image = spec.Bridge.Image
+	}
+	return corev1.Container{
+		Name:    "install-mcp-bridge",
+		Image:   image,
+		Command: []string{"/mcp-bridge", "install", bridgeDir + "/mcp-bridge"},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: bridgeVolume, MountPath: bridgeDir},
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package deployer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildDeploymentBridge(t *testing.T) {
+	var d SimpleDeployer
+	spec := testSpec()
+	spec.Transport, spec.Path = TransportStdio, "/v1/mcp"
+	spec.Bridge = &StdioBridge{Command: []string{"npx", "server-everything"}, Sessions: BridgeShared, Image: "example/mcp-bridge:dev"}
+	spec.ReadinessProbe = &Probe{Type: ProbeMCP}
+
+	podSpec := d.buildDeployment(spec).Spec.Template.Spec
+	container := podSpec.Containers[0]
+
+	wantCommand := []string{"/var/run/mcp-bridge/mcp-bridge", "--port", "8080", "--path", "/v1/mcp", "--sessions", "shared", "--"}
+	if !reflect.DeepEqual(container.Command, wantCommand) {
+		t.Errorf("command = %v, want %v", container.Command, wantCommand)
+	}
+	if wantArgs := []string{"npx", "server-everything", "--verbose"}; !reflect.DeepEqual(container.Args, wantArgs) {
+		t.Errorf("args = %v, want %v", container.Args, wantArgs)
+	}
+
+	var installImage string
+	for _, c := range podSpec.InitContainers {
+		if c.Name == "install-mcp-bridge" {
+			installImage = c.Image
+			if !reflect.DeepEqual(c.Command, []string{"/mcp-bridge", "install", "/var/run/mcp-bridge/mcp-bridge"}) {
+				t.Errorf("install command = %v", c.Command)
+			}
+		}
+	}
+	if installImage != "example/mcp-bridge:dev" {
+		t.Errorf("init containers = %+v, want install-mcp-bridge from the bridge image", podSpec.InitContainers)
+	}
+	if len(podSpec.InitContainers) != 2 {
+		t.Errorf("init containers = %d, want mcp-bridge and mcp-probe", len(podSpec.InitContainers))
+	}
+
+	// The bridge listens on the mcp port, so the probes check it like any server
+	if probe := container.ReadinessProbe; probe == nil || probe.Exec == nil || probe.Exec.Command[2] != "http://127.0.0.1:8080/v1/mcp" {
+		t.Errorf("readiness probe = %+v, want an MCP probe of the bridge", probe)
+	}
+	if probe := container.LivenessProbe; probe == nil || probe.TCPSocket == nil {
+		t.Errorf("liveness probe = %+v, want the default TCP probe", probe)
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		spec := testSpec()
+		spec.Transport = TransportStdio
+		spec.Bridge = &StdioBridge{Command: []string{"mcp-server"}}
+
+		podSpec := d.buildDeployment(spec).Spec.Template.Spec
+		wantCommand := []string{"/var/run/mcp-bridge/mcp-bridge", "--port", "8080", "--path", "/mcp", "--sessions", "per-session", "--"}
+		if !reflect.DeepEqual(podSpec.Containers[0].Command, wantCommand) {
+			t.Errorf("command = %v, want %v", podSpec.Containers[0].Command, wantCommand)
+		}
+		if podSpec.InitContainers[0].Image != DefaultBridgeImage {
+			t.Errorf("bridge image = %s, want %s", podSpec.InitContainers[0].Image, DefaultBridgeImage)
+		}
+	})
+
+	t.Run("command from the spec", func(t *testing.T) {
+		spec := testSpec()
+		spec.Transport = TransportStdio
+		spec.Command, spec.WorkingDir = []string{"python", "-m", "server"}, "/srv"
+
+		container := d.buildDeployment(spec).Spec.Template.Spec.Containers[0]
+		if wantArgs := []string{"python", "-m", "server", "--verbose"}; !reflect.DeepEqual(container.Args, wantArgs) {
+			t.Errorf("args = %v, want %v", container.Args, wantArgs)
+		}
+		if container.Command[0] != "/var/run/mcp-bridge/mcp-bridge" || container.WorkingDir != "/srv" {
+			t.Errorf("command = %v, working dir = %q; want the bridge in /srv", container.Command, container.WorkingDir)
+		}
+	})
+
+	t.Run("http server keeps the image entrypoint", func(t *testing.T) {
+		container := d.buildDeployment(testSpec()).Spec.Template.Spec.Containers[0]
+		if container.Command != nil || !reflect.DeepEqual(container.Args, []string{"--verbose"}) {
+			t.Errorf("command = %v, args = %v; want the image entrypoint with the spec args", container.Command, container.Args)
+		}
+	})
+}
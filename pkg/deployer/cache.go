@@ -0,0 +1,125 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+)
+
+// serverCache holds informer-backed listers for the labeled Deployments,
+// Services and pods of MCP servers
+type serverCache struct {
+	namespace   string
+	deployments appslisters.DeploymentLister
+	services    corelisters.ServiceLister
+	pods        corelisters.PodLister
+}
+
+// covers reports whether the cache holds the objects of a namespace
+func (c *serverCache) covers(namespace string) bool {
+	return c.namespace == metav1.NamespaceAll || c.namespace == namespace
+}
+
+// newServerInformerFactory returns an informer factory restricted to objects
+// carrying the MCP server label, in one namespace or all namespaces if
+// namespace is empty
+func newServerInformerFactory(clientset kubernetes.Interface, namespace string) informers.SharedInformerFactory {
+	return informers.NewSharedInformerFactoryWithOptions(clientset, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = fmt.Sprintf("%s=true", MCPServerLabel)
+		}))
+}
+
+// StartCache starts informers on the MCP server Deployments, Services and pods
+// in a namespace, or in all namespaces if namespace is empty, and waits for
+// them to sync. While the cache runs, ListMCPServers and GetMCPServer are
+// served from it without any API calls for the namespaces it covers. Results
+// may lag the cluster slightly, so a server just deployed may not be listed
+// immediately. The cache stops when the context is done.
+func (d *SimpleDeployer) StartCache(ctx context.Context, namespace string) error {
+	factory := newServerInformerFactory(d.clientset, namespace)
+	cache := &serverCache{
+		namespace:   namespace,
+		deployments: factory.Apps().V1().Deployments().Lister(),
+		services:    factory.Core().V1().Services().Lister(),
+		pods:        factory.Core().V1().Pods().Lister(),
+	}
+
+	factory.Start(ctx.Done())
+	for informerType, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			factory.Shutdown()
+			return fmt.Errorf("failed to sync %v cache: %w", informerType, ctx.Err())
+		}
+	}
+
+	d.cacheMu.Lock()
+	d.cache = cache
+	d.cacheMu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		d.cacheMu.Lock()
+		if d.cache == cache {
+			d.cache = nil
+		}
+		d.cacheMu.Unlock()
+		factory.Shutdown()
+	}()
+
+	return nil
+}
+
+// cacheFor returns the running cache if it covers the namespace, or nil
+func (d *SimpleDeployer) cacheFor(namespace string) *serverCache {
+	d.cacheMu.RLock()
+	defer d.cacheMu.RUnlock()
+	if d.cache != nil && d.cache.covers(namespace) {
+		return d.cache
+	}
+	return nil
+}
+
+// joinServers builds the status of each MCP server Deployment by joining it in
+// memory with its Service and pods. The servers are sorted by namespace and
+// name.
+func joinServers(deployments []*appsv1.Deployment, services []*corev1.Service, pods []*corev1.Pod) []MCPServerStatus {
+	servicesByName := make(map[types.NamespacedName]*corev1.Service, len(services))
+	for _, service := range services {
+		servicesByName[types.NamespacedName{Namespace: service.Namespace, Name: service.Name}] = service
+	}
+
+	podsByNamespace := make(map[string][]*corev1.Pod)
+	for _, pod := range pods {
+		podsByNamespace[pod.Namespace] = append(podsByNamespace[pod.Namespace], pod)
+	}
+
+	servers := make([]MCPServerStatus, 0, len(deployments))
+	for _, deployment := range deployments {
+		service := servicesByName[types.NamespacedName{Namespace: deployment.Namespace, Name: deployment.Name}]
+		servers = append(servers, buildServerStatus(deployment, service, serverPods(deployment, podsByNamespace[deployment.Namespace])))
+	}
+
+	sortByNamespaceAndName(servers)
+	return servers
+}
+
+// sortByNamespaceAndName sorts servers by namespace, then name
+func sortByNamespaceAndName(servers []MCPServerStatus) {
+	sort.Slice(servers, func(i, j int) bool {
+		if servers[i].Namespace != servers[j].Namespace {
+			return servers[i].Namespace < servers[j].Namespace
+		}
+		return servers[i].Name < servers[j].Name
+	})
+}
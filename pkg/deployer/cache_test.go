@@ -0,0 +1,152 @@
+package deployer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// testServers returns the Deployments, Services and pods of n available MCP
+// servers in a namespace
+func testServers(namespace string, n int) []runtime.Object {
+	var objects []runtime.Object
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("server-%03d", i)
+		deployment := testDeployment(namespace, name, 1)
+		deployment.Labels["app"] = name
+		deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: deployment.Labels}
+
+		pod := testPod(corev1.ContainerStatus{Name: "mcp-server", RestartCount: 1})
+		pod.Name = name + "-pod"
+		pod.Namespace = namespace
+		pod.Labels = deployment.Labels
+
+		objects = append(objects, deployment, testService(namespace, name, 8080), pod)
+	}
+	return objects
+}
+
+func TestListMCPServersAPICalls(t *testing.T) {
+	clientset := fake.NewSimpleClientset(testServers("test-ns", 50)...)
+	d := NewSimpleDeployer(clientset)
+
+	list, err := d.ListMCPServers(context.Background(), ListOptions{Namespace: "test-ns"})
+	if err != nil {
+		t.Fatalf("ListMCPServers() error = %v", err)
+	}
+	servers := list.Items
+	if len(servers) != 50 {
+		t.Fatalf("ListMCPServers() returned %d servers, want 50", len(servers))
+	}
+	for _, server := range servers {
+		if server.Endpoint != "http://"+server.Name+".test-ns.svc.cluster.local:8080/mcp" || server.RestartCount != 1 {
+			t.Errorf("server %s endpoint = %q, restarts = %d", server.Name, server.Endpoint, server.RestartCount)
+		}
+	}
+
+	// One list each of deployments, services and pods, however many servers
+	if actions := clientset.Actions(); len(actions) != 3 {
+		t.Errorf("ListMCPServers() made %d API calls, want 3: %v", len(actions), actions)
+	}
+}
+
+func TestStartCache(t *testing.T) {
+	unlabeled := testDeployment("test-ns", "not-mcp", 1)
+	unlabeled.Labels = nil
+	objects := append(testServers("test-ns", 3), unlabeled, testDeployment("other-ns", "elsewhere", 0))
+	clientset := fake.NewSimpleClientset(objects...)
+	d := NewSimpleDeployer(clientset)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := d.StartCache(ctx, "test-ns"); err != nil {
+		t.Fatalf("StartCache() error = %v", err)
+	}
+	clientset.ClearActions()
+
+	list, err := d.ListMCPServers(ctx, ListOptions{Namespace: "test-ns"})
+	if err != nil {
+		t.Fatalf("ListMCPServers() error = %v", err)
+	}
+	servers := list.Items
+	if len(servers) != 3 || servers[0].Name != "server-000" || servers[0].Endpoint != "http://server-000.test-ns.svc.cluster.local:8080/mcp" || servers[0].RestartCount != 1 {
+		t.Errorf("ListMCPServers() = %+v, want 3 sorted servers", servers)
+	}
+
+	server, err := d.GetMCPServer(ctx, "test-ns", "server-001")
+	if err != nil {
+		t.Fatalf("GetMCPServer() error = %v", err)
+	}
+	if server.Endpoint != "http://server-001.test-ns.svc.cluster.local:8080/mcp" {
+		t.Errorf("GetMCPServer() endpoint = %q, want http://server-001.test-ns.svc.cluster.local:8080/mcp", server.Endpoint)
+	}
+	if _, err := d.GetMCPServer(ctx, "test-ns", "not-mcp"); !errors.Is(err, ErrServerNotFound) {
+		t.Errorf("GetMCPServer(not-mcp) error = %v, want ErrServerNotFound", err)
+	}
+
+	if actions := clientset.Actions(); len(actions) != 0 {
+		t.Errorf("cached calls made %d API calls, want 0: %v", len(actions), actions)
+	}
+
+	// Namespaces outside the cache are read from the API
+	list, err = d.ListMCPServers(ctx, ListOptions{Namespace: "other-ns"})
+	if err != nil {
+		t.Fatalf("ListMCPServers(other-ns) error = %v", err)
+	}
+	servers = list.Items
+	if len(servers) != 1 || len(clientset.Actions()) == 0 {
+		t.Errorf("ListMCPServers(other-ns) = %v with %d API calls", servers, len(clientset.Actions()))
+	}
+
+	// Once stopped, lists are read from the API again
+	cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for d.cacheFor("test-ns") != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("cache still in use after the context was cancelled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func BenchmarkListMCPServers(b *testing.B) {
+	for _, n := range []int{100, 500} {
+		clientset := fake.NewSimpleClientset(testServers("test-ns", n)...)
+
+		b.Run(fmt.Sprintf("api/%d", n), func(b *testing.B) {
+			d := NewSimpleDeployer(clientset)
+			benchmarkList(b, d, clientset)
+		})
+
+		b.Run(fmt.Sprintf("cache/%d", n), func(b *testing.B) {
+			d := NewSimpleDeployer(clientset)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if err := d.StartCache(ctx, "test-ns"); err != nil {
+				b.Fatalf("StartCache() error = %v", err)
+			}
+			benchmarkList(b, d, clientset)
+		})
+	}
+}
+
+// benchmarkList lists the servers b.N times and reports the API calls made
+func benchmarkList(b *testing.B, d *SimpleDeployer, clientset *fake.Clientset) {
+	calls := 0
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		clientset.ClearActions()
+		if _, err := d.ListMCPServers(context.Background(), ListOptions{Namespace: "test-ns"}); err != nil {
+			b.Fatalf("ListMCPServers() error = %v", err)
+		}
+		calls += len(clientset.Actions())
+	}
+	b.ReportMetric(float64(calls)/float64(b.N), "calls/op")
+}
@@ -0,0 +1,375 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/grs/mcp-deployment/pkg/apis/mcp/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	autoscalinglisters "k8s.io/client-go/listers/autoscaling/v2"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Controller reconciles MCPServer resources into the Deployment and Service of
+// each server and reports the status of the server on the resource. The
+// Deployment and Service are owned by the resource, so they are recreated if
+// deleted, corrected if edited, and garbage collected with the resource.
+type Controller struct {
+	deployer *SimpleDeployer
+	client   dynamic.Interface
+	queue    workqueue.RateLimitingInterface
+
+	serverFactory dynamicinformer.DynamicSharedInformerFactory
+	kubeFactory   informers.SharedInformerFactory
+
+	servers     cache.GenericLister
+	deployments appslisters.DeploymentLister
+	services    corelisters.ServiceLister
+	hpas        autoscalinglisters.HorizontalPodAutoscalerLister
+	pods        corelisters.PodLister
+}
+
+// NewController creates a controller for the MCPServer resources in a
+// namespace, or in all namespaces if namespace is empty
+func NewController(clientset kubernetes.Interface, client dynamic.Interface, namespace string) (*Controller, error) {
+	c := &Controller{
+		deployer:      NewSimpleDeployer(clientset),
+		client:        client,
+		queue:         workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		serverFactory: dynamicinformer.NewFilteredDynamicSharedInformerFactory(client, 0, namespace, nil),
+		kubeFactory:   newServerInformerFactory(clientset, namespace),
+	}
+
+	servers := c.serverFactory.ForResource(v1alpha1.Resource)
+	deployments := c.kubeFactory.Apps().V1().Deployments()
+	services := c.kubeFactory.Core().V1().Services()
+	hpas := c.kubeFactory.Autoscaling().V2().HorizontalPodAutoscalers()
+	pods := c.kubeFactory.Core().V1().Pods()
+	c.servers = servers.Lister()
+	c.deployments = deployments.Lister()
+	c.services = services.Lister()
+	c.hpas = hpas.Lister()
+	c.pods = pods.Lister()
+
+	handlers := map[cache.SharedIndexInformer]cache.ResourceEventHandlerFuncs{
+		servers.Informer(): {
+			AddFunc:    c.enqueue,
+			UpdateFunc: func(_, obj interface{}) { c.enqueue(obj) },
+			DeleteFunc: c.enqueue,
+		},
+		deployments.Informer(): {
+			AddFunc:    c.enqueueOwner,
+			UpdateFunc: func(_, obj interface{}) { c.enqueueOwner(obj) },
+			DeleteFunc: c.enqueueOwner,
+		},
+		services.Informer(): {
+			AddFunc:    c.enqueueOwner,
+			UpdateFunc: func(_, obj interface{}) { c.enqueueOwner(obj) },
+			DeleteFunc: c.enqueueOwner,
+		},
+		hpas.Informer(): {
+			AddFunc:    c.enqueueOwner,
+			UpdateFunc: func(_, obj interface{}) { c.enqueueOwner(obj) },
+			DeleteFunc: c.enqueueOwner,
+		},
+		pods.Informer(): {
+			AddFunc:    c.enqueueForPod,
+			UpdateFunc: func(_, obj interface{}) { c.enqueueForPod(obj) },
+			DeleteFunc: c.enqueueForPod,
+		},
+	}
+	for informer, handler := range handlers {
+		if _, err := informer.AddEventHandler(handler); err != nil {
+			return nil, fmt.Errorf("failed to watch MCP servers: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// Run starts the informers and reconciles MCPServer resources with the given
+// number of workers until the context is done
+func (c *Controller) Run(ctx context.Context, workers int) error {
+	defer c.queue.ShutDown()
+
+	c.serverFactory.Start(ctx.Done())
+	c.kubeFactory.Start(ctx.Done())
+	defer c.serverFactory.Shutdown()
+	defer c.kubeFactory.Shutdown()
+
+	for resource, synced := range c.serverFactory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("failed to sync %v cache: %w", resource, ctx.Err())
+		}
+	}
+	for informerType, synced := range c.kubeFactory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("failed to sync %v cache: %w", informerType, ctx.Err())
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c.processNextItem(ctx) {
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	c.queue.ShutDown()
+	wg.Wait()
+	return nil
+}
+
+// processNextItem reconciles the next queued MCPServer, requeuing it with
+// backoff on failure. It returns false once the queue is shut down.
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	item, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(item)
+
+	key := item.(string)
+	if err := c.reconcile(ctx, key); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to reconcile MCPServer %s: %w", key, err))
+		c.queue.AddRateLimited(item)
+		return true
+	}
+
+	c.queue.Forget(item)
+	return true
+}
+
+// enqueue queues an MCPServer for reconciliation
+func (c *Controller) enqueue(obj interface{}) {
+	if key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+		c.queue.Add(key)
+	}
+}
+
+// enqueueOwner queues the MCPServer controlling an object, if any
+func (c *Controller) enqueueOwner(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	object, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+
+	owner := metav1.GetControllerOf(object)
+	if owner == nil || owner.Kind != v1alpha1.Kind || owner.APIVersion != v1alpha1.SchemeGroupVersion.String() {
+		return
+	}
+	c.queue.Add(object.GetNamespace() + "/" + owner.Name)
+}
+
+// enqueueForPod queues the MCPServer controlling the Deployment of a pod
+func (c *Controller) enqueueForPod(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+
+	candidates, err := c.deployments.Deployments(pod.Namespace).List(labels.Everything())
+	if err != nil {
+		return
+	}
+	for _, deployment := range candidates {
+		if len(serverPods(deployment, []*corev1.Pod{pod})) > 0 {
+			c.enqueueOwner(deployment)
+		}
+	}
+}
+
+// reconcile applies the objects of an MCPServer and updates its status
+func (c *Controller) reconcile(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil
+	}
+
+	// The objects of a deleted MCPServer are garbage collected
+	obj, err := c.servers.ByNamespace(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	server, err := fromUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	if server.DeletionTimestamp != nil {
+		return nil
+	}
+
+	status := *server.Status.DeepCopy()
+	status.ObservedGeneration = server.Generation
+
+	spec := specFromResource(server)
+	if err := spec.Validate(); err != nil {
+		status.Phase = string(MCPServerFailed)
+		setReadyCondition(&status, server.Generation, metav1.ConditionFalse, v1alpha1.ReasonInvalidSpec, err.Error())
+		return c.updateStatus(ctx, server, status)
+	}
+
+	if err := c.deployer.checkServiceNames(ctx, spec); err != nil {
+		return c.applyFailed(ctx, server, status, err)
+	}
+
+	owner := metav1.NewControllerRef(server, v1alpha1.SchemeGroupVersion.WithKind(v1alpha1.Kind))
+	applyOpts := c.deployer.applyOptions()
+
+	deployment := c.deployer.buildDeployment(spec)
+	deployment.OwnerReferences = []metav1.OwnerReference{*owner}
+	if _, _, err := c.deployer.applyDeployment(ctx, deployment, applyOpts); err != nil {
+		return c.applyFailed(ctx, server, status, fmt.Errorf("failed to apply deployment: %w", err))
+	}
+
+	service := c.deployer.buildService(spec)
+	service.OwnerReferences = []metav1.OwnerReference{*owner}
+	if _, err := c.deployer.applyService(ctx, service, applyOpts); err != nil {
+		return c.applyFailed(ctx, server, status, fmt.Errorf("failed to apply service: %w", err))
+	}
+
+	if usesRouter(spec) {
+		peers := c.deployer.buildPeersService(spec)
+		peers.OwnerReferences = []metav1.OwnerReference{*owner}
+		if _, err := c.deployer.applyService(ctx, peers, applyOpts); err != nil {
+			return c.applyFailed(ctx, server, status, fmt.Errorf("failed to apply peers service: %w", err))
+		}
+	} else if peers, err := c.services.Services(namespace).Get(peersServiceName(name)); err == nil && metav1.IsControlledBy(peers, server) {
+		if _, err := c.deployer.removePeersService(ctx, namespace, name, server, metav1.DeleteOptions{}); err != nil {
+			return c.applyFailed(ctx, server, status, err)
+		}
+	}
+
+	if spec.Autoscaling != nil {
+		hpa := c.deployer.buildHorizontalPodAutoscaler(spec)
+		hpa.OwnerReferences = []metav1.OwnerReference{*owner}
+		if _, err := c.deployer.applyHorizontalPodAutoscaler(ctx, hpa, applyOpts); err != nil {
+			return c.applyFailed(ctx, server, status, fmt.Errorf("failed to apply horizontal pod autoscaler: %w", err))
+		}
+	} else if hpa, err := c.hpas.HorizontalPodAutoscalers(namespace).Get(name); err == nil && metav1.IsControlledBy(hpa, server) {
+		if _, err := c.deployer.removeHorizontalPodAutoscaler(ctx, namespace, name, metav1.DeleteOptions{}); err != nil {
+			return c.applyFailed(ctx, server, status, err)
+		}
+	}
+
+	// The status is computed from the informer caches; the events caused by
+	// the apply will trigger another reconcile with fresher state
+	current, err := c.deployments.Deployments(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		status.Phase = string(MCPServerPending)
+		setReadyCondition(&status, server.Generation, metav1.ConditionFalse, string(MCPServerPending), "waiting for the deployment to be created")
+		return c.updateStatus(ctx, server, status)
+	}
+	if err != nil {
+		return err
+	}
+	currentService, _ := c.services.Services(namespace).Get(name)
+	podList, err := c.pods.Pods(namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	pods := serverPods(current, podList)
+	observed := buildServerStatus(current, currentService, pods)
+
+	status.Phase = string(observed.Phase)
+	status.Image = observed.Image
+	status.Endpoint = observed.Endpoint
+	status.DesiredReplicas = observed.DesiredReplicas
+	status.ReadyReplicas = observed.ReadyReplicas
+	status.UpdatedReplicas = observed.UpdatedReplicas
+	status.AvailableReplicas = observed.AvailableReplicas
+	status.RestartCount = observed.RestartCount
+	status.LastTerminationReason = observed.LastTerminationReason
+
+	// Mirror the Deployment conditions, keeping the Ready condition so that
+	// its transition time is preserved while its status is unchanged
+	ready := meta.FindStatusCondition(status.Conditions, v1alpha1.ConditionReady)
+	status.Conditions = nil
+	for _, condition := range observed.Conditions {
+		condition.ObservedGeneration = server.Generation
+		status.Conditions = append(status.Conditions, condition)
+	}
+	if ready != nil {
+		status.Conditions = append(status.Conditions, *ready)
+	}
+
+	progress, _, _ := rolloutProgress(current)
+	switch failure := rolloutFailure(current, pods); {
+	case observed.Phase == MCPServerReady:
+		setReadyCondition(&status, server.Generation, metav1.ConditionTrue, string(MCPServerReady), progress.Message)
+	case failure != nil:
+		setReadyCondition(&status, server.Generation, metav1.ConditionFalse, failure.Reason, failure.Message)
+	default:
+		setReadyCondition(&status, server.Generation, metav1.ConditionFalse, string(observed.Phase), progress.Message)
+	}
+
+	return c.updateStatus(ctx, server, status)
+}
+
+// applyFailed records a failure to apply the objects of an MCPServer in its
+// status and returns the failure so that the MCPServer is requeued
+func (c *Controller) applyFailed(ctx context.Context, server *v1alpha1.MCPServer, status v1alpha1.MCPServerStatus, err error) error {
+	setReadyCondition(&status, server.Generation, metav1.ConditionFalse, v1alpha1.ReasonApplyFailed, err.Error())
+	if updateErr := c.updateStatus(ctx, server, status); updateErr != nil {
+		return fmt.Errorf("%w; %w", err, updateErr)
+	}
+	return err
+}
+
+// updateStatus writes the status of an MCPServer if it has changed
+func (c *Controller) updateStatus(ctx context.Context, server *v1alpha1.MCPServer, status v1alpha1.MCPServerStatus) error {
+	if equality.Semantic.DeepEqual(server.Status, status) {
+		return nil
+	}
+
+	updated := server.DeepCopy()
+	updated.Status = status
+	obj, err := toUnstructured(updated)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.client.Resource(v1alpha1.Resource).Namespace(server.Namespace).UpdateStatus(ctx, obj, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update MCPServer status: %w", err)
+	}
+	return nil
+}
+
+// setReadyCondition sets the Ready condition of an MCPServer status
+func setReadyCondition(status *v1alpha1.MCPServerStatus, generation int64, conditionStatus metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               v1alpha1.ConditionReady,
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
@@ -0,0 +1,222 @@
+package deployer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grs/mcp-deployment/pkg/apis/mcp/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// eventually polls the condition until it holds or fails the test
+func eventually(t *testing.T, description string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", description)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// runController starts a controller on fake clients until the test ends
+func runController(t *testing.T, servers ...runtime.Object) (*fake.Clientset, *dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("patch", "*", applyReactor(clientset))
+	client := newDynamicClient(t, servers...)
+
+	controller, err := NewController(clientset, client, "")
+	if err != nil {
+		t.Fatalf("NewController() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- controller.Run(ctx, 2) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	})
+
+	return clientset, client
+}
+
+// serverResource returns the stored MCPServer, or nil if it cannot be read
+func serverResource(client *dynamicfake.FakeDynamicClient, namespace, name string) *v1alpha1.MCPServer {
+	obj, err := client.Resource(v1alpha1.Resource).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	server, err := fromUnstructured(obj)
+	if err != nil {
+		return nil
+	}
+	return server
+}
+
+func TestControllerReconcile(t *testing.T) {
+	clientset, client := runController(t, testResource(t, v1alpha1.MCPServerStatus{}))
+	ctx := context.Background()
+
+	var deployment *appsv1.Deployment
+	eventually(t, "the deployment to be created", func() bool {
+		var err error
+		deployment, err = clientset.AppsV1().Deployments("test-ns").Get(ctx, "test-server", metav1.GetOptions{})
+		return err == nil
+	})
+	owner := metav1.GetControllerOf(deployment)
+	if owner == nil || owner.Kind != v1alpha1.Kind || owner.Name != "test-server" {
+		t.Errorf("deployment controller = %v, want MCPServer test-server", owner)
+	}
+	if image := deployment.Spec.Template.Spec.Containers[0].Image; image != "example/mcp-server:1.0" {
+		t.Errorf("deployment image = %q, want example/mcp-server:1.0", image)
+	}
+
+	service, err := clientset.CoreV1().Services("test-ns").Get(ctx, "test-server", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get service: %v", err)
+	}
+	if owner := metav1.GetControllerOf(service); owner == nil || owner.Kind != v1alpha1.Kind {
+		t.Errorf("service controller = %v, want MCPServer", owner)
+	}
+
+	eventually(t, "the Pending status", func() bool {
+		server := serverResource(client, "test-ns", "test-server")
+		return server != nil && server.Status.Phase == string(MCPServerPending)
+	})
+
+	// The status follows the Deployment
+	deployment.Status = appsv1.DeploymentStatus{Replicas: 1, ReadyReplicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	if err := clientset.Tracker().Update(deploymentsResource, deployment, "test-ns"); err != nil {
+		t.Fatalf("failed to update deployment status: %v", err)
+	}
+	eventually(t, "the Ready status", func() bool {
+		server := serverResource(client, "test-ns", "test-server")
+		return server != nil && server.Status.Phase == string(MCPServerReady) && server.Status.Endpoint == "http://test-server.test-ns.svc.cluster.local:8080/mcp" &&
+			meta.IsStatusConditionTrue(server.Status.Conditions, v1alpha1.ConditionReady)
+	})
+
+	// Once reconciled, the controller's own writes do not trigger more writes
+	time.Sleep(100 * time.Millisecond)
+	calls := len(client.Actions()) + len(clientset.Actions())
+	time.Sleep(200 * time.Millisecond)
+	if now := len(client.Actions()) + len(clientset.Actions()); now != calls {
+		t.Errorf("controller made %d API calls after reconciling, want none", now-calls)
+	}
+
+	// A deleted Service is recreated
+	if err := clientset.CoreV1().Services("test-ns").Delete(ctx, "test-server", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete service: %v", err)
+	}
+	eventually(t, "the service to be recreated", func() bool {
+		_, err := clientset.CoreV1().Services("test-ns").Get(ctx, "test-server", metav1.GetOptions{})
+		return err == nil
+	})
+
+	// Manual edits to the Deployment are reverted
+	deployment, err = clientset.AppsV1().Deployments("test-ns").Get(ctx, "test-server", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	deployment.Spec.Template.Spec.Containers[0].Image = "example/edited:latest"
+	deployment.ResourceVersion = "edited"
+	if err := clientset.Tracker().Update(deploymentsResource, deployment, "test-ns"); err != nil {
+		t.Fatalf("failed to edit deployment: %v", err)
+	}
+	eventually(t, "the deployment edit to be reverted", func() bool {
+		deployment, err := clientset.AppsV1().Deployments("test-ns").Get(ctx, "test-server", metav1.GetOptions{})
+		return err == nil && deployment.Spec.Template.Spec.Containers[0].Image == "example/mcp-server:1.0"
+	})
+}
+
+func TestControllerAutoscaling(t *testing.T) {
+	resource, err := toUnstructured(resourceFromSpec(autoscaledSpec()))
+	if err != nil {
+		t.Fatalf("toUnstructured() error = %v", err)
+	}
+	clientset, client := runController(t, resource)
+	ctx := context.Background()
+	hpas := clientset.AutoscalingV2().HorizontalPodAutoscalers("test-ns")
+
+	eventually(t, "the horizontal pod autoscaler to be created", func() bool {
+		hpa, err := hpas.Get(ctx, "test-server", metav1.GetOptions{})
+		if err != nil {
+			return false
+		}
+		owner := metav1.GetControllerOf(hpa)
+		return owner != nil && owner.Kind == v1alpha1.Kind && hpa.Spec.MaxReplicas == 5
+	})
+
+	// Turning autoscaling off deletes the HorizontalPodAutoscaler. The fake
+	// client stores status updates whole, so the update is repeated if a
+	// status update of the controller overwrites it.
+	eventually(t, "the horizontal pod autoscaler to be deleted", func() bool {
+		if _, err := hpas.Get(ctx, "test-server", metav1.GetOptions{}); apierrors.IsNotFound(err) {
+			return true
+		}
+		server := serverResource(client, "test-ns", "test-server")
+		if server == nil || server.Spec.Autoscaling == nil {
+			return false
+		}
+		server.Spec.Autoscaling = nil
+		updated, err := toUnstructured(server)
+		if err != nil {
+			t.Fatalf("toUnstructured() error = %v", err)
+		}
+		if _, err := client.Resource(v1alpha1.Resource).Namespace("test-ns").Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+			t.Fatalf("failed to update MCPServer: %v", err)
+		}
+		return false
+	})
+}
+
+func TestControllerRouter(t *testing.T) {
+	resource, err := toUnstructured(resourceFromSpec(routedSpec()))
+	if err != nil {
+		t.Fatalf("toUnstructured() error = %v", err)
+	}
+	clientset, _ := runController(t, resource)
+
+	eventually(t, "the peers service to be created", func() bool {
+		peers, err := clientset.CoreV1().Services("test-ns").Get(context.Background(), "test-server-peers", metav1.GetOptions{})
+		if err != nil {
+			return false
+		}
+		owner := metav1.GetControllerOf(peers)
+		return owner != nil && owner.Kind == v1alpha1.Kind && peers.Spec.ClusterIP == corev1.ClusterIPNone
+	})
+}
+
+func TestControllerInvalidSpec(t *testing.T) {
+	invalid := testResource(t, v1alpha1.MCPServerStatus{})
+	if err := unstructured.SetNestedField(invalid.Object, int64(0), "spec", "port"); err != nil {
+		t.Fatalf("failed to set port: %v", err)
+	}
+	clientset, client := runController(t, invalid)
+
+	eventually(t, "the InvalidSpec status", func() bool {
+		server := serverResource(client, "test-ns", "test-server")
+		if server == nil {
+			return false
+		}
+		ready := meta.FindStatusCondition(server.Status.Conditions, v1alpha1.ConditionReady)
+		return server.Status.Phase == string(MCPServerFailed) && ready != nil && ready.Reason == v1alpha1.ReasonInvalidSpec
+	})
+
+	if _, err := clientset.AppsV1().Deployments("test-ns").Get(context.Background(), "test-server", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("deployment created for an invalid spec: %v", err)
+	}
+}
@@ -0,0 +1,671 @@
+package deployer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/grs/mcp-deployment/pkg/apis/mcp/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// transientReadyReasons are Ready condition reasons that the controller is
+// expected to resolve by itself
+var transientReadyReasons = map[string]bool{
+	string(MCPServerPending):     true,
+	string(MCPServerProgressing): true,
+	string(MCPServerDegraded):    true,
+	v1alpha1.ReasonApplyFailed:   true,
+}
+
+// CRDDeployer implements the MCPDeployer interface by writing MCPServer
+// resources. The controller reconciles them into Deployments and Services and
+// reports their status on the resources.
+type CRDDeployer struct {
+	client dynamic.Interface
+}
+
+// NewCRDDeployer creates a new CRDDeployer instance
+func NewCRDDeployer(client dynamic.Interface) *CRDDeployer {
+	return &CRDDeployer{
+		client: client,
+	}
+}
+
+// DeployMCPServer creates the MCPServer resource for the spec
+func (d *CRDDeployer) DeployMCPServer(ctx context.Context, spec *MCPServerSpec) error {
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+
+	obj, err := toUnstructured(resourceFromSpec(spec))
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.Resource(v1alpha1.Resource).Namespace(spec.Namespace).Create(ctx, obj, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create MCPServer: %w", mapAPIError(err, spec.Namespace, spec.Name))
+	}
+
+	return nil
+}
+
+// DryRunMCPServer submits the MCPServer resource for the spec to the API server
+// as a server-side dry run and returns it as it would be stored. The
+// Deployment and Service are created later by the controller, so only the
+// resource itself is checked.
+func (d *CRDDeployer) DryRunMCPServer(ctx context.Context, spec *MCPServerSpec) ([]runtime.Object, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	obj, err := toUnstructured(resourceFromSpec(spec))
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := d.client.Resource(v1alpha1.Resource).Namespace(spec.Namespace).Create(ctx, obj, metav1.CreateOptions{
+		DryRun: []string{metav1.DryRunAll},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MCPServer: %w", mapAPIError(err, spec.Namespace, spec.Name))
+	}
+
+	return []runtime.Object{created}, nil
+}
+
+// ApplyMCPServer creates or updates the MCPServer resource for the spec using
+// server-side apply, and reports whether it changed
+func (d *CRDDeployer) ApplyMCPServer(ctx context.Context, spec *MCPServerSpec) (bool, error) {
+	if err := spec.Validate(); err != nil {
+		return false, err
+	}
+
+	data, err := json.Marshal(resourceFromSpec(spec))
+	if err != nil {
+		return false, fmt.Errorf("failed to encode MCPServer: %w", err)
+	}
+
+	servers := d.client.Resource(v1alpha1.Resource).Namespace(spec.Namespace)
+
+	var resourceVersion string
+	existing, err := servers.Get(ctx, spec.Name, metav1.GetOptions{})
+	if err == nil {
+		resourceVersion = existing.GetResourceVersion()
+	} else if !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get MCPServer: %w", mapAPIError(err, spec.Namespace, spec.Name))
+	}
+
+	applied, err := servers.Patch(ctx, spec.Name, types.ApplyPatchType, data, d.applyOptions())
+	if err != nil {
+		return false, fmt.Errorf("failed to apply MCPServer: %w", mapAPIError(err, spec.Namespace, spec.Name))
+	}
+
+	return applied.GetResourceVersion() != resourceVersion, nil
+}
+
+// ScaleMCPServer sets the replicas declared by the MCPServer resource, which
+// the controller then applies to the Deployment
+func (d *CRDDeployer) ScaleMCPServer(ctx context.Context, namespace, name string, replicas int32) error {
+	if replicas < 0 {
+		return &ValidationError{Errors: field.ErrorList{
+			field.Invalid(field.NewPath("replicas"), replicas, "must be greater than or equal to 0"),
+		}}
+	}
+
+	servers := d.client.Resource(v1alpha1.Resource).Namespace(namespace)
+	obj, err := servers.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get MCPServer: %w", mapAPIError(err, namespace, name))
+	}
+	server, err := fromUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	if server.Spec.Autoscaling != nil {
+		return fmt.Errorf("%w: %s/%s", ErrAutoscaled, namespace, name)
+	}
+
+	// The resource version makes the patch fail if autoscaling was enabled
+	// since the resource was read
+	patch := fmt.Sprintf(`{"metadata":{"resourceVersion":%q},"spec":{"replicas":%d}}`, server.ResourceVersion, replicas)
+	_, err = servers.Patch(ctx, name, types.MergePatchType, []byte(patch), metav1.PatchOptions{
+		FieldManager: FieldManager,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scale MCPServer: %w", mapAPIError(err, namespace, name))
+	}
+	return nil
+}
+
+// ListMCPServers lists the MCPServer resources matching the options. The
+// label selector applies to the labels of the resources.
+func (d *CRDDeployer) ListMCPServers(ctx context.Context, opts ListOptions) (*MCPServerList, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %w", opts.LabelSelector, err)
+	}
+	if err := validateSort(opts); err != nil {
+		return nil, err
+	}
+
+	list, err := d.client.Resource(v1alpha1.Resource).Namespace(opts.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+		Limit:         opts.Limit,
+		Continue:      opts.Continue,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MCPServers: %w", mapAPIError(err, opts.Namespace, ""))
+	}
+
+	servers := make([]MCPServerStatus, 0, len(list.Items))
+	for i := range list.Items {
+		server, err := fromUnstructured(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, statusFromResource(server))
+	}
+	sortByNamespaceAndName(servers)
+
+	return &MCPServerList{
+		Items:    filterServers(servers, opts),
+		Continue: list.GetContinue(),
+	}, nil
+}
+
+// GetMCPServer returns the status of a single MCP server as reported on its
+// MCPServer resource
+func (d *CRDDeployer) GetMCPServer(ctx context.Context, namespace, name string) (MCPServerStatus, error) {
+	obj, err := d.client.Resource(v1alpha1.Resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return MCPServerStatus{}, fmt.Errorf("failed to get MCPServer: %w", mapAPIError(err, namespace, name))
+	}
+
+	server, err := fromUnstructured(obj)
+	if err != nil {
+		return MCPServerStatus{}, err
+	}
+	return statusFromResource(server), nil
+}
+
+// WatchMCPServers watches the MCPServer resources in a namespace, or in all
+// namespaces if namespace is empty, and reports changes to their status
+func (d *CRDDeployer) WatchMCPServers(ctx context.Context, namespace string) (<-chan WatchEvent, error) {
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(d.client, 0, namespace, nil)
+	informer := factory.ForResource(v1alpha1.Resource)
+
+	queue := workqueue.New()
+	enqueue := func(obj interface{}) {
+		if key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+			queue.Add(key)
+		}
+	}
+	_, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    enqueue,
+		UpdateFunc: func(_, obj interface{}) { enqueue(obj) },
+		DeleteFunc: enqueue,
+	})
+	if err != nil {
+		queue.ShutDown()
+		return nil, fmt.Errorf("failed to watch MCP servers: %w", err)
+	}
+
+	factory.Start(ctx.Done())
+	for resource, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			queue.ShutDown()
+			factory.Shutdown()
+			return nil, fmt.Errorf("failed to sync %v cache: %w", resource, ctx.Err())
+		}
+	}
+
+	lookup := func(namespace, name string) (MCPServerStatus, error) {
+		obj, err := informer.Lister().ByNamespace(namespace).Get(name)
+		if err != nil {
+			return MCPServerStatus{}, err
+		}
+		server, err := fromUnstructured(obj)
+		if err != nil {
+			return MCPServerStatus{}, err
+		}
+		return statusFromResource(server), nil
+	}
+
+	return runWatch(ctx, queue, lookup, factory.Shutdown), nil
+}
+
+// WaitForMCPServerReady waits until the controller reports the MCP server
+// ready for the latest generation of its MCPServer resource. It returns a
+// *RolloutError if the controller reports a failure that needs intervention.
+func (d *CRDDeployer) WaitForMCPServerReady(ctx context.Context, namespace, name string, opts WaitOptions) error {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	if _, err := d.client.Resource(v1alpha1.Resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{}); err != nil {
+		return fmt.Errorf("failed to get MCPServer: %w", mapAPIError(err, namespace, name))
+	}
+
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(d.client, 0, namespace, func(options *metav1.ListOptions) {
+		options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+	})
+	informer := factory.ForResource(v1alpha1.Resource)
+
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	_, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notify() },
+		UpdateFunc: func(interface{}, interface{}) { notify() },
+		DeleteFunc: func(interface{}) { notify() },
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch MCP server: %w", err)
+	}
+
+	informerCtx, stopInformers := context.WithCancel(ctx)
+	defer func() {
+		stopInformers()
+		factory.Shutdown()
+	}()
+	factory.Start(informerCtx.Done())
+	factory.WaitForCacheSync(informerCtx.Done())
+
+	var last ProgressEvent
+	for {
+		if ctx.Err() != nil {
+			return fmt.Errorf("MCP server %s/%s did not become ready (%s): %w", namespace, name, last.Message, ctx.Err())
+		}
+
+		obj, err := informer.Lister().ByNamespace(namespace).Get(name)
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%w: %s/%s was deleted while waiting for it to become ready", ErrServerNotFound, namespace, name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get MCPServer: %w", err)
+		}
+		server, err := fromUnstructured(obj)
+		if err != nil {
+			return err
+		}
+
+		progress, done, rolloutErr := resourceProgress(server)
+		if rolloutErr != nil {
+			rolloutErr.Namespace, rolloutErr.Name = namespace, name
+			return rolloutErr
+		}
+
+		if progress != last && opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+		last = progress
+
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-changed:
+		}
+	}
+}
+
+// DeleteMCPServer deletes the MCPServer resource. Its Deployment and Service
+// are garbage collected through their owner references, according to the
+// propagation policy.
+func (d *CRDDeployer) DeleteMCPServer(ctx context.Context, namespace, name string, opts DeleteOptions) error {
+	propagation := opts.PropagationPolicy
+	if propagation == "" {
+		propagation = metav1.DeletePropagationBackground
+	}
+
+	err := d.client.Resource(v1alpha1.Resource).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete MCPServer: %w", mapAPIError(err, namespace, name))
+	}
+	return nil
+}
+
+// applyOptions returns the patch options used for server-side apply
+func (d *CRDDeployer) applyOptions() metav1.PatchOptions {
+	force := true
+	return metav1.PatchOptions{
+		FieldManager: FieldManager,
+		Force:        &force,
+	}
+}
+
+// resourceProgress reports the progress of an MCP server from the status of
+// its resource, whether it is ready, and whether the controller has reported
+// a failure that needs intervention
+func resourceProgress(server *v1alpha1.MCPServer) (ProgressEvent, bool, *RolloutError) {
+	progress := ProgressEvent{
+		DesiredReplicas:   server.Status.DesiredReplicas,
+		UpdatedReplicas:   server.Status.UpdatedReplicas,
+		AvailableReplicas: server.Status.AvailableReplicas,
+	}
+
+	if server.Generation > server.Status.ObservedGeneration {
+		progress.Message = "waiting for the controller to observe the update"
+		return progress, false, nil
+	}
+
+	ready := meta.FindStatusCondition(server.Status.Conditions, v1alpha1.ConditionReady)
+	if ready == nil {
+		progress.Message = "waiting for the controller to report status"
+		return progress, false, nil
+	}
+	progress.Message = ready.Message
+
+	switch {
+	case ready.Status == metav1.ConditionTrue:
+		return progress, true, nil
+	case !transientReadyReasons[ready.Reason]:
+		return progress, false, &RolloutError{Reason: ready.Reason, Message: ready.Message}
+	}
+	return progress, false, nil
+}
+
+// resourceFromSpec builds the MCPServer resource declaring the spec. The spec
+// labels are also set on the resource so that it can be selected by them.
+func resourceFromSpec(spec *MCPServerSpec) *v1alpha1.MCPServer {
+	server := &v1alpha1.MCPServer{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       v1alpha1.Kind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      spec.Name,
+			Namespace: spec.Namespace,
+			Labels:    spec.Labels,
+		},
+		Spec: v1alpha1.MCPServerSpec{
+			Image:           spec.Image,
+			Port:            spec.Port,
+			Transport:       string(spec.Transport),
+			Path:            spec.Path,
+			EnvVars:         spec.EnvVars,
+			Command:         spec.Command,
+			Args:            spec.Args,
+			WorkingDir:      spec.WorkingDir,
+			ServiceAccount:  spec.ServiceAccount,
+			Labels:          spec.Labels,
+			Annotations:     spec.Annotations,
+			Resources:       spec.Resources,
+			ReadinessProbe:  resourceProbe(spec.ReadinessProbe),
+			LivenessProbe:   resourceProbe(spec.LivenessProbe),
+			StartupProbe:    resourceProbe(spec.StartupProbe),
+			ProbeImage:      spec.ProbeImage,
+			Bridge:          resourceBridge(spec.Bridge),
+			Package:         resourcePackage(spec.Package),
+			Replicas:        spec.Replicas,
+			Autoscaling:     resourceAutoscaling(spec.Autoscaling),
+			SessionAffinity: resourceSessionAffinity(spec.SessionAffinity),
+		},
+	}
+	for _, secretMount := range spec.SecretMounts {
+		server.Spec.SecretMounts = append(server.Spec.SecretMounts, v1alpha1.SecretMount{
+			SecretName: secretMount.SecretName,
+			MountPath:  secretMount.MountPath,
+		})
+	}
+	return server
+}
+
+// specFromResource returns the spec declared by an MCPServer resource
+func specFromResource(server *v1alpha1.MCPServer) *MCPServerSpec {
+	spec := &MCPServerSpec{
+		Name:            server.Name,
+		Namespace:       server.Namespace,
+		Image:           server.Spec.Image,
+		Port:            server.Spec.Port,
+		Transport:       Transport(server.Spec.Transport),
+		Path:            server.Spec.Path,
+		EnvVars:         server.Spec.EnvVars,
+		Command:         server.Spec.Command,
+		Args:            server.Spec.Args,
+		WorkingDir:      server.Spec.WorkingDir,
+		ServiceAccount:  server.Spec.ServiceAccount,
+		Labels:          server.Spec.Labels,
+		Annotations:     server.Spec.Annotations,
+		Resources:       server.Spec.Resources,
+		ReadinessProbe:  specProbe(server.Spec.ReadinessProbe),
+		LivenessProbe:   specProbe(server.Spec.LivenessProbe),
+		StartupProbe:    specProbe(server.Spec.StartupProbe),
+		ProbeImage:      server.Spec.ProbeImage,
+		Bridge:          specBridge(server.Spec.Bridge),
+		Package:         specPackage(server.Spec.Package),
+		Replicas:        server.Spec.Replicas,
+		Autoscaling:     specAutoscaling(server.Spec.Autoscaling),
+		SessionAffinity: specSessionAffinity(server.Spec.SessionAffinity),
+	}
+	for _, secretMount := range server.Spec.SecretMounts {
+		spec.SecretMounts = append(spec.SecretMounts, SecretMount{
+			SecretName: secretMount.SecretName,
+			MountPath:  secretMount.MountPath,
+		})
+	}
+	return spec
+}
+
+// resourceBridge converts a spec bridge to its MCPServer resource form
+func resourceBridge(bridge *StdioBridge) *v1alpha1.StdioBridge {
+	if bridge == nil {
+		return nil
+	}
+	return &v1alpha1.StdioBridge{
+		Command:  bridge.Command,
+		Sessions: string(bridge.Sessions),
+		Image:    bridge.Image,
+	}
+}
+
+// specBridge converts an MCPServer resource bridge to its spec form
+func specBridge(bridge *v1alpha1.StdioBridge) *StdioBridge {
+	if bridge == nil {
+		return nil
+	}
+	return &StdioBridge{
+		Command:  bridge.Command,
+		Sessions: BridgeSessionMode(bridge.Sessions),
+		Image:    bridge.Image,
+	}
+}
+
+// resourcePackage converts a spec package to its MCPServer resource form
+func resourcePackage(pkg *Package) *v1alpha1.Package {
+	if pkg == nil {
+		return nil
+	}
+	return &v1alpha1.Package{
+		Runtime:    string(pkg.Runtime),
+		Name:       pkg.Name,
+		Version:    pkg.Version,
+		CacheClaim: pkg.CacheClaim,
+	}
+}
+
+// specPackage converts an MCPServer resource package to its spec form
+func specPackage(pkg *v1alpha1.Package) *Package {
+	if pkg == nil {
+		return nil
+	}
+	return &Package{
+		Runtime:    PackageRuntime(pkg.Runtime),
+		Name:       pkg.Name,
+		Version:    pkg.Version,
+		CacheClaim: pkg.CacheClaim,
+	}
+}
+
+// resourceAutoscaling converts spec autoscaling to its MCPServer resource form
+func resourceAutoscaling(autoscaling *Autoscaling) *v1alpha1.Autoscaling {
+	if autoscaling == nil {
+		return nil
+	}
+	return &v1alpha1.Autoscaling{
+		Min:          autoscaling.Min,
+		Max:          autoscaling.Max,
+		TargetCPU:    autoscaling.TargetCPU,
+		TargetMemory: autoscaling.TargetMemory,
+	}
+}
+
+// specAutoscaling converts MCPServer resource autoscaling to its spec form
+func specAutoscaling(autoscaling *v1alpha1.Autoscaling) *Autoscaling {
+	if autoscaling == nil {
+		return nil
+	}
+	return &Autoscaling{
+		Min:          autoscaling.Min,
+		Max:          autoscaling.Max,
+		TargetCPU:    autoscaling.TargetCPU,
+		TargetMemory: autoscaling.TargetMemory,
+	}
+}
+
+// resourceSessionAffinity converts spec session affinity to its MCPServer
+// resource form
+func resourceSessionAffinity(affinity *SessionAffinity) *v1alpha1.SessionAffinity {
+	if affinity == nil {
+		return nil
+	}
+	return &v1alpha1.SessionAffinity{
+		Mode:           string(affinity.Mode),
+		TimeoutSeconds: affinity.TimeoutSeconds,
+		RouterImage:    affinity.RouterImage,
+	}
+}
+
+// specSessionAffinity converts MCPServer resource session affinity to its spec
+// form
+func specSessionAffinity(affinity *v1alpha1.SessionAffinity) *SessionAffinity {
+	if affinity == nil {
+		return nil
+	}
+	return &SessionAffinity{
+		Mode:           SessionAffinityMode(affinity.Mode),
+		TimeoutSeconds: affinity.TimeoutSeconds,
+		RouterImage:    affinity.RouterImage,
+	}
+}
+
+// resourceProbe converts a spec probe to its MCPServer resource form
+func resourceProbe(probe *Probe) *v1alpha1.Probe {
+	if probe == nil {
+		return nil
+	}
+	return &v1alpha1.Probe{
+		Type:                string(probe.Type),
+		Path:                probe.Path,
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		PeriodSeconds:       probe.PeriodSeconds,
+		TimeoutSeconds:      probe.TimeoutSeconds,
+		FailureThreshold:    probe.FailureThreshold,
+	}
+}
+
+// specProbe converts an MCPServer resource probe to its spec form
+func specProbe(probe *v1alpha1.Probe) *Probe {
+	if probe == nil {
+		return nil
+	}
+	return &Probe{
+		Type:                ProbeType(probe.Type),
+		Path:                probe.Path,
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		PeriodSeconds:       probe.PeriodSeconds,
+		TimeoutSeconds:      probe.TimeoutSeconds,
+		FailureThreshold:    probe.FailureThreshold,
+	}
+}
+
+// statusFromResource returns the status of an MCP server as reported on its
+// MCPServer resource
+func statusFromResource(server *v1alpha1.MCPServer) MCPServerStatus {
+	status := server.Status
+
+	phase := MCPServerPhase(status.Phase)
+	if phase == "" {
+		phase = MCPServerPending
+	}
+	spec := specFromResource(server)
+	image := status.Image
+	if image == "" {
+		image = runnerImage(spec)
+	}
+	transport, _ := spec.endpoint()
+	var pkg string
+	if spec.Package != nil {
+		pkg = spec.Package.String()
+	}
+
+	return MCPServerStatus{
+		Name:                  server.Name,
+		Namespace:             server.Namespace,
+		Image:                 image,
+		Package:               pkg,
+		Available:             status.AvailableReplicas > 0,
+		Transport:             transport,
+		Endpoint:              status.Endpoint,
+		Labels:                server.Spec.Labels,
+		Annotations:           server.Spec.Annotations,
+		Conditions:            status.Conditions,
+		Phase:                 phase,
+		DesiredReplicas:       status.DesiredReplicas,
+		ReadyReplicas:         status.ReadyReplicas,
+		UpdatedReplicas:       status.UpdatedReplicas,
+		AvailableReplicas:     status.AvailableReplicas,
+		RestartCount:          status.RestartCount,
+		LastTerminationReason: status.LastTerminationReason,
+		CreationTimestamp:     server.CreationTimestamp,
+		ObservedGeneration:    status.ObservedGeneration,
+	}
+}
+
+// toUnstructured converts an MCPServer resource for the dynamic client
+func toUnstructured(server *v1alpha1.MCPServer) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert MCPServer: %w", err)
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+// fromUnstructured converts an object read through the dynamic client into an
+// MCPServer resource
+func fromUnstructured(obj runtime.Object) (*v1alpha1.MCPServer, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object type %T", obj)
+	}
+
+	server := &v1alpha1.MCPServer{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, server); err != nil {
+		return nil, fmt.Errorf("failed to convert MCPServer %s/%s: %w", u.GetNamespace(), u.GetName(), err)
+	}
+	return server, nil
+}
@@ -0,0 +1,390 @@
+package deployer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/grs/mcp-deployment/pkg/apis/mcp/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newDynamicClient returns a fake dynamic client that serves MCPServer
+// resources, including server-side apply
+func newDynamicClient(t *testing.T, objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	t.Helper()
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{v1alpha1.Resource: v1alpha1.ListKind}, objects...)
+	client.PrependReactor("patch", "mcpservers", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		obj := &unstructured.Unstructured{}
+		if err := json.Unmarshal(patch.GetPatch(), &obj.Object); err != nil {
+			return true, nil, err
+		}
+		obj.SetResourceVersion(fmt.Sprintf("%x", sha256.Sum256(patch.GetPatch()))[:16])
+
+		tracker := client.Tracker()
+		if _, err := tracker.Get(v1alpha1.Resource, patch.GetNamespace(), patch.GetName()); apierrors.IsNotFound(err) {
+			return true, obj, tracker.Create(v1alpha1.Resource, obj, patch.GetNamespace())
+		}
+		return true, obj, tracker.Update(v1alpha1.Resource, obj, patch.GetNamespace())
+	})
+	return client
+}
+
+// testResource returns the MCPServer resource for the test spec with the given
+// status, as stored in the cluster
+func testResource(t *testing.T, status v1alpha1.MCPServerStatus) *unstructured.Unstructured {
+	t.Helper()
+	server := resourceFromSpec(testSpec())
+	server.Status = status
+	obj, err := toUnstructured(server)
+	if err != nil {
+		t.Fatalf("toUnstructured() error = %v", err)
+	}
+	return obj
+}
+
+// readyStatus returns the status the controller reports for a ready server
+func readyStatus() v1alpha1.MCPServerStatus {
+	return v1alpha1.MCPServerStatus{
+		Phase:             string(MCPServerReady),
+		Endpoint:          "http://test-server.test-ns.svc.cluster.local:8080/mcp",
+		DesiredReplicas:   1,
+		ReadyReplicas:     1,
+		UpdatedReplicas:   1,
+		AvailableReplicas: 1,
+		Conditions: []metav1.Condition{
+			{Type: v1alpha1.ConditionReady, Status: metav1.ConditionTrue, Reason: "Ready", Message: "1 of 1 replicas available"},
+		},
+	}
+}
+
+func TestCRDDeployerDeploy(t *testing.T) {
+	client := newDynamicClient(t)
+	d := NewCRDDeployer(client)
+
+	if err := d.DeployMCPServer(context.Background(), testSpec()); err != nil {
+		t.Fatalf("DeployMCPServer() error = %v", err)
+	}
+
+	obj, err := client.Resource(v1alpha1.Resource).Namespace("test-ns").Get(context.Background(), "test-server", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get MCPServer: %v", err)
+	}
+	server, err := fromUnstructured(obj)
+	if err != nil {
+		t.Fatalf("fromUnstructured() error = %v", err)
+	}
+	if server.Spec.Image != "example/mcp-server:1.0" || server.Spec.Port != 8080 || server.Labels["team"] != "platform" {
+		t.Errorf("MCPServer = %+v", server)
+	}
+	if len(server.Spec.SecretMounts) != 1 || server.Spec.SecretMounts[0].MountPath != "/etc/config" {
+		t.Errorf("MCPServer secret mounts = %v", server.Spec.SecretMounts)
+	}
+	if spec := specFromResource(server); spec.Validate() != nil || spec.Resources.Limits.Memory().String() != "256Mi" {
+		t.Errorf("specFromResource() = %+v", spec)
+	}
+
+	if err := d.DeployMCPServer(context.Background(), testSpec()); !errors.Is(err, ErrServerExists) {
+		t.Errorf("second DeployMCPServer() error = %v, want ErrServerExists", err)
+	}
+
+	invalid := testSpec()
+	invalid.Name = "other"
+	invalid.Port = 0
+	var validationErr *ValidationError
+	if err := d.DeployMCPServer(context.Background(), invalid); !errors.As(err, &validationErr) {
+		t.Errorf("DeployMCPServer(invalid) error = %v, want *ValidationError", err)
+	}
+}
+
+func TestCRDDeployerDryRun(t *testing.T) {
+	d := NewCRDDeployer(newDynamicClient(t))
+
+	objs, err := d.DryRunMCPServer(context.Background(), testSpec())
+	if err != nil {
+		t.Fatalf("DryRunMCPServer() error = %v", err)
+	}
+	if len(objs) != 1 {
+		t.Fatalf("DryRunMCPServer() returned %d objects, want 1", len(objs))
+	}
+	if kind := objs[0].GetObjectKind().GroupVersionKind().Kind; kind != v1alpha1.Kind {
+		t.Errorf("object kind = %q, want %q", kind, v1alpha1.Kind)
+	}
+
+	invalid := testSpec()
+	invalid.Port = 0
+	var validationErr *ValidationError
+	if _, err := d.DryRunMCPServer(context.Background(), invalid); !errors.As(err, &validationErr) {
+		t.Errorf("DryRunMCPServer(invalid) error = %v, want *ValidationError", err)
+	}
+}
+
+func TestCRDDeployerApply(t *testing.T) {
+	d := NewCRDDeployer(newDynamicClient(t))
+	spec := testSpec()
+
+	for i, want := range []bool{true, false} {
+		changed, err := d.ApplyMCPServer(context.Background(), spec)
+		if err != nil {
+			t.Fatalf("ApplyMCPServer() #%d error = %v", i+1, err)
+		}
+		if changed != want {
+			t.Errorf("ApplyMCPServer() #%d changed = %t, want %t", i+1, changed, want)
+		}
+	}
+
+	spec.Image = "example/mcp-server:2.0"
+	if changed, err := d.ApplyMCPServer(context.Background(), spec); err != nil || !changed {
+		t.Errorf("ApplyMCPServer(new image) = %t, %v, want true, nil", changed, err)
+	}
+}
+
+func TestCRDDeployerListAndGet(t *testing.T) {
+	other := testResource(t, v1alpha1.MCPServerStatus{})
+	other.SetName("other")
+	other.SetLabels(nil)
+	d := NewCRDDeployer(newDynamicClient(t, testResource(t, readyStatus()), other))
+
+	list, err := d.ListMCPServers(context.Background(), ListOptions{Namespace: "test-ns", LabelSelector: "team=platform"})
+	if err != nil {
+		t.Fatalf("ListMCPServers() error = %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Name != "test-server" {
+		t.Fatalf("ListMCPServers() = %+v, want test-server", list.Items)
+	}
+
+	server, err := d.GetMCPServer(context.Background(), "test-ns", "test-server")
+	if err != nil {
+		t.Fatalf("GetMCPServer() error = %v", err)
+	}
+	if server.Phase != MCPServerReady || !server.Available || server.Endpoint != "http://test-server.test-ns.svc.cluster.local:8080/mcp" || server.Image != "example/mcp-server:1.0" {
+		t.Errorf("GetMCPServer() = %+v", server)
+	}
+
+	pending, err := d.GetMCPServer(context.Background(), "test-ns", "other")
+	if err != nil {
+		t.Fatalf("GetMCPServer(other) error = %v", err)
+	}
+	if pending.Phase != MCPServerPending || pending.Available {
+		t.Errorf("GetMCPServer(other) phase = %s, available = %t, want Pending and unavailable", pending.Phase, pending.Available)
+	}
+
+	if _, err := d.GetMCPServer(context.Background(), "test-ns", "missing"); !errors.Is(err, ErrServerNotFound) {
+		t.Errorf("GetMCPServer(missing) error = %v, want ErrServerNotFound", err)
+	}
+}
+
+func TestCRDDeployerDelete(t *testing.T) {
+	client := newDynamicClient(t, testResource(t, readyStatus()))
+	d := NewCRDDeployer(client)
+
+	if err := d.DeleteMCPServer(context.Background(), "test-ns", "test-server", DeleteOptions{}); err != nil {
+		t.Fatalf("DeleteMCPServer() error = %v", err)
+	}
+	if _, err := client.Resource(v1alpha1.Resource).Namespace("test-ns").Get(context.Background(), "test-server", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("MCPServer still exists after delete: %v", err)
+	}
+	if err := d.DeleteMCPServer(context.Background(), "test-ns", "test-server", DeleteOptions{}); !errors.Is(err, ErrServerNotFound) {
+		t.Errorf("second DeleteMCPServer() error = %v, want ErrServerNotFound", err)
+	}
+}
+
+func TestCRDDeployerWait(t *testing.T) {
+	failed := readyStatus()
+	failed.Phase = string(MCPServerFailed)
+	failed.Conditions[0] = metav1.Condition{Type: v1alpha1.ConditionReady, Status: metav1.ConditionFalse, Reason: "ImagePullBackOff", Message: "pull failed"}
+
+	progressing := readyStatus()
+	progressing.Phase = string(MCPServerProgressing)
+	progressing.Conditions[0] = metav1.Condition{Type: v1alpha1.ConditionReady, Status: metav1.ConditionFalse, Reason: "Progressing", Message: "0 of 1 replicas updated"}
+
+	unobserved := testResource(t, readyStatus())
+	unobserved.SetGeneration(2)
+
+	tests := []struct {
+		name       string
+		obj        *unstructured.Unstructured
+		wantReason string
+		wantErr    error
+	}{
+		{name: "ready", obj: testResource(t, readyStatus())},
+		{name: "failed", obj: testResource(t, failed), wantReason: "ImagePullBackOff"},
+		{name: "progressing", obj: testResource(t, progressing), wantErr: context.DeadlineExceeded},
+		{name: "update not observed", obj: unobserved, wantErr: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewCRDDeployer(newDynamicClient(t, tt.obj))
+			err := d.WaitForMCPServerReady(context.Background(), "test-ns", "test-server", WaitOptions{Timeout: 200 * time.Millisecond})
+
+			var rolloutErr *RolloutError
+			switch {
+			case tt.wantReason != "":
+				if !errors.As(err, &rolloutErr) || rolloutErr.Reason != tt.wantReason {
+					t.Errorf("WaitForMCPServerReady() error = %v, want RolloutError %s", err, tt.wantReason)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("WaitForMCPServerReady() error = %v, want %v", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Errorf("WaitForMCPServerReady() error = %v", err)
+			}
+		})
+	}
+
+	d := NewCRDDeployer(newDynamicClient(t))
+	if err := d.WaitForMCPServerReady(context.Background(), "test-ns", "missing", WaitOptions{}); !errors.Is(err, ErrServerNotFound) {
+		t.Errorf("WaitForMCPServerReady(missing) error = %v, want ErrServerNotFound", err)
+	}
+}
+
+func TestCRDDeployerWatch(t *testing.T) {
+	client := newDynamicClient(t, testResource(t, v1alpha1.MCPServerStatus{}))
+	d := NewCRDDeployer(client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := d.WatchMCPServers(ctx, "test-ns")
+	if err != nil {
+		t.Fatalf("WatchMCPServers() error = %v", err)
+	}
+
+	if event := nextEvent(t, events); event.Type != WatchEventAdded || event.Server.Phase != MCPServerPending {
+		t.Errorf("first event = %s %s, want Added Pending", event.Type, event.Server.Phase)
+	}
+
+	if _, err := client.Resource(v1alpha1.Resource).Namespace("test-ns").UpdateStatus(ctx, testResource(t, readyStatus()), metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+	if event := nextEvent(t, events); event.Type != WatchEventModified || event.Server.Phase != MCPServerReady {
+		t.Errorf("second event = %s %s, want Modified Ready", event.Type, event.Server.Phase)
+	}
+
+	if err := d.DeleteMCPServer(ctx, "test-ns", "test-server", DeleteOptions{}); err != nil {
+		t.Fatalf("DeleteMCPServer() error = %v", err)
+	}
+	if event := nextEvent(t, events); event.Type != WatchEventDeleted {
+		t.Errorf("third event = %s, want Deleted", event.Type)
+	}
+}
+
+// fullSpec returns a spec with every field set
+func fullSpec() *MCPServerSpec {
+	spec := autoscaledSpec()
+	spec.Transport = TransportStdio
+	spec.Path = "/rpc"
+	spec.Command = []string{"node", "dist/index.js"}
+	spec.WorkingDir = "/app"
+	spec.ReadinessProbe = &Probe{Type: ProbeMCP, Path: "/rpc", InitialDelaySeconds: 1, PeriodSeconds: 2, TimeoutSeconds: 3, FailureThreshold: 4}
+	spec.LivenessProbe = &Probe{Type: ProbeTCP}
+	spec.StartupProbe = &Probe{Type: ProbeNone}
+	spec.ProbeImage = "registry.example.com/mcp-probe:v1"
+	spec.Bridge = &StdioBridge{Command: []string{"uvx", "mcp-server-time"}, Sessions: BridgeShared, Image: "example/mcp-bridge:dev"}
+	spec.Package = &Package{Runtime: RuntimeUVX, Name: "mcp-server-fetch", Version: "2025.4.7", CacheClaim: "mcp-packages"}
+	spec.Replicas = int32Ptr(3)
+	spec.Autoscaling.Min = 2
+	spec.SessionAffinity = &SessionAffinity{Mode: AffinityClientIP, TimeoutSeconds: 600, RouterImage: "example/mcp-router:1.0"}
+	return spec
+}
+
+func TestSpecRoundTripsThroughResource(t *testing.T) {
+	full := reflect.ValueOf(*fullSpec())
+	for i := 0; i < full.NumField(); i++ {
+		if full.Field(i).IsZero() {
+			t.Errorf("fullSpec() leaves %s unset", full.Type().Field(i).Name)
+		}
+	}
+
+	tests := []struct {
+		name string
+		spec *MCPServerSpec
+	}{
+		{name: "every field", spec: fullSpec()},
+		{name: "defaults", spec: testSpec()},
+		{
+			name: "package",
+			spec: func() *MCPServerSpec {
+				spec := testSpec()
+				spec.Image = ""
+				spec.Transport = TransportStdio
+				spec.Package = &Package{Runtime: RuntimeNPX, Name: "@modelcontextprotocol/server-everything"}
+				spec.Bridge = &StdioBridge{Sessions: BridgeShared}
+				return spec
+			}(),
+		},
+		{
+			name: "router",
+			spec: func() *MCPServerSpec {
+				spec := testSpec()
+				spec.Replicas = int32Ptr(3)
+				spec.SessionAffinity = &SessionAffinity{Mode: AffinityRouter, RouterImage: "example/mcp-router:1.0"}
+				return spec
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := specFromResource(resourceFromSpec(tt.spec))
+			if !equality.Semantic.DeepEqual(got, tt.spec) {
+				t.Errorf("specFromResource() = %+v, want %+v", got, tt.spec)
+			}
+		})
+	}
+}
+
+func TestCRDDeployerScale(t *testing.T) {
+	autoscaled, err := toUnstructured(func() *v1alpha1.MCPServer {
+		server := resourceFromSpec(autoscaledSpec())
+		server.Name = "autoscaled"
+		return server
+	}())
+	if err != nil {
+		t.Fatalf("toUnstructured() error = %v", err)
+	}
+	client := newDynamicClient(t, testResource(t, readyStatus()), autoscaled)
+	d := NewCRDDeployer(client)
+
+	if err := d.ScaleMCPServer(context.Background(), "test-ns", "test-server", 3); err != nil {
+		t.Fatalf("ScaleMCPServer() error = %v", err)
+	}
+	obj, err := client.Resource(v1alpha1.Resource).Namespace("test-ns").Get(context.Background(), "test-server", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get MCPServer: %v", err)
+	}
+	server, err := fromUnstructured(obj)
+	if err != nil {
+		t.Fatalf("fromUnstructured() error = %v", err)
+	}
+	if server.Spec.Replicas == nil || *server.Spec.Replicas != 3 || server.Spec.Image != "example/mcp-server:1.0" {
+		t.Errorf("MCPServer spec = %+v, want 3 replicas and the rest unchanged", server.Spec)
+	}
+
+	if err := d.ScaleMCPServer(context.Background(), "test-ns", "autoscaled", 3); !errors.Is(err, ErrAutoscaled) {
+		t.Errorf("ScaleMCPServer(autoscaled) error = %v, want ErrAutoscaled", err)
+	}
+	if err := d.ScaleMCPServer(context.Background(), "test-ns", "missing", 3); !errors.Is(err, ErrServerNotFound) {
+		t.Errorf("ScaleMCPServer(missing) error = %v, want ErrServerNotFound", err)
+	}
+}
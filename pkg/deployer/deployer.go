@@ -4,39 +4,300 @@ import (
 	"context"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // SecretMount represents a secret to be mounted in the MCP server pod
 type SecretMount struct {
-	SecretName string
-	MountPath  string
+	SecretName string `json:"secretName"`
+	MountPath  string `json:"mountPath"`
 }
 
-// MCPServerSpec contains the specification for deploying an MCP server
+// Transport is the MCP transport a server speaks
+type Transport string
+
+const (
+	// TransportStreamableHTTP serves MCP over HTTP POST with optional event
+	// streams, on Path. It is the default.
+	TransportStreamableHTTP Transport = "streamable-http"
+
+	// TransportSSE is the older HTTP transport, with an event stream on Path
+	// and messages posted to an endpoint announced on the stream
+	TransportSSE Transport = "sse"
+
+	// TransportStdio serves MCP over the standard input and output of the
+	// server process. The process is started by mcp-bridge, which serves it
+	// with streamable HTTP on Path and SSE on /sse, see StdioBridge.
+	TransportStdio Transport = "stdio"
+)
+
+// BridgeSessionMode selects how mcp-bridge maps client sessions to stdio
+// server processes
+type BridgeSessionMode string
+
+const (
+	// BridgePerSession starts a server process for each client session. It is
+	// the default.
+	BridgePerSession BridgeSessionMode = "per-session"
+
+	// BridgeShared runs one server process for all client sessions of a pod
+	BridgeShared BridgeSessionMode = "shared"
+)
+
+// StdioBridge configures the bridge in front of a stdio server. The bridge
+// replaces the entrypoint of the image, so the server command must be set in
+// the spec or here, unless the server is deployed from a package.
+type StdioBridge struct {
+	// Command starts the stdio server, the Command of the spec if empty; Args
+	// of the spec are appended to it
+	Command []string `json:"command,omitempty"`
+
+	// Sessions is BridgePerSession if empty
+	Sessions BridgeSessionMode `json:"sessions,omitempty"`
+
+	// Image is the image providing the mcp-bridge binary, DefaultBridgeImage
+	// if empty
+	Image string `json:"image,omitempty"`
+}
+
+// PackageRuntime is the tool that downloads and runs a packaged MCP server
+type PackageRuntime string
+
+const (
+	// RuntimeNPX runs npm packages with npx
+	RuntimeNPX PackageRuntime = "npx"
+
+	// RuntimeUVX runs PyPI packages with uvx
+	RuntimeUVX PackageRuntime = "uvx"
+)
+
+// Package deploys a stdio MCP server published to npm or PyPI without
+// building an image for it. The server runs in a generic runner image behind
+// mcp-bridge, with Args appended to the package command.
+type Package struct {
+	Runtime PackageRuntime `json:"runtime"`
+	Name    string         `json:"name"`
+
+	// Version is the latest version if empty
+	Version string `json:"version,omitempty"`
+
+	// CacheClaim is a PersistentVolumeClaim keeping downloaded packages
+	// across restarts. Packages are cached in an emptyDir if empty.
+	CacheClaim string `json:"cacheClaim,omitempty"`
+}
+
+// Autoscaling scales an MCP server with a HorizontalPodAutoscaler between Min
+// and Max replicas, keeping the average utilization of the CPU or memory
+// requested by its pods near a target. At least one target is required, and
+// the resources of the spec must request what is targeted.
+type Autoscaling struct {
+	// Min is the minimum number of replicas, 1 if zero
+	Min int32 `json:"min,omitempty"`
+	Max int32 `json:"max"`
+
+	// TargetCPU is the target average CPU utilization, in percent of the CPU
+	// request; zero does not scale on CPU
+	TargetCPU int32 `json:"targetCPU,omitempty"`
+
+	// TargetMemory is the target average memory utilization, in percent of the
+	// memory request; zero does not scale on memory
+	TargetMemory int32 `json:"targetMemory,omitempty"`
+}
+
+// SessionAffinityMode selects how the requests of an MCP session reach the
+// replica holding the session
+type SessionAffinityMode string
+
+const (
+	// AffinityNone spreads requests over the replicas. It is the default.
+	AffinityNone SessionAffinityMode = "none"
+
+	// AffinityClientIP has the Service send the connections of a client IP to
+	// one replica. Clients behind the same proxy or NAT share a replica.
+	AffinityClientIP SessionAffinityMode = "client-ip"
+
+	// AffinityRouter runs mcp-router beside each replica, which routes every
+	// request by its Mcp-Session-Id header to the replica that issued the
+	// session. It needs the streamable HTTP transport, directly or through the
+	// bridge of a stdio server.
+	AffinityRouter SessionAffinityMode = "router"
+)
+
+// SessionAffinity keeps the requests of stateful MCP sessions on the replica
+// holding the session once a server has more than one
+type SessionAffinity struct {
+	Mode SessionAffinityMode `json:"mode"`
+
+	// TimeoutSeconds is how long a client IP stays on a replica after its
+	// last connection with AffinityClientIP, 10800 (3 hours) if zero
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// RouterImage is the image providing the mcp-router binary for
+	// AffinityRouter, DefaultRouterImage if empty
+	RouterImage string `json:"routerImage,omitempty"`
+}
+
+// ProbeType selects how a probe checks that the MCP server is healthy
+type ProbeType string
+
+const (
+	// ProbeTCP opens a TCP connection to the mcp port. It is the default.
+	ProbeTCP ProbeType = "tcp"
+
+	// ProbeMCP performs an MCP initialize handshake over streamable HTTP with
+	// the mcp-probe binary, which an init container copies into the pod. It
+	// checks stdio servers through their bridge.
+	ProbeMCP ProbeType = "mcp"
+
+	// ProbeNone disables the probe
+	ProbeNone ProbeType = "none"
+)
+
+// Probe configures a readiness, liveness or startup probe. Zero fields take
+// the defaults of the probe, see ReadinessProbe, LivenessProbe and
+// StartupProbe.
+type Probe struct {
+	// Type is the kind of check, ProbeTCP if empty
+	Type ProbeType `json:"type,omitempty"`
+
+	// Path is the path of the MCP endpoint checked by ProbeMCP, the path of
+	// the server if empty
+	Path string `json:"path,omitempty"`
+
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+	PeriodSeconds       int32 `json:"periodSeconds,omitempty"`
+	TimeoutSeconds      int32 `json:"timeoutSeconds,omitempty"`
+	FailureThreshold    int32 `json:"failureThreshold,omitempty"`
+}
+
+// MCPServerSpec contains the specification for deploying an MCP server. The
+// JSON field names are those of spec files, see LoadSpecs. Transport defaults
+// to TransportStreamableHTTP, or TransportStdio with a Package, and Path to the
+// default path of the transport, see DefaultPath. Image is required unless
+// Package is set, when it overrides the runner image of the package runtime.
+// Command replaces the entrypoint of the image and Args its arguments, as in
+// a container; for stdio servers the bridge runs them instead.
 type MCPServerSpec struct {
-	Name             string
-	Namespace        string
-	Image            string
-	Port             int32
-	EnvVars          []corev1.EnvVar
-	Args             []string
-	SecretMounts     []SecretMount
-	ServiceAccount   string
-	Labels           map[string]string
-	Annotations      map[string]string
-	Resources        *corev1.ResourceRequirements
-}
-
-// MCPServerStatus represents the status of a deployed MCP server
+	Name           string                       `json:"name"`
+	Namespace      string                       `json:"namespace,omitempty"`
+	Image          string                       `json:"image"`
+	Port           int32                        `json:"port"`
+	Transport      Transport                    `json:"transport,omitempty"`
+	Path           string                       `json:"path,omitempty"`
+	EnvVars        []corev1.EnvVar              `json:"envVars,omitempty"`
+	Command        []string                     `json:"command,omitempty"`
+	Args           []string                     `json:"args,omitempty"`
+	WorkingDir     string                       `json:"workingDir,omitempty"`
+	SecretMounts   []SecretMount                `json:"secretMounts,omitempty"`
+	ServiceAccount string                       `json:"serviceAccount,omitempty"`
+	Labels         map[string]string            `json:"labels,omitempty"`
+	Annotations    map[string]string            `json:"annotations,omitempty"`
+	Resources      *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// ReadinessProbe decides when the server receives traffic. Nil checks the
+	// mcp port every 10 seconds.
+	ReadinessProbe *Probe `json:"readinessProbe,omitempty"`
+
+	// LivenessProbe decides when the server is restarted. Nil checks the mcp
+	// port every 20 seconds and restarts after 3 failures.
+	LivenessProbe *Probe `json:"livenessProbe,omitempty"`
+
+	// StartupProbe holds off the other probes until the server has started.
+	// Nil checks the mcp port every 2 seconds for up to a minute.
+	StartupProbe *Probe `json:"startupProbe,omitempty"`
+
+	// ProbeImage is the image providing the mcp-probe binary for ProbeMCP
+	// probes, DefaultProbeImage if empty
+	ProbeImage string `json:"probeImage,omitempty"`
+
+	// Bridge configures the mcp-bridge that every stdio server runs behind. It
+	// is optional and only valid with TransportStdio, which needs Command or
+	// Bridge.Command unless Package is set.
+	Bridge *StdioBridge `json:"bridge,omitempty"`
+
+	// Package runs the server from an npm or PyPI package
+	Package *Package `json:"package,omitempty"`
+
+	// Replicas is the number of pods running the server, 1 if nil. It must
+	// not be set with Autoscaling.
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Autoscaling scales the server with a HorizontalPodAutoscaler owned by it
+	Autoscaling *Autoscaling `json:"autoscaling,omitempty"`
+
+	// SessionAffinity keeps sessions on one replica. Nil spreads requests
+	// over the replicas.
+	SessionAffinity *SessionAffinity `json:"sessionAffinity,omitempty"`
+}
+
+// MCPServerPhase summarizes the state of a deployed MCP server
+type MCPServerPhase string
+
+const (
+	// MCPServerPending means no replica of the server is available yet
+	MCPServerPending MCPServerPhase = "Pending"
+
+	// MCPServerProgressing means a rollout is in progress while at least one
+	// replica is available
+	MCPServerProgressing MCPServerPhase = "Progressing"
+
+	// MCPServerReady means the rollout is complete and all replicas are available
+	MCPServerReady MCPServerPhase = "Ready"
+
+	// MCPServerDegraded means the server is available but with fewer replicas
+	// than desired, or some of its pods are failing
+	MCPServerDegraded MCPServerPhase = "Degraded"
+
+	// MCPServerFailed means no replica is available and the rollout has failed
+	MCPServerFailed MCPServerPhase = "Failed"
+)
+
+// MCPServerStatus represents the status of a deployed MCP server. Its JSON
+// encoding is the output format of the wizard and is kept stable. Endpoint is
+// the URL clients connect to with Transport, e.g.
+// http://name.namespace.svc.cluster.local:8080/mcp, or with streamable HTTP
+// for stdio servers, which are served by mcp-bridge. It is empty until the
+// server is available. Package is set for servers deployed from a package, as
+// RUNTIME:NAME[@VERSION], and Image is then the runner image.
 type MCPServerStatus struct {
-	Name        string
-	Namespace   string
-	Image       string
-	Available   bool
-	Endpoint    string
-	Labels      map[string]string
-	Annotations map[string]string
-	Conditions  []string
+	Name        string             `json:"name"`
+	Namespace   string             `json:"namespace"`
+	Image       string             `json:"image"`
+	Package     string             `json:"package,omitempty"`
+	Available   bool               `json:"available"`
+	Transport   Transport          `json:"transport,omitempty"`
+	Endpoint    string             `json:"endpoint,omitempty"`
+	Labels      map[string]string  `json:"labels,omitempty"`
+	Annotations map[string]string  `json:"annotations,omitempty"`
+	Conditions  []metav1.Condition `json:"conditions,omitempty"`
+
+	// Phase is computed from the rollout state and the pods of the server
+	Phase MCPServerPhase `json:"phase"`
+
+	DesiredReplicas   int32 `json:"desiredReplicas"`
+	ReadyReplicas     int32 `json:"readyReplicas"`
+	UpdatedReplicas   int32 `json:"updatedReplicas"`
+	AvailableReplicas int32 `json:"availableReplicas"`
+
+	// RestartCount is the total number of container restarts across all pods
+	RestartCount int32 `json:"restartCount"`
+
+	// LastTerminationReason is the reason the most recently terminated
+	// container stopped, e.g. OOMKilled or Error
+	LastTerminationReason string `json:"lastTerminationReason,omitempty"`
+
+	CreationTimestamp  metav1.Time `json:"creationTimestamp"`
+	ObservedGeneration int64       `json:"observedGeneration"`
+}
+
+// DeleteOptions configures DeleteMCPServer
+type DeleteOptions struct {
+	// PropagationPolicy controls how the pods and Service of the server are
+	// deleted. Background, the default, returns at once and lets the garbage
+	// collector remove them; Foreground keeps the Deployment until its pods are
+	// gone; Orphan leaves the pods and Service behind.
+	PropagationPolicy metav1.DeletionPropagation
 }
 
 // MCPDeployer is the interface for managing MCP server deployments
@@ -44,9 +305,41 @@ type MCPDeployer interface {
 	// DeployMCPServer creates a Deployment and Service for an MCP server
 	DeployMCPServer(ctx context.Context, spec *MCPServerSpec) error
 
-	// ListMCPServers lists all MCP servers in the specified namespace
-	ListMCPServers(ctx context.Context, namespace string) ([]MCPServerStatus, error)
+	// DryRunMCPServer submits the objects for an MCP server to the API server
+	// as a server-side dry run, surfacing validation and admission errors
+	// without persisting anything. It returns the objects as they would be
+	// stored.
+	DryRunMCPServer(ctx context.Context, spec *MCPServerSpec) ([]runtime.Object, error)
+
+	// ApplyMCPServer creates or updates the Deployment and Service for an MCP
+	// server and reports whether anything changed. It is safe to call repeatedly.
+	ApplyMCPServer(ctx context.Context, spec *MCPServerSpec) (bool, error)
+
+	// ScaleMCPServer sets the number of replicas of an MCP server. It returns an
+	// error wrapping ErrServerNotFound if the server does not exist, or
+	// ErrAutoscaled if its replicas are managed by a HorizontalPodAutoscaler.
+	ScaleMCPServer(ctx context.Context, namespace, name string, replicas int32) error
+
+	// ListMCPServers lists the MCP servers matching the options, in one
+	// namespace or all namespaces, one page at a time if opts.Limit is set
+	ListMCPServers(ctx context.Context, opts ListOptions) (*MCPServerList, error)
+
+	// GetMCPServer returns the status of a single MCP server by name. It returns
+	// an error wrapping ErrServerNotFound if the server does not exist.
+	GetMCPServer(ctx context.Context, namespace, name string) (MCPServerStatus, error)
+
+	// WatchMCPServers reports MCP servers in the namespace, or in all namespaces
+	// if namespace is empty, as they are added, modified and deleted. The
+	// channel is closed when the context is done.
+	WatchMCPServers(ctx context.Context, namespace string) (<-chan WatchEvent, error)
+
+	// WaitForMCPServerReady waits until all replicas of an MCP server are
+	// available, reporting progress through opts.OnProgress. It returns a
+	// *RolloutError if the rollout fails.
+	WaitForMCPServerReady(ctx context.Context, namespace, name string, opts WaitOptions) error
 
-	// DeleteMCPServer deletes an MCP server (Deployment and Service) by name
-	DeleteMCPServer(ctx context.Context, namespace, name string) error
+	// DeleteMCPServer deletes an MCP server (Deployment and Service) by name,
+	// tolerating pieces that are already missing. It returns an error wrapping
+	// ErrServerNotFound if the server does not exist.
+	DeleteMCPServer(ctx context.Context, namespace, name string, opts DeleteOptions) error
 }
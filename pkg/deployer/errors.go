@@ -0,0 +1,122 @@
+package deployer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var (
+	// ErrServerExists is returned when an MCP server, or one of the objects
+	// making it up, already exists
+	ErrServerExists = errors.New("MCP server already exists")
+
+	// ErrServerNotFound is returned when no MCP server with the requested name
+	// exists, including when a Deployment of that name exists but is not labeled
+	// as an MCP server
+	ErrServerNotFound = errors.New("MCP server not found")
+
+	// ErrAutoscaled is returned when scaling an MCP server whose replicas are
+	// managed by a HorizontalPodAutoscaler
+	ErrAutoscaled = errors.New("MCP server is autoscaled")
+
+	// ErrForbidden is returned when the client is not permitted to perform an
+	// operation on the cluster
+	ErrForbidden = errors.New("forbidden")
+)
+
+// ValidationError is returned when an MCP server spec is invalid, either
+// because it failed validation in this package or because the API server
+// rejected the objects built from it
+type ValidationError struct {
+	// Errors lists the individual problems with their field paths
+	Errors field.ErrorList
+
+	// Err is the underlying API error, if the API server rejected the spec
+	Err error
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("invalid MCP server spec: %v", e.Err)
+	}
+	return fmt.Sprintf("invalid MCP server spec: %v", e.Errors.ToAggregate())
+}
+
+// Unwrap returns the underlying API error, if any
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// PartialFailureError is returned when an operation failed after it had
+// already changed the cluster, leaving some objects behind
+type PartialFailureError struct {
+	// Remaining describes the objects left in the cluster, e.g. "service ns/name"
+	Remaining []string
+
+	// Err is the failure that interrupted the operation
+	Err error
+}
+
+// Error implements the error interface
+func (e *PartialFailureError) Error() string {
+	return fmt.Sprintf("%v (left in cluster: %s)", e.Err, strings.Join(e.Remaining, ", "))
+}
+
+// Unwrap returns the failure that interrupted the operation
+func (e *PartialFailureError) Unwrap() error {
+	return e.Err
+}
+
+// RolloutError is returned when an MCP server fails to become ready because
+// its rollout or one of its pods has failed
+type RolloutError struct {
+	Namespace string
+	Name      string
+
+	// Reason is a machine readable reason such as ImagePullBackOff,
+	// CrashLoopBackOff, OOMKilled or ProgressDeadlineExceeded
+	Reason string
+
+	// Message describes the failure
+	Message string
+}
+
+// Error implements the error interface
+func (e *RolloutError) Error() string {
+	return fmt.Sprintf("MCP server %s/%s failed to become ready: %s: %s", e.Namespace, e.Name, e.Reason, e.Message)
+}
+
+// mapAPIError translates a Kubernetes API error into the error values of this
+// package. The original error remains available through errors.Is/As.
+func mapAPIError(err error, namespace, name string) error {
+	switch {
+	case err == nil:
+		return nil
+	case apierrors.IsAlreadyExists(err):
+		return fmt.Errorf("%w: %s/%s: %w", ErrServerExists, namespace, name, err)
+	case apierrors.IsNotFound(err):
+		return fmt.Errorf("%w: %s/%s: %w", ErrServerNotFound, namespace, name, err)
+	case apierrors.IsForbidden(err):
+		return fmt.Errorf("%w: %w", ErrForbidden, err)
+	case apierrors.IsInvalid(err):
+		validationErr := &ValidationError{Err: err}
+		var status apierrors.APIStatus
+		if errors.As(err, &status) && status.Status().Details != nil {
+			for _, cause := range status.Status().Details.Causes {
+				validationErr.Errors = append(validationErr.Errors, &field.Error{
+					Type:     field.ErrorType(cause.Type),
+					Field:    cause.Field,
+					BadValue: field.OmitValueType{},
+					Detail:   cause.Message,
+				})
+			}
+		}
+		return validationErr
+	}
+	return err
+}
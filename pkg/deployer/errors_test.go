@@ -0,0 +1,80 @@
+package deployer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestMapAPIError(t *testing.T) {
+	deployments := schema.GroupResource{Group: "apps", Resource: "deployments"}
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "already exists", err: apierrors.NewAlreadyExists(deployments, "test-server"), want: ErrServerExists},
+		{name: "not found", err: apierrors.NewNotFound(deployments, "test-server"), want: ErrServerNotFound},
+		{name: "forbidden", err: apierrors.NewForbidden(deployments, "test-server", fmt.Errorf("denied")), want: ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mapAPIError(tt.err, "test-ns", "test-server")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("mapAPIError() = %v, want %v", err, tt.want)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("mapAPIError() = %v, want to wrap the API error", err)
+			}
+		})
+	}
+}
+
+func TestMapAPIErrorInvalid(t *testing.T) {
+	apiErr := apierrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "Deployment"}, "test-server", field.ErrorList{
+		field.Required(field.NewPath("spec", "template", "spec", "containers").Index(0).Child("image"), ""),
+	})
+
+	err := mapAPIError(apiErr, "test-ns", "test-server")
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("mapAPIError() = %v, want ValidationError", err)
+	}
+	if !apierrors.IsInvalid(err) {
+		t.Errorf("mapAPIError() = %v, want to wrap the Invalid error", err)
+	}
+	if len(validationErr.Errors) != 1 || validationErr.Errors[0].Field != "spec.template.spec.containers[0].image" {
+		t.Errorf("Errors = %v, want the image field", validationErr.Errors)
+	}
+}
+
+func TestMapAPIErrorPassThrough(t *testing.T) {
+	if err := mapAPIError(nil, "test-ns", "test-server"); err != nil {
+		t.Errorf("mapAPIError(nil) = %v, want nil", err)
+	}
+
+	original := fmt.Errorf("connection refused")
+	if err := mapAPIError(original, "test-ns", "test-server"); err != original {
+		t.Errorf("mapAPIError() = %v, want the original error", err)
+	}
+}
+
+func TestPartialFailureError(t *testing.T) {
+	cause := fmt.Errorf("injected failure")
+	err := error(&PartialFailureError{Remaining: []string{"service ns/name"}, Err: cause})
+
+	if !errors.Is(err, cause) {
+		t.Errorf("errors.Is(%v, cause) = false, want true", err)
+	}
+	if !strings.Contains(err.Error(), "service ns/name") {
+		t.Errorf("Error() = %q, want remaining objects listed", err)
+	}
+}
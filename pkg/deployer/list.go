@@ -0,0 +1,165 @@
+package deployer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// SortField is a field by which ListMCPServers can order servers
+type SortField string
+
+const (
+	// SortByName orders servers by namespace and name. This is the default.
+	SortByName SortField = "name"
+
+	// SortByCreationTimestamp orders servers from oldest to newest
+	SortByCreationTimestamp SortField = "creationTimestamp"
+
+	// SortByImage orders servers by image
+	SortByImage SortField = "image"
+)
+
+// ListOptions configures ListMCPServers
+type ListOptions struct {
+	// Namespace restricts the list to one namespace. Empty lists all namespaces.
+	Namespace string
+
+	// LabelSelector further restricts the servers listed, e.g. "team=platform"
+	LabelSelector string
+
+	// Available, if set, keeps only servers that do or do not have available
+	// replicas
+	Available *bool
+
+	// Image, if set, keeps only servers running the image. An image without a
+	// tag or digest matches every tag and digest of that image.
+	Image string
+
+	// SortBy orders the servers. Only SortByName can be combined with Limit.
+	SortBy SortField
+
+	// Limit is the maximum number of servers to fetch per call. Zero fetches
+	// all servers. Because the Available and Image filters are applied after
+	// fetching, a page may hold fewer servers than the limit. The limit may be
+	// ignored when the list is served from the cache.
+	Limit int64
+
+	// Continue is the token returned by a previous call to fetch the next page
+	Continue string
+}
+
+// MCPServerList is a page of MCP servers returned by ListMCPServers
+type MCPServerList struct {
+	Items []MCPServerStatus `json:"items"`
+
+	// Continue is set if more servers remain; pass it in ListOptions.Continue
+	// to fetch them
+	Continue string `json:"continue,omitempty"`
+}
+
+// serverSelector returns the selector matching MCP servers that also satisfy
+// the additional label selector
+func serverSelector(labelSelector string) (labels.Selector, error) {
+	selector := labels.SelectorFromSet(labels.Set{MCPServerLabel: "true"})
+	if labelSelector == "" {
+		return selector, nil
+	}
+
+	extra, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %w", labelSelector, err)
+	}
+	requirements, _ := extra.Requirements()
+	return selector.Add(requirements...), nil
+}
+
+// instanceSelector returns the selector matching the objects of the given
+// servers by their instance label. It returns false if a server was deployed
+// before that label, since its objects may lack it.
+func instanceSelector(deployments []*appsv1.Deployment) (labels.Selector, bool) {
+	names := make([]string, 0, len(deployments))
+	for _, deployment := range deployments {
+		if selector := deployment.Spec.Selector; selector != nil && selector.MatchLabels[InstanceLabel] == "" {
+			return nil, false
+		}
+		names = append(names, deployment.Name)
+	}
+
+	instance, err := labels.NewRequirement(InstanceLabel, selection.In, names)
+	if err != nil {
+		return nil, false
+	}
+	return labels.SelectorFromSet(labels.Set{MCPServerLabel: "true"}).Add(*instance), true
+}
+
+// validateSort checks the sort order of the options. Pages come from the API
+// server in namespace and name order, so other orders would only hold within
+// a page and cannot be combined with a limit.
+func validateSort(opts ListOptions) error {
+	switch opts.SortBy {
+	case "", SortByName:
+		return nil
+	case SortByCreationTimestamp, SortByImage:
+		if opts.Limit > 0 {
+			return fmt.Errorf("cannot sort by %s with a limit, since pages are fetched in name order", opts.SortBy)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid sort field %q", opts.SortBy)
+}
+
+// filterServers returns the servers that pass the Available and Image filters,
+// in the requested order
+func filterServers(servers []MCPServerStatus, opts ListOptions) []MCPServerStatus {
+	filtered := make([]MCPServerStatus, 0, len(servers))
+	for _, server := range servers {
+		if opts.Available != nil && server.Available != *opts.Available {
+			continue
+		}
+		if opts.Image != "" && !imageMatches(server.Image, opts.Image) {
+			continue
+		}
+		filtered = append(filtered, server)
+	}
+
+	// Servers arrive sorted by namespace and name, which breaks ties
+	switch opts.SortBy {
+	case SortByCreationTimestamp:
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return filtered[i].CreationTimestamp.Before(&filtered[j].CreationTimestamp)
+		})
+	case SortByImage:
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return filtered[i].Image < filtered[j].Image
+		})
+	}
+
+	return filtered
+}
+
+// imageMatches reports whether an image matches the image filter, either
+// exactly or as the same image with any tag or digest
+func imageMatches(image, filter string) bool {
+	if image == filter {
+		return true
+	}
+	rest, ok := strings.CutPrefix(image, filter)
+	if !ok || hasTagOrDigest(filter) {
+		return false
+	}
+	return strings.HasPrefix(rest, ":") || strings.HasPrefix(rest, "@")
+}
+
+// hasTagOrDigest reports whether an image reference includes a tag or digest.
+// A colon before the last slash belongs to a registry port.
+func hasTagOrDigest(image string) bool {
+	if strings.Contains(image, "@") {
+		return true
+	}
+	return strings.Contains(image[strings.LastIndex(image, "/")+1:], ":")
+}
@@ -0,0 +1,260 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	typedappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// listTestServers returns servers in two namespaces with varied labels,
+// images, availability and ages
+func listTestServers() []runtime.Object {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := func(namespace, name, image, team string, available int32, age int) *appsv1.Deployment {
+		deployment := testDeployment(namespace, name, available)
+		deployment.Spec.Template.Spec.Containers[0].Image = image
+		deployment.CreationTimestamp = metav1.NewTime(base.Add(time.Duration(age) * time.Hour))
+		if team != "" {
+			deployment.Labels["team"] = team
+		}
+		return deployment
+	}
+	return []runtime.Object{
+		server("team-a", "alpha", "registry:5000/mcp/fetch:1.0", "platform", 1, 3),
+		server("team-a", "bravo", "example/time:2.0", "", 0, 1),
+		server("team-b", "charlie", "registry:5000/mcp/fetch:2.0", "platform", 1, 2),
+		server("team-b", "delta", "registry:5000/mcp/fetch-extra:1.0", "data", 1, 4),
+	}
+}
+
+func TestListMCPServersOptions(t *testing.T) {
+	available, unavailable := true, false
+
+	tests := []struct {
+		name string
+		opts ListOptions
+		want []string
+	}{
+		{name: "one namespace", opts: ListOptions{Namespace: "team-a"}, want: []string{"team-a/alpha", "team-a/bravo"}},
+		{name: "all namespaces", opts: ListOptions{}, want: []string{"team-a/alpha", "team-a/bravo", "team-b/charlie", "team-b/delta"}},
+		{name: "label selector", opts: ListOptions{LabelSelector: "team=platform"}, want: []string{"team-a/alpha", "team-b/charlie"}},
+		{name: "set based label selector", opts: ListOptions{LabelSelector: "team in (data),team notin (platform)"}, want: []string{"team-b/delta"}},
+		{name: "available", opts: ListOptions{Available: &available}, want: []string{"team-a/alpha", "team-b/charlie", "team-b/delta"}},
+		{name: "unavailable", opts: ListOptions{Available: &unavailable}, want: []string{"team-a/bravo"}},
+		{name: "image without tag", opts: ListOptions{Image: "registry:5000/mcp/fetch"}, want: []string{"team-a/alpha", "team-b/charlie"}},
+		{name: "image with tag", opts: ListOptions{Image: "registry:5000/mcp/fetch:2.0"}, want: []string{"team-b/charlie"}},
+		{name: "sort by creation timestamp", opts: ListOptions{SortBy: SortByCreationTimestamp}, want: []string{"team-a/bravo", "team-b/charlie", "team-a/alpha", "team-b/delta"}},
+		{name: "sort by image", opts: ListOptions{SortBy: SortByImage, Namespace: "team-a"}, want: []string{"team-a/bravo", "team-a/alpha"}},
+	}
+
+	for _, cached := range []bool{false, true} {
+		clientset := fake.NewSimpleClientset(listTestServers()...)
+		d := NewSimpleDeployer(clientset)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		if cached {
+			if err := d.StartCache(ctx, ""); err != nil {
+				t.Fatalf("StartCache() error = %v", err)
+			}
+		}
+
+		for _, tt := range tests {
+			name := tt.name
+			if cached {
+				name += " from cache"
+			}
+			t.Run(name, func(t *testing.T) {
+				list, err := d.ListMCPServers(ctx, tt.opts)
+				if err != nil {
+					t.Fatalf("ListMCPServers() error = %v", err)
+				}
+
+				var got []string
+				for _, server := range list.Items {
+					got = append(got, server.Namespace+"/"+server.Name)
+				}
+				if len(got) != len(tt.want) {
+					t.Fatalf("ListMCPServers() = %v, want %v", got, tt.want)
+				}
+				for i := range got {
+					if got[i] != tt.want[i] {
+						t.Fatalf("ListMCPServers() = %v, want %v", got, tt.want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestListMCPServersInvalidOptions(t *testing.T) {
+	d := NewSimpleDeployer(fake.NewSimpleClientset())
+
+	tests := []struct {
+		name string
+		opts ListOptions
+	}{
+		{name: "invalid label selector", opts: ListOptions{LabelSelector: "team in platform"}},
+		{name: "invalid sort field", opts: ListOptions{SortBy: "replicas"}},
+		{name: "sort by creation timestamp with a limit", opts: ListOptions{SortBy: SortByCreationTimestamp, Limit: 2}},
+		{name: "sort by image with a limit", opts: ListOptions{SortBy: SortByImage, Limit: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := d.ListMCPServers(context.Background(), tt.opts); err == nil {
+				t.Error("ListMCPServers() error = nil, want error")
+			}
+		})
+	}
+}
+
+// pagedDeployments serves deployments in pages of two, recording the options
+// of each list, since the fake clientset neither paginates nor passes the
+// limit and continue token on to reactors
+type pagedDeployments struct {
+	typedappsv1.DeploymentInterface
+	options *[]metav1.ListOptions
+}
+
+func (p pagedDeployments) List(ctx context.Context, opts metav1.ListOptions) (*appsv1.DeploymentList, error) {
+	*p.options = append(*p.options, opts)
+	all, err := p.DeploymentInterface.List(ctx, metav1.ListOptions{LabelSelector: opts.LabelSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &appsv1.DeploymentList{}
+	switch opts.Continue {
+	case "":
+		page.Items = all.Items[:2]
+		page.Continue = "page-2"
+	case "page-2":
+		page.Items = all.Items[2:]
+	default:
+		return nil, fmt.Errorf("unexpected continue token %q", opts.Continue)
+	}
+	return page, nil
+}
+
+type pagedApps struct {
+	typedappsv1.AppsV1Interface
+	options *[]metav1.ListOptions
+}
+
+func (p pagedApps) Deployments(namespace string) typedappsv1.DeploymentInterface {
+	return pagedDeployments{p.AppsV1Interface.Deployments(namespace), p.options}
+}
+
+type pagedClientset struct {
+	*fake.Clientset
+	options *[]metav1.ListOptions
+}
+
+func (p pagedClientset) AppsV1() typedappsv1.AppsV1Interface {
+	return pagedApps{p.Clientset.AppsV1(), p.options}
+}
+
+func TestListMCPServersPagination(t *testing.T) {
+	var options []metav1.ListOptions
+	clientset := fake.NewSimpleClientset(listTestServers()...)
+	d := NewSimpleDeployer(pagedClientset{clientset, &options})
+
+	opts := ListOptions{Limit: 2}
+	var names []string
+	for pages := 0; pages < 3; pages++ {
+		clientset.ClearActions()
+		list, err := d.ListMCPServers(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("ListMCPServers() error = %v", err)
+		}
+		page := map[string]bool{}
+		for _, server := range list.Items {
+			names = append(names, server.Name)
+			page[server.Name] = true
+		}
+
+		// Only the Services and pods of the servers on the page are listed
+		listed := 0
+		for _, action := range clientset.Actions() {
+			list, ok := action.(k8stesting.ListAction)
+			if !ok || action.GetResource().Resource == "deployments" {
+				continue
+			}
+			listed++
+			selector := list.GetListRestrictions().Labels
+			for _, server := range listTestServers() {
+				name := server.(*appsv1.Deployment).Name
+				if matches := selector.Matches(labels.Set{MCPServerLabel: "true", InstanceLabel: name}); matches != page[name] {
+					t.Errorf("%s selector %q matches %s = %v, want %v", action.GetResource().Resource, selector, name, matches, page[name])
+				}
+			}
+		}
+		if listed != 2 {
+			t.Errorf("listed %d kinds of objects besides deployments, want services and pods", listed)
+		}
+		if list.Continue == "" {
+			break
+		}
+		opts.Continue = list.Continue
+	}
+
+	if len(names) != 4 {
+		t.Errorf("paginated ListMCPServers() = %v, want 4 servers", names)
+	}
+	if len(options) != 2 {
+		t.Fatalf("deployments listed %d times, want 2", len(options))
+	}
+	for i, wantContinue := range []string{"", "page-2"} {
+		if options[i].Limit != 2 || options[i].Continue != wantContinue {
+			t.Errorf("list %d limit = %d, continue = %q, want 2, %q", i, options[i].Limit, options[i].Continue, wantContinue)
+		}
+	}
+}
+
+func TestInstanceSelector(t *testing.T) {
+	legacy := testDeployment("team-a", "legacy", 1)
+	legacy.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{MCPServerLabel: "true"}}
+	current := testDeployment("team-a", "current", 1)
+	current.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{MCPServerLabel: "true", InstanceLabel: "current"}}
+
+	selector, ok := instanceSelector([]*appsv1.Deployment{current})
+	if !ok || selector.String() != "app.kubernetes.io/instance in (current),mcp.opendatahub.io/mcp-server=true" {
+		t.Errorf("instanceSelector() = %v, %v, want the current server", selector, ok)
+	}
+	if _, ok := instanceSelector([]*appsv1.Deployment{current, legacy}); ok {
+		t.Error("instanceSelector() with a server deployed before the instance label = true, want false")
+	}
+}
+
+func TestImageMatches(t *testing.T) {
+	tests := []struct {
+		image  string
+		filter string
+		want   bool
+	}{
+		{image: "example/fetch:1.0", filter: "example/fetch:1.0", want: true},
+		{image: "example/fetch:1.0", filter: "example/fetch", want: true},
+		{image: "example/fetch@sha256:abc", filter: "example/fetch", want: true},
+		{image: "example/fetch:1.0", filter: "example/fetch:2.0", want: false},
+		{image: "example/fetch-extra:1.0", filter: "example/fetch", want: false},
+		{image: "registry:5000/fetch:1.0", filter: "registry:5000/fetch", want: true},
+		{image: "registry:5000/fetch:1.0", filter: "registry:5000/fetch:1", want: false},
+		{image: "example/fetch", filter: "example/fetch", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := imageMatches(tt.image, tt.filter); got != tt.want {
+			t.Errorf("imageMatches(%q, %q) = %t, want %t", tt.image, tt.filter, got, tt.want)
+		}
+	}
+}
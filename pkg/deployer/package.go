@@ -0,0 +1,73 @@
+package deployer
+
+import corev1 "k8s.io/api/core/v1"
+
+const (
+	// DefaultNPXImage is the runner image of npm packages
+	DefaultNPXImage = "docker.io/library/node:22-slim"
+
+	// DefaultUVXImage is the runner image of PyPI packages
+	DefaultUVXImage = "ghcr.io/astral-sh/uv:python3.12-bookworm-slim"
+
+	// PackageAnnotation records the package of an MCP server deployed from one
+	// on its Deployment and Service, as RUNTIME:NAME[@VERSION]
+	PackageAnnotation = "mcp.opendatahub.io/package"
+
+	// packageCacheVolume holds the downloaded packages
+	packageCacheVolume = "package-cache"
+
+	// packageCacheDir is where packageCacheVolume is mounted
+	packageCacheDir = "/var/cache/mcp-packages"
+)
+
+// reference returns the package as the runner expects it, NAME[@VERSION]
+func (p *Package) reference() string {
+	if p.Version == "" {
+		return p.Name
+	}
+	return p.Name + "@" + p.Version
+}
+
+// String returns the package as RUNTIME:NAME[@VERSION]
+func (p *Package) String() string {
+	return string(p.Runtime) + ":" + p.reference()
+}
+
+// runnerImage returns the image the server container runs: the spec image if
+// set, otherwise the runner image of the package runtime
+func runnerImage(spec *MCPServerSpec) string {
+	if spec.Image != "" || spec.Package == nil {
+		return spec.Image
+	}
+	if spec.Package.Runtime == RuntimeUVX {
+		return DefaultUVXImage
+	}
+	return DefaultNPXImage
+}
+
+// packageCommand returns the command that downloads and runs the package
+func packageCommand(pkg *Package) []string {
+	if pkg.Runtime == RuntimeUVX {
+		return []string{"uvx", pkg.reference()}
+	}
+	// -y installs without asking, as there is no one to answer
+	return []string{"npx", "-y", pkg.reference()}
+}
+
+// packageCache returns the volume caching downloads of the package, a claim if
+// the spec names one and an emptyDir otherwise, and the environment pointing
+// the runner at it
+func packageCache(pkg *Package) (corev1.Volume, []corev1.EnvVar) {
+	volume := corev1.Volume{Name: packageCacheVolume}
+	if pkg.CacheClaim != "" {
+		volume.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pkg.CacheClaim}
+	} else {
+		volume.EmptyDir = &corev1.EmptyDirVolumeSource{}
+	}
+
+	env := corev1.EnvVar{Name: "npm_config_cache", Value: packageCacheDir + "/npm"}
+	if pkg.Runtime == RuntimeUVX {
+		env = corev1.EnvVar{Name: "UV_CACHE_DIR", Value: packageCacheDir + "/uv"}
+	}
+	return volume, []corev1.EnvVar{env}
+}
@@ -0,0 +1,104 @@
+package deployer
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestBuildDeploymentPackage(t *testing.T) {
+	tests := []struct {
+		name      string
+		pkg       *Package
+		image     string
+		wantImage string
+		wantArgs  []string
+		wantEnv   corev1.EnvVar
+		wantClaim string
+	}{
+		{
+			name:      "npm package",
+			pkg:       &Package{Runtime: RuntimeNPX, Name: "@modelcontextprotocol/server-filesystem", Version: "2025.7.1"},
+			wantImage: DefaultNPXImage,
+			wantArgs:  []string{"npx", "-y", "@modelcontextprotocol/server-filesystem@2025.7.1", "--verbose"},
+			wantEnv:   corev1.EnvVar{Name: "npm_config_cache", Value: "/var/cache/mcp-packages/npm"},
+		},
+		{
+			name:      "pypi package with a cache claim",
+			pkg:       &Package{Runtime: RuntimeUVX, Name: "mcp-server-time", CacheClaim: "mcp-packages"},
+			wantImage: DefaultUVXImage,
+			wantArgs:  []string{"uvx", "mcp-server-time", "--verbose"},
+			wantEnv:   corev1.EnvVar{Name: "UV_CACHE_DIR", Value: "/var/cache/mcp-packages/uv"},
+			wantClaim: "mcp-packages",
+		},
+		{
+			name:      "runner image override",
+			pkg:       &Package{Runtime: RuntimeNPX, Name: "mcp-server-kubernetes"},
+			image:     "registry.example.com/node:22",
+			wantImage: "registry.example.com/node:22",
+			wantArgs:  []string{"npx", "-y", "mcp-server-kubernetes", "--verbose"},
+			wantEnv:   corev1.EnvVar{Name: "npm_config_cache", Value: "/var/cache/mcp-packages/npm"},
+		},
+	}
+
+	var d SimpleDeployer
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := testSpec()
+			spec.Image, spec.Package = tt.image, tt.pkg
+
+			deployment := d.buildDeployment(spec)
+			podSpec := deployment.Spec.Template.Spec
+			container := podSpec.Containers[0]
+
+			if container.Image != tt.wantImage {
+				t.Errorf("image = %s, want %s", container.Image, tt.wantImage)
+			}
+			if len(container.Command) == 0 || container.Command[0] != "/var/run/mcp-bridge/mcp-bridge" {
+				t.Errorf("command = %v, want the bridge", container.Command)
+			}
+			if !reflect.DeepEqual(container.Args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", container.Args, tt.wantArgs)
+			}
+			if last := container.Env[len(container.Env)-1]; last != tt.wantEnv || len(spec.EnvVars) != 1 {
+				t.Errorf("env = %v, want %v added to the spec env", container.Env, tt.wantEnv)
+			}
+			if deployment.Annotations[TransportAnnotation] != string(TransportStdio) || deployment.Annotations[PackageAnnotation] != tt.pkg.String() {
+				t.Errorf("annotations = %v, want stdio and the package", deployment.Annotations)
+			}
+
+			var cache *corev1.Volume
+			for i := range podSpec.Volumes {
+				if podSpec.Volumes[i].Name == "package-cache" {
+					cache = &podSpec.Volumes[i]
+				}
+			}
+			switch {
+			case cache == nil:
+				t.Fatal("no package cache volume")
+			case tt.wantClaim == "" && cache.EmptyDir == nil:
+				t.Errorf("cache volume = %+v, want an emptyDir", cache.VolumeSource)
+			case tt.wantClaim != "" && (cache.PersistentVolumeClaim == nil || cache.PersistentVolumeClaim.ClaimName != tt.wantClaim):
+				t.Errorf("cache volume = %+v, want claim %s", cache.VolumeSource, tt.wantClaim)
+			}
+
+			deployment.Status.AvailableReplicas = 1
+			status := buildServerStatus(deployment, d.buildService(spec), nil)
+			if status.Package != tt.pkg.String() || status.Endpoint != "http://test-server.test-ns.svc.cluster.local:8080/mcp" {
+				t.Errorf("status package %q, endpoint %q", status.Package, status.Endpoint)
+			}
+		})
+	}
+}
+
+func TestPackageStatusFromResource(t *testing.T) {
+	spec := testSpec()
+	spec.Image = ""
+	spec.Package = &Package{Runtime: RuntimeUVX, Name: "mcp-server-fetch", Version: "2025.4.7", CacheClaim: "mcp-packages"}
+
+	status := statusFromResource(resourceFromSpec(spec))
+	if status.Image != DefaultUVXImage || status.Package != "uvx:mcp-server-fetch@2025.4.7" || status.Transport != TransportStdio {
+		t.Errorf("statusFromResource() image %q, package %q, transport %q", status.Image, status.Package, status.Transport)
+	}
+}
@@ -0,0 +1,104 @@
+package deployer
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// DefaultProbeImage is the image providing the mcp-probe binary, built from
+	// cmd/mcp-probe
+	DefaultProbeImage = "ghcr.io/grs/mcp-deployment/mcp-probe:latest"
+
+	// probeVolume is the emptyDir the init container copies mcp-probe into
+	probeVolume = "mcp-probe"
+
+	// probeDir is where probeVolume is mounted in both containers
+	probeDir = "/var/run/mcp-probe"
+)
+
+// probeDefaults are the settings of a probe that the spec leaves at zero
+type probeDefaults struct {
+	periodSeconds    int32
+	failureThreshold int32
+}
+
+var (
+	readinessDefaults = probeDefaults{periodSeconds: 10, failureThreshold: 3}
+	livenessDefaults  = probeDefaults{periodSeconds: 20, failureThreshold: 3}
+	startupDefaults   = probeDefaults{periodSeconds: 2, failureThreshold: 30}
+)
+
+// buildProbe builds the container probe for a probe of the spec, or returns
+// nil if the probe is disabled. A nil probe is a TCP probe with the defaults.
+func buildProbe(spec *MCPServerSpec, probe *Probe, defaults probeDefaults) *corev1.Probe {
+	if probe == nil {
+		probe = &Probe{}
+	}
+
+	result := &corev1.Probe{
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		PeriodSeconds:       valueOrDefault(probe.PeriodSeconds, defaults.periodSeconds),
+		TimeoutSeconds:      valueOrDefault(probe.TimeoutSeconds, 1),
+		FailureThreshold:    valueOrDefault(probe.FailureThreshold, defaults.failureThreshold),
+	}
+
+	switch probe.Type {
+	case ProbeNone:
+		return nil
+	case ProbeMCP:
+		// A handshake takes longer than opening a connection
+		result.TimeoutSeconds = valueOrDefault(probe.TimeoutSeconds, 5)
+		path := probe.Path
+		if path == "" {
+			_, path = spec.endpoint()
+		}
+		result.Exec = &corev1.ExecAction{
+			Command: []string{
+				probeDir + "/mcp-probe",
+				"--url", fmt.Sprintf("http://127.0.0.1:%d%s", spec.Port, path),
+				"--timeout", fmt.Sprintf("%ds", result.TimeoutSeconds),
+			},
+		}
+	default:
+		result.TCPSocket = &corev1.TCPSocketAction{Port: intstr.FromString("mcp")}
+	}
+	return result
+}
+
+// usesMCPProbe reports whether any probe of the spec runs mcp-probe
+func usesMCPProbe(spec *MCPServerSpec) bool {
+	for _, probe := range []*Probe{spec.ReadinessProbe, spec.LivenessProbe, spec.StartupProbe} {
+		if probe != nil && probe.Type == ProbeMCP {
+			return true
+		}
+	}
+	return false
+}
+
+// probeInitContainer returns the init container that installs mcp-probe into
+// the shared volume. The binary copies itself, so the image needs no shell.
+func probeInitContainer(spec *MCPServerSpec) corev1.Container {
+	image := spec.ProbeImage
+	if image == "" {
+		image = DefaultProbeImage
+	}
+	return corev1.Container{
+		Name:    "install-mcp-probe",
+		Image:   image,
+		Command: []string{"/mcp-probe", "install", probeDir + "/mcp-probe"},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: probeVolume, MountPath: probeDir},
+		},
+	}
+}
+
+// valueOrDefault returns value, or def if value is zero
+func valueOrDefault(value, def int32) int32 {
+	if value == 0 {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,86 @@
+package deployer
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestBuildDeploymentProbes(t *testing.T) {
+	var d SimpleDeployer
+
+	t.Run("defaults", func(t *testing.T) {
+		deployment := d.buildDeployment(testSpec())
+		podSpec := deployment.Spec.Template.Spec
+		container := podSpec.Containers[0]
+
+		tests := []struct {
+			name             string
+			probe            *corev1.Probe
+			period, failures int32
+		}{
+			{"readiness", container.ReadinessProbe, 10, 3},
+			{"liveness", container.LivenessProbe, 20, 3},
+			{"startup", container.StartupProbe, 2, 30},
+		}
+		for _, tt := range tests {
+			p := tt.probe
+			if p == nil || p.TCPSocket == nil || p.TCPSocket.Port.StrVal != "mcp" {
+				t.Fatalf("%s probe = %+v, want a TCP probe on the mcp port", tt.name, p)
+			}
+			if p.PeriodSeconds != tt.period || p.FailureThreshold != tt.failures || p.TimeoutSeconds != 1 {
+				t.Errorf("%s probe period %d, failures %d, timeout %d; want %d, %d, 1",
+					tt.name, p.PeriodSeconds, p.FailureThreshold, p.TimeoutSeconds, tt.period, tt.failures)
+			}
+		}
+		if len(podSpec.InitContainers) != 0 {
+			t.Errorf("init containers = %v, want none without MCP probes", podSpec.InitContainers)
+		}
+	})
+
+	t.Run("mcp and disabled probes", func(t *testing.T) {
+		spec := testSpec()
+		spec.ReadinessProbe = &Probe{Type: ProbeMCP, Path: "/rpc", PeriodSeconds: 15}
+		spec.LivenessProbe = &Probe{Type: ProbeNone}
+		spec.ProbeImage = "registry.example.com/mcp-probe:v1"
+
+		podSpec := d.buildDeployment(spec).Spec.Template.Spec
+		container := podSpec.Containers[0]
+
+		readiness := container.ReadinessProbe
+		wantCommand := []string{"/var/run/mcp-probe/mcp-probe", "--url", "http://127.0.0.1:8080/rpc", "--timeout", "5s"}
+		if readiness == nil || readiness.Exec == nil || !reflect.DeepEqual(readiness.Exec.Command, wantCommand) {
+			t.Fatalf("readiness probe = %+v, want exec %v", readiness, wantCommand)
+		}
+		if readiness.PeriodSeconds != 15 || readiness.TimeoutSeconds != 5 {
+			t.Errorf("readiness probe period %d, timeout %d; want 15, 5", readiness.PeriodSeconds, readiness.TimeoutSeconds)
+		}
+		if container.LivenessProbe != nil {
+			t.Errorf("liveness probe = %+v, want none", container.LivenessProbe)
+		}
+		if container.StartupProbe == nil || container.StartupProbe.TCPSocket == nil {
+			t.Errorf("startup probe = %+v, want the default TCP probe", container.StartupProbe)
+		}
+
+		if len(podSpec.InitContainers) != 1 {
+			t.Fatalf("init containers = %d, want 1", len(podSpec.InitContainers))
+		}
+		install := podSpec.InitContainers[0]
+		if install.Image != spec.ProbeImage || install.VolumeMounts[0].MountPath != "/var/run/mcp-probe" {
+			t.Errorf("init container = %+v, want %s mounting /var/run/mcp-probe", install, spec.ProbeImage)
+		}
+
+		var mounted bool
+		for _, mount := range container.VolumeMounts {
+			mounted = mounted || (mount.Name == probeVolume && mount.MountPath == "/var/run/mcp-probe")
+		}
+		var volume bool
+		for _, v := range podSpec.Volumes {
+			volume = volume || (v.Name == probeVolume && v.EmptyDir != nil)
+		}
+		if !mounted || !volume {
+			t.Errorf("probe volume mounted %t, declared %t; want both", mounted, volume)
+		}
+	})
+}
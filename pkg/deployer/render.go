@@ -0,0 +1,133 @@
+package deployer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// ManifestFormat is the serialization format used by EncodeManifests
+type ManifestFormat string
+
+const (
+	// ManifestYAML encodes each object as a YAML document, separated by "---"
+	ManifestYAML ManifestFormat = "yaml"
+
+	// ManifestJSON encodes the objects as a single v1 List
+	ManifestJSON ManifestFormat = "json"
+)
+
+// RenderManifests returns the Deployment and Service that DeployMCPServer
+// would create for the spec, the headless Service of a routed server and the
+// HorizontalPodAutoscaler of an autoscaled one, without contacting the
+// cluster. The spec is validated first. The objects besides the Deployment
+// carry no owner reference, since the Deployment has no UID until it is
+// created; tools such as Argo CD that apply the manifests are expected to
+// track and prune the objects themselves.
+func RenderManifests(spec *MCPServerSpec) ([]runtime.Object, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	// The builders do not use the client, so a zero deployer is enough
+	var d SimpleDeployer
+	objs := []runtime.Object{d.buildDeployment(spec), d.buildService(spec)}
+	if usesRouter(spec) {
+		objs = append(objs, d.buildPeersService(spec))
+	}
+	if spec.Autoscaling != nil {
+		objs = append(objs, d.buildHorizontalPodAutoscaler(spec))
+	}
+	return objs, nil
+}
+
+// EncodeManifests serializes objects returned by RenderManifests or
+// DryRunMCPServer. Empty status and creation timestamps are omitted so that
+// the output can be committed and applied as is.
+func EncodeManifests(objs []runtime.Object, format ManifestFormat) ([]byte, error) {
+	items := make([]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		item, err := manifestObject(obj)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	switch format {
+	case ManifestYAML:
+		var buf bytes.Buffer
+		for i, item := range items {
+			data, err := yaml.Marshal(item)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode manifest: %w", err)
+			}
+			if i > 0 {
+				buf.WriteString("---\n")
+			}
+			buf.Write(data)
+		}
+		return buf.Bytes(), nil
+	case ManifestJSON:
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "List",
+			"items":      items,
+		}, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode manifest: %w", err)
+		}
+		return append(data, '\n'), nil
+	}
+	return nil, fmt.Errorf("unsupported manifest format %q", format)
+}
+
+// manifestObject converts an object to its unstructured form, dropping the
+// fields that are only ever empty in a manifest
+func manifestObject(obj runtime.Object) (map[string]interface{}, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %T: %w", obj, err)
+	}
+
+	for _, path := range [][]string{
+		{"metadata", "creationTimestamp"},
+		{"spec", "template", "metadata", "creationTimestamp"},
+	} {
+		if value, found, _ := unstructured.NestedFieldNoCopy(content, path...); found && value == nil {
+			unstructured.RemoveNestedField(content, path...)
+		}
+	}
+	// Empty statuses still carry their fields without omitempty, such as the
+	// load balancer of a Service or the replicas of a HorizontalPodAutoscaler
+	if status, ok := content["status"]; ok && emptyValue(status) {
+		delete(content, "status")
+	}
+
+	return content, nil
+}
+
+// emptyValue reports whether an unstructured value is nil or zero, or a map
+// or slice holding only empty values
+func emptyValue(value interface{}) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		for _, item := range value {
+			if !emptyValue(item) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		return len(value) == 0
+	case int64:
+		return value == 0
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package deployer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestRenderManifests(t *testing.T) {
+	spec := testSpec()
+	objs, err := RenderManifests(spec)
+	if err != nil {
+		t.Fatalf("RenderManifests() error = %v", err)
+	}
+	if len(objs) != 2 {
+		t.Fatalf("RenderManifests() returned %d objects, want 2", len(objs))
+	}
+	rendered, ok := objs[0].(*appsv1.Deployment)
+	if !ok {
+		t.Fatalf("first object = %T, want *appsv1.Deployment", objs[0])
+	}
+	renderedService, ok := objs[1].(*corev1.Service)
+	if !ok {
+		t.Fatalf("second object = %T, want *corev1.Service", objs[1])
+	}
+
+	// The manifests must match what DeployMCPServer creates
+	clientset := fake.NewSimpleClientset()
+	if err := NewSimpleDeployer(clientset).DeployMCPServer(context.Background(), spec); err != nil {
+		t.Fatalf("DeployMCPServer() error = %v", err)
+	}
+	deployment, err := clientset.AppsV1().Deployments(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	service, err := clientset.CoreV1().Services(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get service: %v", err)
+	}
+	if !equality.Semantic.DeepEqual(rendered.Spec, deployment.Spec) || !equality.Semantic.DeepEqual(rendered.Labels, deployment.Labels) {
+		t.Errorf("rendered deployment differs from deployed one:\n%+v\n%+v", rendered, deployment)
+	}
+	if !equality.Semantic.DeepEqual(renderedService.Spec, service.Spec) {
+		t.Errorf("rendered service spec = %+v, want %+v", renderedService.Spec, service.Spec)
+	}
+	if len(renderedService.OwnerReferences) != 0 {
+		t.Errorf("rendered service owner references = %v, want none", renderedService.OwnerReferences)
+	}
+
+	invalid := testSpec()
+	invalid.Image = ""
+	var validationErr *ValidationError
+	if _, err := RenderManifests(invalid); !errors.As(err, &validationErr) {
+		t.Errorf("RenderManifests(invalid) error = %v, want *ValidationError", err)
+	}
+}
+
+func TestRenderManifestsAutoscaled(t *testing.T) {
+	objs, err := RenderManifests(autoscaledSpec())
+	if err != nil {
+		t.Fatalf("RenderManifests() error = %v", err)
+	}
+	if len(objs) != 3 {
+		t.Fatalf("RenderManifests() returned %d objects, want 3", len(objs))
+	}
+	hpa, ok := objs[2].(*autoscalingv2.HorizontalPodAutoscaler)
+	if !ok {
+		t.Fatalf("third object = %T, want *autoscalingv2.HorizontalPodAutoscaler", objs[2])
+	}
+	if len(hpa.OwnerReferences) != 0 {
+		t.Errorf("rendered horizontal pod autoscaler owner references = %v, want none", hpa.OwnerReferences)
+	}
+
+	data, err := EncodeManifests(objs, ManifestYAML)
+	if err != nil {
+		t.Fatalf("EncodeManifests(yaml) error = %v", err)
+	}
+	documents := strings.Split(string(data), "---\n")
+	if len(documents) != 3 || strings.Contains(string(data), "status") {
+		t.Fatalf("YAML = %s, want 3 documents without status", data)
+	}
+	var deployment appsv1.Deployment
+	if err := yaml.UnmarshalStrict([]byte(documents[0]), &deployment); err != nil {
+		t.Fatalf("failed to decode deployment: %v", err)
+	}
+	if deployment.Spec.Replicas != nil {
+		t.Errorf("deployment replicas = %d, want unset for the autoscaler", *deployment.Spec.Replicas)
+	}
+}
+
+func TestRenderManifestsRouted(t *testing.T) {
+	spec := autoscaledSpec()
+	spec.SessionAffinity = &SessionAffinity{Mode: AffinityRouter}
+	objs, err := RenderManifests(spec)
+	if err != nil {
+		t.Fatalf("RenderManifests() error = %v", err)
+	}
+	if len(objs) != 4 {
+		t.Fatalf("RenderManifests() returned %d objects, want 4", len(objs))
+	}
+	peers, ok := objs[2].(*corev1.Service)
+	if !ok || peers.Name != "test-server-peers" {
+		t.Fatalf("third object = %#v, want the peers service", objs[2])
+	}
+	if _, ok := objs[3].(*autoscalingv2.HorizontalPodAutoscaler); !ok {
+		t.Errorf("fourth object = %T, want *autoscalingv2.HorizontalPodAutoscaler", objs[3])
+	}
+
+	data, err := EncodeManifests(objs, ManifestYAML)
+	if err != nil {
+		t.Fatalf("EncodeManifests(yaml) error = %v", err)
+	}
+	if documents := strings.Split(string(data), "---\n"); len(documents) != 4 || strings.Contains(string(data), "loadBalancer") {
+		t.Errorf("YAML = %s, want 4 documents without status", data)
+	}
+}
+
+func TestEncodeManifests(t *testing.T) {
+	objs, err := RenderManifests(testSpec())
+	if err != nil {
+		t.Fatalf("RenderManifests() error = %v", err)
+	}
+
+	data, err := EncodeManifests(objs, ManifestYAML)
+	if err != nil {
+		t.Fatalf("EncodeManifests(yaml) error = %v", err)
+	}
+	documents := strings.Split(string(data), "---\n")
+	if len(documents) != 2 {
+		t.Fatalf("YAML documents = %d, want 2:\n%s", len(documents), data)
+	}
+	for _, unwanted := range []string{"creationTimestamp", "status"} {
+		if strings.Contains(string(data), unwanted) {
+			t.Errorf("YAML contains %q:\n%s", unwanted, data)
+		}
+	}
+	var deployment appsv1.Deployment
+	if err := yaml.UnmarshalStrict([]byte(documents[0]), &deployment); err != nil {
+		t.Fatalf("failed to decode deployment: %v", err)
+	}
+	if deployment.Kind != "Deployment" || deployment.Spec.Template.Spec.Containers[0].Image != "example/mcp-server:1.0" {
+		t.Errorf("decoded deployment = %+v", deployment)
+	}
+	var service corev1.Service
+	if err := yaml.UnmarshalStrict([]byte(documents[1]), &service); err != nil {
+		t.Fatalf("failed to decode service: %v", err)
+	}
+	if service.Kind != "Service" || service.Spec.Ports[0].Port != 8080 {
+		t.Errorf("decoded service = %+v", service)
+	}
+
+	data, err = EncodeManifests(objs, ManifestJSON)
+	if err != nil {
+		t.Fatalf("EncodeManifests(json) error = %v", err)
+	}
+	var list struct {
+		Kind  string            `json:"kind"`
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		t.Fatalf("failed to decode JSON: %v", err)
+	}
+	if list.Kind != "List" || len(list.Items) != 2 {
+		t.Errorf("JSON list kind = %q with %d items, want List with 2", list.Kind, len(list.Items))
+	}
+
+	if _, err := EncodeManifests(objs, "xml"); err == nil {
+		t.Error("EncodeManifests(xml) error = nil, want error")
+	}
+}
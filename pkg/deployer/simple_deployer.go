@@ -2,96 +2,480 @@ package deployer
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/kubernetes"
 )
 
 const (
 	// MCPServerLabel is the label used to identify MCP server deployments
 	MCPServerLabel = "mcp.opendatahub.io/mcp-server"
+
+	// InstanceLabel carries the name of the MCP server on its objects and
+	// pods, so that servers with the same labels select only their own pods
+	InstanceLabel = "app.kubernetes.io/instance"
+
+	// FieldManager is the field manager name used for server-side apply
+	FieldManager = "mcp-deployer"
 )
 
+// createdResource records an object created during a deploy so that it can be
+// removed again if a later step fails
+type createdResource struct {
+	description string
+	delete      func(ctx context.Context) error
+}
+
 // SimpleDeployer implements the MCPDeployer interface using Kubernetes client
 type SimpleDeployer struct {
-	clientset *kubernetes.Clientset
+	clientset kubernetes.Interface
+
+	// cache, if set by StartCache, serves lists and gets without API calls
+	cacheMu sync.RWMutex
+	cache   *serverCache
 }
 
 // NewSimpleDeployer creates a new SimpleDeployer instance
-func NewSimpleDeployer(clientset *kubernetes.Clientset) *SimpleDeployer {
+func NewSimpleDeployer(clientset kubernetes.Interface) *SimpleDeployer {
 	return &SimpleDeployer{
 		clientset: clientset,
 	}
 }
 
-// DeployMCPServer creates a Deployment and Service for an MCP server
+// DeployMCPServer creates a Deployment and Service for an MCP server, a
+// HorizontalPodAutoscaler if it is autoscaled and the headless Service of
+// mcp-router if it has router session affinity. The spec is validated first,
+// and the deploy is all-or-nothing: if any step fails, the objects created so
+// far are deleted.
 func (d *SimpleDeployer) DeployMCPServer(ctx context.Context, spec *MCPServerSpec) error {
-	if err := d.createDeployment(ctx, spec); err != nil {
-		return fmt.Errorf("failed to create deployment: %w", err)
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	if err := d.checkServiceNames(ctx, spec); err != nil {
+		return err
+	}
+
+	var created []createdResource
+
+	deployment, err := d.createDeployment(ctx, spec, metav1.CreateOptions{})
+	if err != nil {
+		return d.rollback(ctx, created, err)
+	}
+	created = append(created, createdResource{
+		description: fmt.Sprintf("deployment %s/%s", spec.Namespace, spec.Name),
+		delete: func(ctx context.Context) error {
+			return d.clientset.AppsV1().Deployments(spec.Namespace).Delete(ctx, spec.Name, d.rollbackOptions())
+		},
+	})
+
+	if _, err := d.createService(ctx, spec, deployment, metav1.CreateOptions{}); err != nil {
+		return d.rollback(ctx, created, err)
 	}
+	created = append(created, createdResource{
+		description: fmt.Sprintf("service %s/%s", spec.Namespace, spec.Name),
+		delete: func(ctx context.Context) error {
+			return d.clientset.CoreV1().Services(spec.Namespace).Delete(ctx, spec.Name, d.rollbackOptions())
+		},
+	})
 
-	if err := d.createService(ctx, spec); err != nil {
-		return fmt.Errorf("failed to create service: %w", err)
+	if usesRouter(spec) {
+		if _, err := d.createPeersService(ctx, spec, deployment, metav1.CreateOptions{}); err != nil {
+			return d.rollback(ctx, created, err)
+		}
+		created = append(created, createdResource{
+			description: fmt.Sprintf("service %s/%s", spec.Namespace, peersServiceName(spec.Name)),
+			delete: func(ctx context.Context) error {
+				return d.clientset.CoreV1().Services(spec.Namespace).Delete(ctx, peersServiceName(spec.Name), d.rollbackOptions())
+			},
+		})
+	}
+
+	if spec.Autoscaling != nil {
+		if _, err := d.createHorizontalPodAutoscaler(ctx, spec, deployment, metav1.CreateOptions{}); err != nil {
+			return d.rollback(ctx, created, err)
+		}
 	}
 
 	return nil
 }
 
-// ListMCPServers lists all MCP servers in the specified namespace
-func (d *SimpleDeployer) ListMCPServers(ctx context.Context, namespace string) ([]MCPServerStatus, error) {
-	listOptions := metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("%s=true", MCPServerLabel),
+// DryRunMCPServer submits the objects for an MCP server to the
+// API server as a server-side dry run. Nothing is persisted, but the objects go
+// through defaulting, validation and admission, so errors that DeployMCPServer
+// would hit are reported. It returns the objects as the API server would store
+// them.
+func (d *SimpleDeployer) DryRunMCPServer(ctx context.Context, spec *MCPServerSpec) ([]runtime.Object, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
 	}
 
-	deployments, err := d.clientset.AppsV1().Deployments(namespace).List(ctx, listOptions)
+	opts := metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}}
+	deployment, err := d.createDeployment(ctx, spec, opts)
+	if err != nil {
+		return nil, err
+	}
+	service, err := d.createService(ctx, spec, deployment, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list deployments: %w", err)
+		return nil, err
+	}
+	objs := []runtime.Object{deployment, service}
+
+	if usesRouter(spec) {
+		peers, err := d.createPeersService(ctx, spec, deployment, opts)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, peers)
 	}
 
-	var servers []MCPServerStatus
-	for _, deployment := range deployments.Items {
-		status := MCPServerStatus{
-			Name:        deployment.Name,
-			Namespace:   deployment.Namespace,
-			Available:   deployment.Status.AvailableReplicas > 0,
-			Labels:      deployment.Labels,
-			Annotations: deployment.Annotations,
+	if spec.Autoscaling != nil {
+		hpa, err := d.createHorizontalPodAutoscaler(ctx, spec, deployment, opts)
+		if err != nil {
+			return nil, err
 		}
+		objs = append(objs, hpa)
+	}
+
+	return objs, nil
+}
 
-		// Extract image from the first container
-		if len(deployment.Spec.Template.Spec.Containers) > 0 {
-			status.Image = deployment.Spec.Template.Spec.Containers[0].Image
+// rollback deletes the resources created during a failed deploy, newest first,
+// and returns an error describing both the failure and what was cleaned up
+func (d *SimpleDeployer) rollback(ctx context.Context, created []createdResource, cause error) error {
+	if len(created) == 0 {
+		return cause
+	}
+
+	// Clean up even if the caller's context was what caused the failure
+	ctx = context.WithoutCancel(ctx)
+
+	var cleaned, remaining []string
+	var cleanupErrs []error
+	for i := len(created) - 1; i >= 0; i-- {
+		resource := created[i]
+		if err := resource.delete(ctx); err != nil && !apierrors.IsNotFound(err) {
+			cleanupErrs = append(cleanupErrs, fmt.Errorf("failed to delete %s: %w", resource.description, err))
+			remaining = append(remaining, resource.description)
+			continue
 		}
+		cleaned = append(cleaned, resource.description)
+	}
+
+	if len(cleanupErrs) > 0 {
+		return &PartialFailureError{
+			Remaining: remaining,
+			Err:       fmt.Errorf("%w; rollback incomplete: %w", cause, errors.Join(cleanupErrs...)),
+		}
+	}
+
+	return fmt.Errorf("%w; rolled back %s", cause, strings.Join(cleaned, ", "))
+}
+
+// ApplyMCPServer creates or updates the Deployment and Service for an MCP server
+// using server-side apply, and reports whether any object was changed. The
+// HorizontalPodAutoscaler and the headless Service of mcp-router are applied
+// too, or deleted once the server is no longer autoscaled or routed.
+func (d *SimpleDeployer) ApplyMCPServer(ctx context.Context, spec *MCPServerSpec) (bool, error) {
+	return d.applyMCPServer(ctx, spec, nil)
+}
+
+// DryRunApplyMCPServer submits what ApplyMCPServer would to the API server as
+// a server-side dry run, and reports whether any object would change. Unlike
+// DryRunMCPServer, it previews updates of existing servers.
+func (d *SimpleDeployer) DryRunApplyMCPServer(ctx context.Context, spec *MCPServerSpec) (bool, error) {
+	return d.applyMCPServer(ctx, spec, []string{metav1.DryRunAll})
+}
+
+// applyMCPServer applies the objects of an MCP server, as a dry run if dryRun
+// is set
+func (d *SimpleDeployer) applyMCPServer(ctx context.Context, spec *MCPServerSpec, dryRun []string) (bool, error) {
+	if err := spec.Validate(); err != nil {
+		return false, err
+	}
+	if err := d.checkServiceNames(ctx, spec); err != nil {
+		return false, err
+	}
+
+	applyOpts := d.applyOptions()
+	applyOpts.DryRun = dryRun
+	deleteOpts := metav1.DeleteOptions{DryRun: dryRun}
+
+	deployment, deploymentChanged, err := d.applyDeployment(ctx, d.buildDeployment(spec), applyOpts)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply deployment: %w", err)
+	}
+
+	service := d.buildService(spec)
+	service.OwnerReferences = d.ownerReferences(deployment)
+	serviceChanged, err := d.applyService(ctx, service, applyOpts)
+	if err != nil {
+		return deploymentChanged, fmt.Errorf("failed to apply service: %w", err)
+	}
+	changed := deploymentChanged || serviceChanged
+
+	var peersChanged bool
+	if usesRouter(spec) {
+		peers := d.buildPeersService(spec)
+		peers.OwnerReferences = d.ownerReferences(deployment)
+		peersChanged, err = d.applyService(ctx, peers, applyOpts)
+		if err != nil {
+			return changed, fmt.Errorf("failed to apply peers service: %w", err)
+		}
+	} else {
+		peersChanged, err = d.removePeersService(ctx, spec.Namespace, spec.Name, deployment, deleteOpts)
+		if err != nil {
+			return changed, err
+		}
+	}
+	changed = changed || peersChanged
+
+	var hpaChanged bool
+	if spec.Autoscaling != nil {
+		hpa := d.buildHorizontalPodAutoscaler(spec)
+		hpa.OwnerReferences = d.ownerReferences(deployment)
+		hpaChanged, err = d.applyHorizontalPodAutoscaler(ctx, hpa, applyOpts)
+		if err != nil {
+			return changed, fmt.Errorf("failed to apply horizontal pod autoscaler: %w", err)
+		}
+	} else {
+		hpaChanged, err = d.removeHorizontalPodAutoscaler(ctx, spec.Namespace, spec.Name, deleteOpts)
+		if err != nil {
+			return changed, err
+		}
+	}
+
+	return changed || hpaChanged, nil
+}
+
+// ScaleMCPServer sets the replicas of the Deployment of an MCP server. The
+// next ApplyMCPServer restores the replicas of the spec.
+func (d *SimpleDeployer) ScaleMCPServer(ctx context.Context, namespace, name string, replicas int32) error {
+	if replicas < 0 {
+		return &ValidationError{Errors: field.ErrorList{
+			field.Invalid(field.NewPath("replicas"), replicas, "must be greater than or equal to 0"),
+		}}
+	}
+
+	deployment, err := d.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get deployment: %w", mapAPIError(err, namespace, name))
+	}
+	if deployment.Labels[MCPServerLabel] != "true" {
+		return fmt.Errorf("%w: %s/%s", ErrServerNotFound, namespace, name)
+	}
+
+	autoscaled, err := d.autoscaled(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+	if autoscaled {
+		return fmt.Errorf("%w: %s/%s", ErrAutoscaled, namespace, name)
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas)
+	_, err = d.clientset.AppsV1().Deployments(namespace).Patch(ctx, name, types.MergePatchType, []byte(patch), metav1.PatchOptions{
+		FieldManager: FieldManager,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scale deployment: %w", mapAPIError(err, namespace, name))
+	}
+	return nil
+}
+
+// ListMCPServers lists the MCP servers matching the options. The Deployments,
+// Services and pods of the servers are each listed once and joined in memory,
+// or read from the cache if one is running. With a limit, only the Services
+// and pods of the servers on the page are listed.
+func (d *SimpleDeployer) ListMCPServers(ctx context.Context, opts ListOptions) (*MCPServerList, error) {
+	selector, err := serverSelector(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSort(opts); err != nil {
+		return nil, err
+	}
+	namespace := opts.Namespace
+
+	// Continue tokens come from the API server, so only first pages are cached
+	if cache := d.cacheFor(namespace); cache != nil && opts.Continue == "" {
+		deployments, err := cache.deployments.Deployments(namespace).List(selector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deployments: %w", err)
+		}
+		services, err := cache.services.Services(namespace).List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services: %w", err)
+		}
+		pods, err := cache.pods.Pods(namespace).List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+		return &MCPServerList{Items: filterServers(joinServers(deployments, services, pods), opts)}, nil
+	}
+
+	deploymentList, err := d.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+		Limit:         opts.Limit,
+		Continue:      opts.Continue,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", mapAPIError(err, namespace, ""))
+	}
+	deployments := make([]*appsv1.Deployment, 0, len(deploymentList.Items))
+	for i := range deploymentList.Items {
+		deployments = append(deployments, &deploymentList.Items[i])
+	}
+
+	if len(deployments) == 0 {
+		return &MCPServerList{Continue: deploymentList.Continue}, nil
+	}
+	listOptions := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=true", MCPServerLabel),
+	}
+	// A page only needs the Services and pods of its own servers
+	if opts.Limit > 0 {
+		if selector, ok := instanceSelector(deployments); ok {
+			listOptions.LabelSelector = selector.String()
+		}
+	}
+
+	serviceList, err := d.clientset.CoreV1().Services(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", mapAPIError(err, namespace, ""))
+	}
+	services := make([]*corev1.Service, 0, len(serviceList.Items))
+	for i := range serviceList.Items {
+		services = append(services, &serviceList.Items[i])
+	}
+
+	podList, err := d.clientset.CoreV1().Pods(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", mapAPIError(err, namespace, ""))
+	}
+	pods := make([]*corev1.Pod, 0, len(podList.Items))
+	for i := range podList.Items {
+		pods = append(pods, &podList.Items[i])
+	}
 
-		// Get the service to extract endpoint (only if deployment is available)
-		if status.Available {
-			service, err := d.clientset.CoreV1().Services(namespace).Get(ctx, deployment.Name, metav1.GetOptions{})
-			if err == nil && len(service.Spec.Ports) > 0 {
-				status.Endpoint = fmt.Sprintf("%s:%d", service.Name, service.Spec.Ports[0].Port)
-			}
+	return &MCPServerList{
+		Items:    filterServers(joinServers(deployments, services, pods), opts),
+		Continue: deploymentList.Continue,
+	}, nil
+}
+
+// GetMCPServer returns the status of a single MCP server by name
+func (d *SimpleDeployer) GetMCPServer(ctx context.Context, namespace, name string) (MCPServerStatus, error) {
+	if cache := d.cacheFor(namespace); cache != nil {
+		// The cache only holds labeled Deployments, so unlabeled ones are missing
+		deployment, err := cache.deployments.Deployments(namespace).Get(name)
+		if err != nil {
+			return MCPServerStatus{}, fmt.Errorf("failed to get deployment: %w", mapAPIError(err, namespace, name))
 		}
+		service, _ := cache.services.Services(namespace).Get(name)
+		pods, err := cache.pods.Pods(namespace).List(labels.Everything())
+		if err != nil {
+			return MCPServerStatus{}, fmt.Errorf("failed to list pods: %w", err)
+		}
+		return buildServerStatus(deployment, service, serverPods(deployment, pods)), nil
+	}
+
+	deployment, err := d.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return MCPServerStatus{}, fmt.Errorf("failed to get deployment: %w", mapAPIError(err, namespace, name))
+	}
 
-		// Extract condition messages
-		for _, condition := range deployment.Status.Conditions {
-			status.Conditions = append(status.Conditions,
-				fmt.Sprintf("%s: %s - %s", condition.Type, condition.Status, condition.Message))
+	// Deployments that are not MCP servers are treated as missing
+	if deployment.Labels[MCPServerLabel] != "true" {
+		return MCPServerStatus{}, fmt.Errorf("%w: %s/%s", ErrServerNotFound, namespace, name)
+	}
+
+	// Get the service to extract endpoint (only if deployment is available)
+	var service *corev1.Service
+	if deployment.Status.AvailableReplicas > 0 {
+		if svc, err := d.clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+			service = svc
 		}
+	}
 
-		servers = append(servers, status)
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return MCPServerStatus{}, fmt.Errorf("invalid deployment selector: %w", err)
+	}
+	podList, err := d.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return MCPServerStatus{}, fmt.Errorf("failed to list pods: %w", mapAPIError(err, namespace, name))
+	}
+	pods := make([]*corev1.Pod, 0, len(podList.Items))
+	for i := range podList.Items {
+		pods = append(pods, &podList.Items[i])
 	}
 
-	return servers, nil
+	return buildServerStatus(deployment, service, serverPods(deployment, pods)), nil
 }
 
 // createDeployment creates a Kubernetes Deployment for the MCP server
-func (d *SimpleDeployer) createDeployment(ctx context.Context, spec *MCPServerSpec) error {
-	labels := d.mergeLabels(spec.Labels)
+func (d *SimpleDeployer) createDeployment(ctx context.Context, spec *MCPServerSpec, opts metav1.CreateOptions) (*appsv1.Deployment, error) {
+	deployment := d.buildDeployment(spec)
+
+	created, err := d.clientset.AppsV1().Deployments(spec.Namespace).Create(ctx, deployment, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deployment: %w", mapAPIError(err, spec.Namespace, spec.Name))
+	}
+
+	return created, nil
+}
+
+// applyDeployment server-side applies a Deployment built for the MCP server and
+// returns the stored object and whether it changed
+func (d *SimpleDeployer) applyDeployment(ctx context.Context, deployment *appsv1.Deployment, opts metav1.PatchOptions) (*appsv1.Deployment, bool, error) {
+	namespace, name := deployment.Namespace, deployment.Name
+	deployments := d.clientset.AppsV1().Deployments(namespace)
+
+	var resourceVersion string
+	existing, err := deployments.Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		resourceVersion = existing.ResourceVersion
+		// Selectors are immutable, so existing Deployments keep theirs. Those
+		// created when the selector held every label, custom ones included,
+		// keep those labels on their pods to stay valid.
+		if selector := existing.Spec.Selector; selector != nil {
+			deployment.Spec.Selector = selector
+			deployment.Spec.Template.Labels = withLabels(deployment.Spec.Template.Labels, selector.MatchLabels)
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return nil, false, fmt.Errorf("failed to get deployment: %w", mapAPIError(err, namespace, name))
+	}
+
+	data, err := json.Marshal(deployment)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode deployment: %w", err)
+	}
+
+	applied, err := deployments.Patch(ctx, name, types.ApplyPatchType, data, opts)
+	if err != nil {
+		return nil, false, mapAPIError(err, namespace, name)
+	}
+
+	return applied, applyChanged(existing, applied, resourceVersion, opts), nil
+}
 
-	replicas := int32(1)
+// buildDeployment builds the Kubernetes Deployment for the MCP server
+func (d *SimpleDeployer) buildDeployment(spec *MCPServerSpec) *appsv1.Deployment {
+	labels := d.mergeLabels(spec)
 
 	// Build volumes and volume mounts from secret mounts
 	var volumes []corev1.Volume
@@ -113,17 +497,92 @@ func (d *SimpleDeployer) createDeployment(ctx context.Context, spec *MCPServerSp
 		})
 	}
 
-	deployment := &appsv1.Deployment{
+	// MCP probes run mcp-probe, installed into a shared volume by an init container
+	var initContainers []corev1.Container
+	if usesMCPProbe(spec) {
+		volumes = append(volumes, corev1.Volume{
+			Name:         probeVolume,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      probeVolume,
+			MountPath: probeDir,
+			ReadOnly:  true,
+		})
+		initContainers = append(initContainers, probeInitContainer(spec))
+	}
+
+	// Stdio servers run behind mcp-bridge, installed the same way
+	if usesBridge(spec) {
+		volumes = append(volumes, corev1.Volume{
+			Name:         bridgeVolume,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      bridgeVolume,
+			MountPath: bridgeDir,
+			ReadOnly:  true,
+		})
+		initContainers = append(initContainers, bridgeInitContainer(spec))
+	}
+	command, args := containerCommand(spec)
+
+	// Packages are downloaded into a cache shared by restarts of the container
+	env := spec.EnvVars
+	if spec.Package != nil {
+		volume, cacheEnv := packageCache(spec.Package)
+		volumes = append(volumes, volume)
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      packageCacheVolume,
+			MountPath: packageCacheDir,
+		})
+		env = append(append([]corev1.EnvVar{}, spec.EnvVars...), cacheEnv...)
+	}
+
+	containers := []corev1.Container{
+		{
+			Name:  "mcp-server",
+			Image: runnerImage(spec),
+			Ports: []corev1.ContainerPort{
+				{
+					Name:          "mcp",
+					ContainerPort: spec.Port,
+					Protocol:      corev1.ProtocolTCP,
+				},
+			},
+			Env:          env,
+			Command:      command,
+			Args:         args,
+			WorkingDir:   spec.WorkingDir,
+			VolumeMounts: volumeMounts,
+			Resources:    d.getResources(spec.Resources),
+
+			ReadinessProbe: buildProbe(spec, spec.ReadinessProbe, readinessDefaults),
+			LivenessProbe:  buildProbe(spec, spec.LivenessProbe, livenessDefaults),
+			StartupProbe:   buildProbe(spec, spec.StartupProbe, startupDefaults),
+		},
+	}
+
+	// Routed servers receive their requests through mcp-router
+	if usesRouter(spec) {
+		containers = append(containers, routerContainer(spec))
+	}
+
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        spec.Name,
 			Namespace:   spec.Namespace,
 			Labels:      labels,
-			Annotations: spec.Annotations,
+			Annotations: d.mergeAnnotations(spec),
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: &replicas,
+			Replicas: deploymentReplicas(spec),
 			Selector: &metav1.LabelSelector{
-				MatchLabels: labels,
+				MatchLabels: selectorLabels(spec),
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
@@ -132,85 +591,193 @@ func (d *SimpleDeployer) createDeployment(ctx context.Context, spec *MCPServerSp
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: spec.ServiceAccount,
-					Containers: []corev1.Container{
-						{
-							Name:  "mcp-server",
-							Image: spec.Image,
-							Ports: []corev1.ContainerPort{
-								{
-									Name:          "mcp",
-									ContainerPort: spec.Port,
-									Protocol:      corev1.ProtocolTCP,
-								},
-							},
-							Env:          spec.EnvVars,
-							Args:         spec.Args,
-							VolumeMounts: volumeMounts,
-							Resources:    d.getResources(spec.Resources),
-						},
-					},
-					Volumes: volumes,
+					InitContainers:     initContainers,
+					Containers:         containers,
+					Volumes:            volumes,
 				},
 			},
 		},
 	}
+}
+
+// createService creates a Kubernetes Service for the MCP server, owned by its
+// Deployment
+func (d *SimpleDeployer) createService(ctx context.Context, spec *MCPServerSpec, deployment *appsv1.Deployment, opts metav1.CreateOptions) (*corev1.Service, error) {
+	service := d.buildService(spec)
+	service.OwnerReferences = d.ownerReferences(deployment)
 
-	_, err := d.clientset.AppsV1().Deployments(spec.Namespace).Create(ctx, deployment, metav1.CreateOptions{})
+	created, err := d.clientset.CoreV1().Services(spec.Namespace).Create(ctx, service, opts)
 	if err != nil {
-		return fmt.Errorf("failed to create deployment: %w", err)
+		return nil, fmt.Errorf("failed to create service: %w", mapAPIError(err, spec.Namespace, spec.Name))
 	}
 
-	return nil
+	return created, nil
+}
+
+// applyService server-side applies a Service built for the MCP server and
+// reports whether the stored object changed. A Service of the same name that
+// belongs to another server is left alone.
+func (d *SimpleDeployer) applyService(ctx context.Context, service *corev1.Service, opts metav1.PatchOptions) (bool, error) {
+	data, err := json.Marshal(service)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode service: %w", err)
+	}
+
+	namespace, name := service.Namespace, service.Name
+	services := d.clientset.CoreV1().Services(namespace)
+
+	var resourceVersion string
+	existing, err := services.Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		if err := checkServiceServer(existing, service.Labels[InstanceLabel]); err != nil {
+			return false, err
+		}
+		resourceVersion = existing.ResourceVersion
+	} else if !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get service: %w", mapAPIError(err, namespace, name))
+	}
+
+	applied, err := services.Patch(ctx, name, types.ApplyPatchType, data, opts)
+	if err != nil {
+		return false, mapAPIError(err, namespace, name)
+	}
+
+	return applyChanged(existing, applied, resourceVersion, opts), nil
 }
 
-// createService creates a Kubernetes Service for the MCP server
-func (d *SimpleDeployer) createService(ctx context.Context, spec *MCPServerSpec) error {
-	labels := d.mergeLabels(spec.Labels)
+// buildService builds the Kubernetes Service for the MCP server
+func (d *SimpleDeployer) buildService(spec *MCPServerSpec) *corev1.Service {
+	labels := d.mergeLabels(spec)
+	affinity, affinityConfig := serviceAffinity(spec)
 
-	service := &corev1.Service{
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        spec.Name,
 			Namespace:   spec.Namespace,
 			Labels:      labels,
-			Annotations: spec.Annotations,
+			Annotations: d.mergeAnnotations(spec),
 		},
 		Spec: corev1.ServiceSpec{
-			Selector: labels,
+			Selector: selectorLabels(spec),
 			Ports: []corev1.ServicePort{
 				{
 					Name:       "mcp",
 					Port:       spec.Port,
-					TargetPort: intstr.FromInt(int(spec.Port)),
+					TargetPort: serviceTargetPort(spec),
 					Protocol:   corev1.ProtocolTCP,
 				},
 			},
-			Type: corev1.ServiceTypeClusterIP,
+			Type:                  corev1.ServiceTypeClusterIP,
+			SessionAffinity:       affinity,
+			SessionAffinityConfig: affinityConfig,
 		},
 	}
+}
 
-	_, err := d.clientset.CoreV1().Services(spec.Namespace).Create(ctx, service, metav1.CreateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to create service: %w", err)
+// DeleteMCPServer deletes an MCP server (Deployment and Service) by name.
+// Pieces that are already missing are skipped; ErrServerNotFound is returned
+// only if neither the Deployment nor the Service exists.
+func (d *SimpleDeployer) DeleteMCPServer(ctx context.Context, namespace, name string, opts DeleteOptions) error {
+	deleteOptions := d.deleteOptions(opts)
+
+	// Delete the deployment. The garbage collector removes its pods, and the
+	// Service and HorizontalPodAutoscaler it owns, according to the propagation policy.
+	err := d.clientset.AppsV1().Deployments(namespace).Delete(ctx, name, deleteOptions)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete deployment: %w", mapAPIError(err, namespace, name))
 	}
+	deploymentFound := err == nil
 
-	return nil
+	if *deleteOptions.PropagationPolicy == metav1.DeletePropagationOrphan {
+		if !deploymentFound {
+			return fmt.Errorf("failed to delete deployment: %w", mapAPIError(err, namespace, name))
+		}
+		return nil
+	}
+
+	// Delete the service directly as well, since Services created before they
+	// were owned by the Deployment are not garbage collected
+	err = d.clientset.CoreV1().Services(namespace).Delete(ctx, name, deleteOptions)
+	switch {
+	case err == nil:
+		return nil
+	case apierrors.IsNotFound(err) && deploymentFound:
+		return nil
+	case apierrors.IsNotFound(err):
+		return fmt.Errorf("%w: %s/%s", ErrServerNotFound, namespace, name)
+	case !deploymentFound:
+		return fmt.Errorf("failed to delete service: %w", mapAPIError(err, namespace, name))
+	}
+	return &PartialFailureError{
+		Remaining: []string{fmt.Sprintf("service %s/%s", namespace, name)},
+		Err:       fmt.Errorf("failed to delete service: %w", mapAPIError(err, namespace, name)),
+	}
 }
 
-// DeleteMCPServer deletes an MCP server (Deployment and Service) by name
-func (d *SimpleDeployer) DeleteMCPServer(ctx context.Context, namespace, name string) error {
-	// Delete the deployment
-	err := d.clientset.AppsV1().Deployments(namespace).Delete(ctx, name, metav1.DeleteOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to delete deployment: %w", err)
+// ownerReferences returns the owner references that make an object owned by
+// the Deployment of the MCP server, so that it is garbage collected with it
+func (d *SimpleDeployer) ownerReferences(deployment *appsv1.Deployment) []metav1.OwnerReference {
+	// Blocking owner deletion would require permission to update the
+	// Deployment's finalizers, which callers may not have
+	blockOwnerDeletion := false
+	owner := metav1.NewControllerRef(deployment, appsv1.SchemeGroupVersion.WithKind("Deployment"))
+	owner.BlockOwnerDeletion = &blockOwnerDeletion
+	return []metav1.OwnerReference{*owner}
+}
+
+// applyOptions returns the patch options used for server-side apply. Conflicts
+// are forced since the deployer owns the objects it manages.
+func (d *SimpleDeployer) applyOptions() metav1.PatchOptions {
+	force := true
+	return metav1.PatchOptions{
+		FieldManager: FieldManager,
+		Force:        &force,
 	}
+}
 
-	// Delete the service
-	err = d.clientset.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to delete service: %w", err)
+// applyChanged reports whether an apply changed the stored object, given the
+// object and its resourceVersion before the apply, which is empty if there was
+// none. Dry runs leave the resourceVersion as it was, so their result is
+// compared with the stored object instead, apart from the managed fields.
+func applyChanged(existing, applied runtime.Object, resourceVersion string, opts metav1.PatchOptions) bool {
+	if resourceVersion == "" {
+		return true
+	}
+	if len(opts.DryRun) == 0 {
+		return applied.(metav1.Object).GetResourceVersion() != resourceVersion
 	}
 
-	return nil
+	before, after := existing.DeepCopyObject(), applied.DeepCopyObject()
+	for _, obj := range []runtime.Object{before, after} {
+		obj.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+		obj.(metav1.Object).SetManagedFields(nil)
+	}
+	return !equality.Semantic.DeepEqual(before, after)
+}
+
+// deleteOptions returns the delete options for DeleteMCPServer, defaulting to
+// background propagation
+func (d *SimpleDeployer) deleteOptions(opts DeleteOptions) metav1.DeleteOptions {
+	propagation := opts.PropagationPolicy
+	if propagation == "" {
+		propagation = metav1.DeletePropagationBackground
+	}
+	return metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	}
+}
+
+// rollbackOptions returns the delete options used when rolling back a failed
+// deploy, removing dependent pods along with the Deployment
+func (d *SimpleDeployer) rollbackOptions() metav1.DeleteOptions {
+	propagation := metav1.DeletePropagationBackground
+	return metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	}
 }
 
 // getResources returns the resource requirements or an empty one if nil
@@ -221,17 +788,41 @@ func (d *SimpleDeployer) getResources(resources *corev1.ResourceRequirements) co
 	return corev1.ResourceRequirements{}
 }
 
-// mergeLabels merges user-provided labels with the required MCP server label
-func (d *SimpleDeployer) mergeLabels(userLabels map[string]string) map[string]string {
+// mergeLabels merges user-provided labels with the required MCP server and
+// instance labels
+func (d *SimpleDeployer) mergeLabels(spec *MCPServerSpec) map[string]string {
 	labels := make(map[string]string)
 
 	// Copy user labels
-	for k, v := range userLabels {
+	for k, v := range spec.Labels {
 		labels[k] = v
 	}
 
-	// Add the required MCP server label
+	// Add the required MCP server and instance labels
 	labels[MCPServerLabel] = "true"
+	labels[InstanceLabel] = spec.Name
 
 	return labels
 }
+
+// selectorLabels returns the labels the Deployment and Services of the MCP
+// server select its pods on. Custom labels are left out, so that changing
+// them does not change the immutable Deployment selector.
+func selectorLabels(spec *MCPServerSpec) map[string]string {
+	return map[string]string{
+		MCPServerLabel: "true",
+		InstanceLabel:  spec.Name,
+	}
+}
+
+// withLabels returns a copy of labels with extra added
+func withLabels(labels, extra map[string]string) map[string]string {
+	merged := make(map[string]string, len(labels)+len(extra))
+	for k, v := range labels {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,829 @@
+package deployer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	typedappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// testSpec returns a fully populated spec for use in tests
+func testSpec() *MCPServerSpec {
+	return &MCPServerSpec{
+		Name:      "test-server",
+		Namespace: "test-ns",
+		Image:     "example/mcp-server:1.0",
+		Port:      8080,
+		EnvVars: []corev1.EnvVar{
+			{Name: "LOG_LEVEL", Value: "debug"},
+		},
+		Args: []string{"--verbose"},
+		SecretMounts: []SecretMount{
+			{SecretName: "config", MountPath: "/etc/config"},
+		},
+		ServiceAccount: "mcp-sa",
+		Labels:         map[string]string{"team": "platform"},
+		Annotations:    map[string]string{"description": "test server"},
+		Resources: &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+		},
+	}
+}
+
+// testDeployment returns a labeled MCP server Deployment as stored in the cluster
+func testDeployment(namespace, name string, availableReplicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{MCPServerLabel: "true"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "mcp-server", Image: "example/" + name + ":latest"},
+					},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{
+			AvailableReplicas: availableReplicas,
+			Conditions: []appsv1.DeploymentCondition{
+				{
+					Type:    appsv1.DeploymentAvailable,
+					Status:  corev1.ConditionTrue,
+					Message: "Deployment has minimum availability.",
+				},
+			},
+		},
+	}
+}
+
+// testService returns an MCP server Service as stored in the cluster
+func testService(namespace, name string, port int32) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{MCPServerLabel: "true"},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "mcp", Port: port}},
+		},
+	}
+}
+
+// failOn returns a reactor that fails every matching action with err
+func failOn(err error) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, err
+	}
+}
+
+// applyReactor emulates server-side apply on the fake clientset, which does not
+// support it natively; other patches are left to the clientset. The applied object replaces the stored one apart from
+// its status, and its resourceVersion is derived from the patch so that
+// identical applies are no-ops.
+func applyReactor(clientset *fake.Clientset) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+
+		var obj interface {
+			runtime.Object
+			metav1.Object
+		}
+		switch action.GetResource().Resource {
+		case "deployments":
+			obj = &appsv1.Deployment{}
+		case "services":
+			obj = &corev1.Service{}
+		case "horizontalpodautoscalers":
+			obj = &autoscalingv2.HorizontalPodAutoscaler{}
+		default:
+			return false, nil, nil
+		}
+		if err := json.Unmarshal(patch.GetPatch(), obj); err != nil {
+			return true, nil, err
+		}
+		obj.SetResourceVersion(fmt.Sprintf("%x", sha256.Sum256(patch.GetPatch()))[:16])
+
+		gvr := action.GetResource()
+		tracker := clientset.Tracker()
+		existing, err := tracker.Get(gvr, patch.GetNamespace(), patch.GetName())
+		if apierrors.IsNotFound(err) {
+			return true, obj, tracker.Create(gvr, obj, patch.GetNamespace())
+		}
+		if err != nil {
+			return true, nil, err
+		}
+		if existing.(metav1.Object).GetResourceVersion() == obj.GetResourceVersion() {
+			return true, existing, nil
+		}
+
+		switch obj := obj.(type) {
+		case *appsv1.Deployment:
+			obj.Status = existing.(*appsv1.Deployment).Status
+		case *corev1.Service:
+			obj.Status = existing.(*corev1.Service).Status
+		case *autoscalingv2.HorizontalPodAutoscaler:
+			obj.Status = existing.(*autoscalingv2.HorizontalPodAutoscaler).Status
+		}
+		return true, obj, tracker.Update(gvr, obj, patch.GetNamespace())
+	}
+}
+
+var (
+	deploymentsResource = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	servicesResource    = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	hpasResource        = schema.GroupVersionResource{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}
+)
+
+func TestDeployMCPServer(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	d := NewSimpleDeployer(clientset)
+	spec := testSpec()
+
+	if err := d.DeployMCPServer(context.Background(), spec); err != nil {
+		t.Fatalf("DeployMCPServer() error = %v", err)
+	}
+
+	deployment, err := clientset.AppsV1().Deployments(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if deployment.Labels[MCPServerLabel] != "true" || deployment.Labels["team"] != "platform" {
+		t.Errorf("deployment labels = %v, want MCP server label and user labels", deployment.Labels)
+	}
+	if *deployment.Spec.Replicas != 1 {
+		t.Errorf("replicas = %d, want 1", *deployment.Spec.Replicas)
+	}
+	if deployment.Spec.Selector.MatchLabels[InstanceLabel] != spec.Name || deployment.Spec.Template.Labels[InstanceLabel] != spec.Name {
+		t.Errorf("selector = %v, template labels = %v, want the instance label", deployment.Spec.Selector.MatchLabels, deployment.Spec.Template.Labels)
+	}
+
+	podSpec := deployment.Spec.Template.Spec
+	if podSpec.ServiceAccountName != "mcp-sa" {
+		t.Errorf("service account = %q, want %q", podSpec.ServiceAccountName, "mcp-sa")
+	}
+	if len(podSpec.Containers) != 1 {
+		t.Fatalf("containers = %d, want 1", len(podSpec.Containers))
+	}
+	container := podSpec.Containers[0]
+	if container.Image != spec.Image {
+		t.Errorf("image = %q, want %q", container.Image, spec.Image)
+	}
+	if len(container.Ports) != 1 || container.Ports[0].ContainerPort != 8080 || container.Ports[0].Name != "mcp" {
+		t.Errorf("ports = %v, want mcp port 8080", container.Ports)
+	}
+	if len(container.Env) != 1 || container.Env[0].Name != "LOG_LEVEL" {
+		t.Errorf("env = %v, want LOG_LEVEL", container.Env)
+	}
+	if len(container.Args) != 1 || container.Args[0] != "--verbose" {
+		t.Errorf("args = %v, want [--verbose]", container.Args)
+	}
+	if len(podSpec.Volumes) != 1 || podSpec.Volumes[0].Secret == nil || podSpec.Volumes[0].Secret.SecretName != "config" {
+		t.Errorf("volumes = %v, want secret volume for config", podSpec.Volumes)
+	}
+	if len(container.VolumeMounts) != 1 || container.VolumeMounts[0].MountPath != "/etc/config" || !container.VolumeMounts[0].ReadOnly {
+		t.Errorf("volume mounts = %v, want read-only mount at /etc/config", container.VolumeMounts)
+	}
+	if memory := container.Resources.Limits[corev1.ResourceMemory]; memory.String() != "256Mi" {
+		t.Errorf("memory limit = %s, want 256Mi", memory.String())
+	}
+
+	service, err := clientset.CoreV1().Services(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get service: %v", err)
+	}
+	if service.Spec.Type != corev1.ServiceTypeClusterIP {
+		t.Errorf("service type = %s, want ClusterIP", service.Spec.Type)
+	}
+	if len(service.Spec.Ports) != 1 || service.Spec.Ports[0].Port != 8080 || service.Spec.Ports[0].TargetPort.IntValue() != 8080 {
+		t.Errorf("service ports = %v, want 8080 -> 8080", service.Spec.Ports)
+	}
+	if service.Spec.Selector[MCPServerLabel] != "true" || service.Spec.Selector[InstanceLabel] != spec.Name {
+		t.Errorf("service selector = %v, want MCP server and instance labels", service.Spec.Selector)
+	}
+	assertOwnedByDeployment(t, service, deployment)
+}
+
+// assertOwnedByDeployment checks that an object is controlled by the
+// Deployment so that it is garbage-collected with it
+func assertOwnedByDeployment(t *testing.T, object metav1.Object, deployment *appsv1.Deployment) {
+	t.Helper()
+	owner := metav1.GetControllerOf(object)
+	if owner == nil {
+		t.Fatalf("%s owner references = %v, want Deployment controller", object.GetName(), object.GetOwnerReferences())
+	}
+	if owner.Kind != "Deployment" || owner.Name != deployment.Name || owner.UID != deployment.UID {
+		t.Errorf("owner = %s/%s (%s), want Deployment/%s (%s)", owner.Kind, owner.Name, owner.UID, deployment.Name, deployment.UID)
+	}
+	if owner.BlockOwnerDeletion != nil && *owner.BlockOwnerDeletion {
+		t.Error("owner blocks owner deletion, want false")
+	}
+}
+
+func TestDeployMCPServerCommand(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	d := NewSimpleDeployer(clientset)
+	spec := testSpec()
+	spec.Image = "registry.example.com/python:3.12"
+	spec.Command = []string{"python", "-m", "weather_mcp"}
+	spec.WorkingDir = "/srv/weather"
+
+	if err := d.DeployMCPServer(context.Background(), spec); err != nil {
+		t.Fatalf("DeployMCPServer() error = %v", err)
+	}
+
+	deployment, err := clientset.AppsV1().Deployments(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	container := deployment.Spec.Template.Spec.Containers[0]
+	if !reflect.DeepEqual(container.Command, spec.Command) || !reflect.DeepEqual(container.Args, spec.Args) {
+		t.Errorf("command = %v, args = %v; want %v, %v", container.Command, container.Args, spec.Command, spec.Args)
+	}
+	if container.WorkingDir != "/srv/weather" {
+		t.Errorf("working dir = %q, want /srv/weather", container.WorkingDir)
+	}
+}
+
+func TestDeployMCPServerAlreadyExists(t *testing.T) {
+	spec := testSpec()
+	clientset := fake.NewSimpleClientset(testDeployment(spec.Namespace, spec.Name, 1))
+	d := NewSimpleDeployer(clientset)
+
+	err := d.DeployMCPServer(context.Background(), spec)
+	if !errors.Is(err, ErrServerExists) || !apierrors.IsAlreadyExists(err) {
+		t.Fatalf("DeployMCPServer() error = %v, want ErrServerExists wrapping AlreadyExists", err)
+	}
+
+	// The pre-existing Deployment must not be rolled back
+	if _, err := clientset.AppsV1().Deployments(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{}); err != nil {
+		t.Errorf("existing deployment was removed: %v", err)
+	}
+}
+
+func TestDeployMCPServerRollsBackOnServiceFailure(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "services", failOn(apierrors.NewForbidden(servicesResource.GroupResource(), "test-server", fmt.Errorf("exceeded quota"))))
+	d := NewSimpleDeployer(clientset)
+	spec := testSpec()
+
+	err := d.DeployMCPServer(context.Background(), spec)
+	if err == nil {
+		t.Fatal("DeployMCPServer() error = nil, want error")
+	}
+	if !errors.Is(err, ErrForbidden) || !apierrors.IsForbidden(err) {
+		t.Errorf("DeployMCPServer() error = %v, want ErrForbidden wrapping Forbidden", err)
+	}
+	if !strings.Contains(err.Error(), "failed to create service") || !strings.Contains(err.Error(), "rolled back deployment test-ns/test-server") {
+		t.Errorf("DeployMCPServer() error = %q, want failure and rollback described", err)
+	}
+
+	_, err = clientset.AppsV1().Deployments(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("deployment still present after rollback: %v", err)
+	}
+}
+
+func TestDeployMCPServerRollbackFailure(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "services", failOn(fmt.Errorf("connection refused")))
+	clientset.PrependReactor("delete", "deployments", failOn(fmt.Errorf("connection reset")))
+	d := NewSimpleDeployer(clientset)
+
+	err := d.DeployMCPServer(context.Background(), testSpec())
+	if err == nil {
+		t.Fatal("DeployMCPServer() error = nil, want error")
+	}
+	if !strings.Contains(err.Error(), "rollback incomplete") || !strings.Contains(err.Error(), "connection reset") {
+		t.Errorf("DeployMCPServer() error = %q, want incomplete rollback described", err)
+	}
+
+	var partialErr *PartialFailureError
+	if !errors.As(err, &partialErr) {
+		t.Fatalf("DeployMCPServer() error = %v, want PartialFailureError", err)
+	}
+	if len(partialErr.Remaining) != 1 || partialErr.Remaining[0] != "deployment test-ns/test-server" {
+		t.Errorf("Remaining = %v, want the created deployment", partialErr.Remaining)
+	}
+}
+
+func TestApplyMCPServer(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("patch", "*", applyReactor(clientset))
+	d := NewSimpleDeployer(clientset)
+	spec := testSpec()
+
+	changed, err := d.ApplyMCPServer(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("ApplyMCPServer() error = %v", err)
+	}
+	if !changed {
+		t.Error("first ApplyMCPServer() changed = false, want true")
+	}
+
+	changed, err = d.ApplyMCPServer(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("ApplyMCPServer() error = %v", err)
+	}
+	if changed {
+		t.Error("repeated ApplyMCPServer() changed = true, want false")
+	}
+
+	spec.Image = "example/mcp-server:2.0"
+	changed, err = d.ApplyMCPServer(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("ApplyMCPServer() error = %v", err)
+	}
+	if !changed {
+		t.Error("ApplyMCPServer() with new image changed = false, want true")
+	}
+
+	deployment, err := clientset.AppsV1().Deployments(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if image := deployment.Spec.Template.Spec.Containers[0].Image; image != spec.Image {
+		t.Errorf("image = %q, want %q", image, spec.Image)
+	}
+
+	service, err := clientset.CoreV1().Services(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get service: %v", err)
+	}
+	assertOwnedByDeployment(t, service, deployment)
+
+	for _, action := range clientset.Actions() {
+		if patch, ok := action.(k8stesting.PatchAction); ok && string(patch.GetPatchType()) != "application/apply-patch+yaml" {
+			t.Errorf("patch type = %s, want apply patch", patch.GetPatchType())
+		}
+	}
+}
+
+func TestApplyMCPServerChangedLabels(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("patch", "*", applyReactor(clientset))
+	d := NewSimpleDeployer(clientset)
+	spec := testSpec()
+
+	if _, err := d.ApplyMCPServer(context.Background(), spec); err != nil {
+		t.Fatalf("ApplyMCPServer() error = %v", err)
+	}
+
+	spec.Labels = map[string]string{"team": "infra", "tier": "gold"}
+	changed, err := d.ApplyMCPServer(context.Background(), spec)
+	if err != nil || !changed {
+		t.Fatalf("ApplyMCPServer() with new labels = %v, %v, want changed", changed, err)
+	}
+
+	deployment, err := clientset.AppsV1().Deployments(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	wantSelector := map[string]string{MCPServerLabel: "true", InstanceLabel: spec.Name}
+	if !reflect.DeepEqual(deployment.Spec.Selector.MatchLabels, wantSelector) {
+		t.Errorf("selector = %v, want %v", deployment.Spec.Selector.MatchLabels, wantSelector)
+	}
+	if labels := deployment.Spec.Template.Labels; labels["team"] != "infra" || labels["tier"] != "gold" {
+		t.Errorf("template labels = %v, want the new labels", labels)
+	}
+
+	service, err := clientset.CoreV1().Services(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get service: %v", err)
+	}
+	if !reflect.DeepEqual(service.Spec.Selector, wantSelector) || service.Labels["team"] != "infra" {
+		t.Errorf("service labels = %v, selector = %v, want the new labels selecting on %v", service.Labels, service.Spec.Selector, wantSelector)
+	}
+}
+
+func TestApplyMCPServerKeepsSelector(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("patch", "*", applyReactor(clientset))
+	d := NewSimpleDeployer(clientset)
+	spec := testSpec()
+
+	// A Deployment created before the instance label, whose selector cannot change
+	legacy := d.buildDeployment(spec)
+	legacy.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{MCPServerLabel: "true", "team": "platform"}}
+	if _, err := clientset.AppsV1().Deployments(spec.Namespace).Create(context.Background(), legacy, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create deployment: %v", err)
+	}
+
+	if _, err := d.ApplyMCPServer(context.Background(), spec); err != nil {
+		t.Fatalf("ApplyMCPServer() error = %v", err)
+	}
+	deployment, err := clientset.AppsV1().Deployments(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if _, ok := deployment.Spec.Selector.MatchLabels[InstanceLabel]; ok {
+		t.Errorf("selector = %v, want the existing selector", deployment.Spec.Selector.MatchLabels)
+	}
+	if deployment.Spec.Template.Labels[InstanceLabel] != spec.Name {
+		t.Errorf("template labels = %v, want the instance label", deployment.Spec.Template.Labels)
+	}
+
+	// The pods keep matching the existing selector when the labels change
+	spec.Labels = map[string]string{"team": "infra"}
+	if _, err := d.ApplyMCPServer(context.Background(), spec); err != nil {
+		t.Fatalf("ApplyMCPServer() with new labels error = %v", err)
+	}
+	deployment, err = clientset.AppsV1().Deployments(spec.Namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil || !selector.Matches(labels.Set(deployment.Spec.Template.Labels)) {
+		t.Errorf("template labels = %v, want them matching the selector %v", deployment.Spec.Template.Labels, deployment.Spec.Selector.MatchLabels)
+	}
+}
+
+func TestListMCPServers(t *testing.T) {
+	unlabeled := testDeployment("test-ns", "not-mcp", 1)
+	unlabeled.Labels = map[string]string{"app": "other"}
+
+	clientset := fake.NewSimpleClientset(
+		testDeployment("test-ns", "ready", 1),
+		testService("test-ns", "ready", 8080),
+		testDeployment("test-ns", "starting", 0),
+		testService("test-ns", "starting", 9090),
+		testDeployment("other-ns", "elsewhere", 1),
+		unlabeled,
+	)
+	d := NewSimpleDeployer(clientset)
+
+	list, err := d.ListMCPServers(context.Background(), ListOptions{Namespace: "test-ns"})
+	if err != nil {
+		t.Fatalf("ListMCPServers() error = %v", err)
+	}
+	servers := list.Items
+	if len(servers) != 2 {
+		t.Fatalf("ListMCPServers() returned %d servers, want 2: %v", len(servers), servers)
+	}
+
+	byName := make(map[string]MCPServerStatus)
+	for _, server := range servers {
+		byName[server.Name] = server
+	}
+
+	ready := byName["ready"]
+	if !ready.Available {
+		t.Error("ready.Available = false, want true")
+	}
+	if ready.Endpoint != "http://ready.test-ns.svc.cluster.local:8080/mcp" {
+		t.Errorf("ready.Endpoint = %q, want %q", ready.Endpoint, "http://ready.test-ns.svc.cluster.local:8080/mcp")
+	}
+	if ready.Image != "example/ready:latest" {
+		t.Errorf("ready.Image = %q, want %q", ready.Image, "example/ready:latest")
+	}
+	if len(ready.Conditions) != 1 || ready.Conditions[0].Type != "Available" || ready.Conditions[0].Status != metav1.ConditionTrue ||
+		ready.Conditions[0].Message != "Deployment has minimum availability." {
+		t.Errorf("ready.Conditions = %v", ready.Conditions)
+	}
+
+	starting := byName["starting"]
+	if starting.Available {
+		t.Error("starting.Available = true, want false")
+	}
+	if starting.Endpoint != "" {
+		t.Errorf("starting.Endpoint = %q, want empty for unavailable server", starting.Endpoint)
+	}
+}
+
+func TestListMCPServersError(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("list", "deployments", failOn(apierrors.NewForbidden(deploymentsResource.GroupResource(), "", fmt.Errorf("no access"))))
+	d := NewSimpleDeployer(clientset)
+
+	_, err := d.ListMCPServers(context.Background(), ListOptions{Namespace: "test-ns"})
+	if !errors.Is(err, ErrForbidden) {
+		t.Fatalf("ListMCPServers() error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestGetMCPServer(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		testDeployment("test-ns", "test-server", 1),
+		testService("test-ns", "test-server", 8080),
+	)
+	d := NewSimpleDeployer(clientset)
+
+	server, err := d.GetMCPServer(context.Background(), "test-ns", "test-server")
+	if err != nil {
+		t.Fatalf("GetMCPServer() error = %v", err)
+	}
+	if server.Name != "test-server" || server.Namespace != "test-ns" {
+		t.Errorf("GetMCPServer() = %s/%s, want test-ns/test-server", server.Namespace, server.Name)
+	}
+	if !server.Available || server.Endpoint != "http://test-server.test-ns.svc.cluster.local:8080/mcp" {
+		t.Errorf("GetMCPServer() available = %t, endpoint = %q", server.Available, server.Endpoint)
+	}
+}
+
+func TestGetMCPServerNotFound(t *testing.T) {
+	unlabeled := testDeployment("test-ns", "not-mcp", 1)
+	unlabeled.Labels = nil
+
+	tests := []struct {
+		name       string
+		serverName string
+	}{
+		{name: "missing deployment", serverName: "missing"},
+		{name: "unlabeled deployment", serverName: "not-mcp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewSimpleDeployer(fake.NewSimpleClientset(unlabeled))
+
+			_, err := d.GetMCPServer(context.Background(), "test-ns", tt.serverName)
+			if !errors.Is(err, ErrServerNotFound) {
+				t.Errorf("GetMCPServer() error = %v, want ErrServerNotFound", err)
+			}
+		})
+	}
+}
+
+func TestGetMCPServerError(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("get", "deployments", failOn(fmt.Errorf("connection refused")))
+	d := NewSimpleDeployer(clientset)
+
+	_, err := d.GetMCPServer(context.Background(), "test-ns", "test-server")
+	if err == nil || errors.Is(err, ErrServerNotFound) {
+		t.Errorf("GetMCPServer() error = %v, want non-not-found error", err)
+	}
+}
+
+func TestDeleteMCPServer(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		testDeployment("test-ns", "test-server", 1),
+		testService("test-ns", "test-server", 8080),
+	)
+	d := NewSimpleDeployer(clientset)
+
+	if err := d.DeleteMCPServer(context.Background(), "test-ns", "test-server", DeleteOptions{}); err != nil {
+		t.Fatalf("DeleteMCPServer() error = %v", err)
+	}
+
+	if _, err := clientset.AppsV1().Deployments("test-ns").Get(context.Background(), "test-server", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("deployment still present: %v", err)
+	}
+	if _, err := clientset.CoreV1().Services("test-ns").Get(context.Background(), "test-server", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("service still present: %v", err)
+	}
+}
+
+func TestDeleteMCPServerErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		objects       []runtime.Object
+		verb          string
+		object        string
+		wantNotFound  bool
+		wantRemaining []string
+	}{
+		{
+			name:         "missing server",
+			wantNotFound: true,
+		},
+		{
+			name:    "deployment delete fails",
+			objects: []runtime.Object{testDeployment("test-ns", "test-server", 1), testService("test-ns", "test-server", 8080)},
+			verb:    "delete",
+			object:  "deployments",
+		},
+		{
+			name:          "service delete fails",
+			objects:       []runtime.Object{testDeployment("test-ns", "test-server", 1), testService("test-ns", "test-server", 8080)},
+			verb:          "delete",
+			object:        "services",
+			wantRemaining: []string{"service test-ns/test-server"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(tt.objects...)
+			if tt.verb != "" {
+				clientset.PrependReactor(tt.verb, tt.object, failOn(fmt.Errorf("injected failure")))
+			}
+			d := NewSimpleDeployer(clientset)
+
+			err := d.DeleteMCPServer(context.Background(), "test-ns", "test-server", DeleteOptions{})
+			if err == nil {
+				t.Fatal("DeleteMCPServer() error = nil, want error")
+			}
+			if got := errors.Is(err, ErrServerNotFound); got != tt.wantNotFound {
+				t.Errorf("errors.Is(%v, ErrServerNotFound) = %t, want %t", err, got, tt.wantNotFound)
+			}
+
+			var partialErr *PartialFailureError
+			if errors.As(err, &partialErr) {
+				if strings.Join(partialErr.Remaining, ",") != strings.Join(tt.wantRemaining, ",") {
+					t.Errorf("Remaining = %v, want %v", partialErr.Remaining, tt.wantRemaining)
+				}
+			} else if tt.wantRemaining != nil {
+				t.Errorf("DeleteMCPServer() error = %v, want PartialFailureError", err)
+			}
+		})
+	}
+}
+
+func TestDeleteMCPServerMissingService(t *testing.T) {
+	d := NewSimpleDeployer(fake.NewSimpleClientset(testDeployment("test-ns", "test-server", 1)))
+
+	if err := d.DeleteMCPServer(context.Background(), "test-ns", "test-server", DeleteOptions{}); err != nil {
+		t.Errorf("DeleteMCPServer() error = %v, want nil when the Service is already gone", err)
+	}
+}
+
+func TestDeleteMCPServerMissingDeployment(t *testing.T) {
+	clientset := fake.NewSimpleClientset(testService("test-ns", "test-server", 8080))
+	d := NewSimpleDeployer(clientset)
+
+	if err := d.DeleteMCPServer(context.Background(), "test-ns", "test-server", DeleteOptions{}); err != nil {
+		t.Fatalf("DeleteMCPServer() error = %v, want nil when only the Service is left", err)
+	}
+	if _, err := clientset.CoreV1().Services("test-ns").Get(context.Background(), "test-server", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("service still present: %v", err)
+	}
+}
+
+func TestDeleteMCPServerPropagation(t *testing.T) {
+	tests := []struct {
+		name               string
+		policy             metav1.DeletionPropagation
+		wantPolicy         metav1.DeletionPropagation
+		wantServiceDeleted bool
+	}{
+		{name: "default", wantPolicy: metav1.DeletePropagationBackground, wantServiceDeleted: true},
+		{name: "foreground", policy: metav1.DeletePropagationForeground, wantPolicy: metav1.DeletePropagationForeground, wantServiceDeleted: true},
+		{name: "orphan", policy: metav1.DeletePropagationOrphan, wantPolicy: metav1.DeletePropagationOrphan},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(
+				testDeployment("test-ns", "test-server", 1),
+				testService("test-ns", "test-server", 8080),
+			)
+			d := NewSimpleDeployer(clientset)
+
+			err := d.DeleteMCPServer(context.Background(), "test-ns", "test-server", DeleteOptions{PropagationPolicy: tt.policy})
+			if err != nil {
+				t.Fatalf("DeleteMCPServer() error = %v", err)
+			}
+
+			serviceDeleted := false
+			for _, action := range clientset.Actions() {
+				deleteAction, ok := action.(k8stesting.DeleteActionImpl)
+				if !ok {
+					continue
+				}
+				if deleteAction.GetResource().Resource == "services" {
+					serviceDeleted = true
+				}
+				policy := deleteAction.DeleteOptions.PropagationPolicy
+				if policy == nil || *policy != tt.wantPolicy {
+					t.Errorf("%s propagation policy = %v, want %s", deleteAction.GetResource().Resource, policy, tt.wantPolicy)
+				}
+			}
+			if serviceDeleted != tt.wantServiceDeleted {
+				t.Errorf("service deleted = %t, want %t", serviceDeleted, tt.wantServiceDeleted)
+			}
+		})
+	}
+}
+
+func TestDryRunMCPServer(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	d := NewSimpleDeployer(clientset)
+
+	objs, err := d.DryRunMCPServer(context.Background(), testSpec())
+	if err != nil {
+		t.Fatalf("DryRunMCPServer() error = %v", err)
+	}
+	if len(objs) != 2 {
+		t.Fatalf("DryRunMCPServer() returned %d objects, want 2", len(objs))
+	}
+	service, ok := objs[1].(*corev1.Service)
+	if !ok {
+		t.Fatalf("second object = %T, want *corev1.Service", objs[1])
+	}
+	if deployment, ok := objs[0].(*appsv1.Deployment); !ok {
+		t.Errorf("first object = %T, want *appsv1.Deployment", objs[0])
+	} else {
+		assertOwnedByDeployment(t, service, deployment)
+	}
+}
+
+// dryRunDeployments records the patch options of Deployments, which the fake
+// clientset does not pass on to reactors
+type dryRunDeployments struct {
+	typedappsv1.DeploymentInterface
+	options *[]metav1.PatchOptions
+}
+
+func (d dryRunDeployments) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*appsv1.Deployment, error) {
+	*d.options = append(*d.options, opts)
+	return d.DeploymentInterface.Patch(ctx, name, pt, data, opts, subresources...)
+}
+
+type dryRunApps struct {
+	typedappsv1.AppsV1Interface
+	options *[]metav1.PatchOptions
+}
+
+func (a dryRunApps) Deployments(namespace string) typedappsv1.DeploymentInterface {
+	return dryRunDeployments{a.AppsV1Interface.Deployments(namespace), a.options}
+}
+
+type dryRunClientset struct {
+	*fake.Clientset
+	options *[]metav1.PatchOptions
+}
+
+func (c dryRunClientset) AppsV1() typedappsv1.AppsV1Interface {
+	return dryRunApps{c.Clientset.AppsV1(), c.options}
+}
+
+func TestDryRunApplyMCPServer(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("patch", "*", applyReactor(clientset))
+	var options []metav1.PatchOptions
+	d := NewSimpleDeployer(dryRunClientset{clientset, &options})
+	spec := testSpec()
+
+	if _, err := d.ApplyMCPServer(context.Background(), spec); err != nil {
+		t.Fatalf("ApplyMCPServer() error = %v", err)
+	}
+	if len(options[0].DryRun) != 0 {
+		t.Errorf("ApplyMCPServer() dry run = %v, want none", options[0].DryRun)
+	}
+
+	// An existing server is previewed as an apply, not rejected as a create
+	changed, err := d.DryRunApplyMCPServer(context.Background(), spec)
+	if err != nil || changed {
+		t.Errorf("DryRunApplyMCPServer() of the applied spec = %v, %v, want unchanged", changed, err)
+	}
+	spec.Image = "example/mcp-server:2.0"
+	changed, err = d.DryRunApplyMCPServer(context.Background(), spec)
+	if err != nil || !changed {
+		t.Errorf("DryRunApplyMCPServer() with a new image = %v, %v, want changed", changed, err)
+	}
+	for _, opts := range options[1:] {
+		if !reflect.DeepEqual(opts.DryRun, []string{metav1.DryRunAll}) || opts.FieldManager != FieldManager {
+			t.Errorf("DryRunApplyMCPServer() patch options = %+v, want a dry run by %s", opts, FieldManager)
+		}
+	}
+}
+
+func TestDryRunMCPServerAdmissionError(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	denied := apierrors.NewForbidden(schema.GroupResource{Resource: "services"}, "test-server", fmt.Errorf("admission webhook denied the request"))
+	clientset.PrependReactor("create", "services", failOn(denied))
+	d := NewSimpleDeployer(clientset)
+
+	_, err := d.DryRunMCPServer(context.Background(), testSpec())
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("DryRunMCPServer() error = %v, want ErrForbidden", err)
+	}
+	if !strings.Contains(fmt.Sprint(err), "admission webhook denied") {
+		t.Errorf("DryRunMCPServer() error = %v, want the admission message", err)
+	}
+
+	// Nothing was persisted, so nothing is rolled back
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "delete" {
+			t.Errorf("unexpected %s of %s", action.GetVerb(), action.GetResource().Resource)
+		}
+	}
+}
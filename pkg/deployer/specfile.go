@@ -0,0 +1,348 @@
+package deployer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// SpecAPIVersion is the apiVersion of the documents in a spec file
+	SpecAPIVersion = "mcp.opendatahub.io/v1alpha1"
+
+	// SpecKind is the kind of the documents in a spec file
+	SpecKind = "MCPServerSpec"
+)
+
+// specDocument is a single document of a spec file
+type specDocument struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	MCPServerSpec
+}
+
+// SpecFileError is returned when a spec file cannot be loaded. It lists every
+// problem found in the file with the line it was found on.
+type SpecFileError struct {
+	// File is the name of the file, or empty if the specs were read from a reader
+	File string
+
+	// Problems lists the individual problems in the order they appear
+	Problems []SpecProblem
+}
+
+// SpecProblem is a single problem in a spec file
+type SpecProblem struct {
+	// Line is the 1-based line of the problem, or 0 if it is not known
+	Line int
+
+	// Message describes the problem, prefixed with its field path if any
+	Message string
+}
+
+// Error implements the error interface, reporting one problem per line
+func (e *SpecFileError) Error() string {
+	lines := make([]string, 0, len(e.Problems))
+	for _, problem := range e.Problems {
+		switch {
+		case e.File != "" && problem.Line > 0:
+			lines = append(lines, fmt.Sprintf("%s:%d: %s", e.File, problem.Line, problem.Message))
+		case e.File != "":
+			lines = append(lines, fmt.Sprintf("%s: %s", e.File, problem.Message))
+		case problem.Line > 0:
+			lines = append(lines, fmt.Sprintf("line %d: %s", problem.Line, problem.Message))
+		default:
+			lines = append(lines, problem.Message)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// LoadSpecFile loads the MCP server specs from a YAML or JSON spec file, see
+// LoadSpecs
+func LoadSpecFile(path, namespace string) ([]*MCPServerSpec, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spec file: %w", err)
+	}
+	defer f.Close()
+
+	specs, err := LoadSpecs(f, namespace)
+	var fileErr *SpecFileError
+	if errors.As(err, &fileErr) {
+		fileErr.File = path
+	}
+	return specs, err
+}
+
+// LoadSpecs loads MCP server specs from YAML or JSON. Each document, separated
+// by "---" in YAML, describes one server and must set apiVersion and kind to
+// SpecAPIVersion and SpecKind. Documents that do not set a namespace get the
+// given one. Unknown fields are rejected and every spec is validated; all
+// problems found are returned together as a *SpecFileError.
+func LoadSpecs(r io.Reader, namespace string) ([]*MCPServerSpec, error) {
+	var specs []*MCPServerSpec
+	var problems []SpecProblem
+	firstLines := make(map[string]int)
+
+	decoder := yaml.NewDecoder(r)
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// The decoder cannot resume after a syntax error
+			problems = append(problems, syntaxProblem(err))
+			break
+		}
+		if len(node.Content) == 0 {
+			continue
+		}
+		root := node.Content[0]
+		if root.Kind == yaml.ScalarNode && root.Tag == "!!null" {
+			// Empty document, e.g. a trailing "---"
+			continue
+		}
+
+		spec, docProblems := loadSpecDocument(root, namespace)
+		problems = append(problems, docProblems...)
+		if spec == nil {
+			continue
+		}
+
+		key := spec.Namespace + "/" + spec.Name
+		if line, ok := firstLines[key]; ok {
+			problems = append(problems, SpecProblem{
+				Line:    root.Line,
+				Message: fmt.Sprintf("duplicate MCP server %s, first defined on line %d", key, line),
+			})
+			continue
+		}
+		firstLines[key] = root.Line
+		specs = append(specs, spec)
+	}
+
+	if len(problems) > 0 {
+		return nil, &SpecFileError{Problems: problems}
+	}
+	return specs, nil
+}
+
+// loadSpecDocument decodes and validates a single document. It returns a nil
+// spec if the document could not be decoded.
+func loadSpecDocument(root *yaml.Node, namespace string) (*MCPServerSpec, []SpecProblem) {
+	if root.Kind != yaml.MappingNode {
+		return nil, []SpecProblem{{Line: root.Line, Message: "document must be a mapping"}}
+	}
+
+	var problems []SpecProblem
+	report := func(line int, path *field.Path, msg string) {
+		problems = append(problems, SpecProblem{Line: line, Message: fmt.Sprintf("%s: %s", path, msg)})
+	}
+	checkFields(root, reflect.TypeOf(specDocument{}), nil, report)
+
+	// Decode each top-level field on its own so that type errors are reported
+	// on the line of the field
+	var doc specDocument
+	value := reflect.ValueOf(&doc).Elem()
+	fields := jsonFields(value.Type())
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, fieldNode := root.Content[i], root.Content[i+1]
+		structField, ok := fields[key.Value]
+		if !ok {
+			continue
+		}
+		if err := decodeField(fieldNode, value.FieldByIndex(structField.Index).Addr().Interface()); err != nil {
+			report(key.Line, field.NewPath(key.Value), err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+
+	if doc.APIVersion != SpecAPIVersion {
+		report(lineOf(root, "apiVersion"), field.NewPath("apiVersion"),
+			field.NotSupported(nil, doc.APIVersion, []string{SpecAPIVersion}).ErrorBody())
+	}
+	if doc.Kind != SpecKind {
+		report(lineOf(root, "kind"), field.NewPath("kind"),
+			field.NotSupported(nil, doc.Kind, []string{SpecKind}).ErrorBody())
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+
+	spec := doc.MCPServerSpec
+	if spec.Namespace == "" {
+		spec.Namespace = namespace
+	}
+	var validationErr *ValidationError
+	if errors.As(spec.Validate(), &validationErr) {
+		for _, fieldErr := range validationErr.Errors {
+			problems = append(problems, SpecProblem{Line: lineOf(root, fieldErr.Field), Message: fieldErr.Error()})
+		}
+	}
+	return &spec, problems
+}
+
+// decodeField decodes a YAML node into a value through JSON, so that the json
+// tags and unmarshalers of the Kubernetes types apply
+func decodeField(node *yaml.Node, into interface{}) error {
+	var raw interface{}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		return errors.New(strings.TrimPrefix(err.Error(), "json: "))
+	}
+	return nil
+}
+
+// syntaxProblem converts a YAML syntax error, which names its line in the
+// message, into a problem
+func syntaxProblem(err error) SpecProblem {
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	if match := syntaxLine.FindStringSubmatch(msg); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		return SpecProblem{Line: line, Message: strings.TrimPrefix(msg, match[0])}
+	}
+	return SpecProblem{Message: msg}
+}
+
+var syntaxLine = regexp.MustCompile(`^line (\d+): `)
+
+var jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// checkFields reports keys in the node that do not match a json field of the
+// type, and keys given twice
+func checkFields(node *yaml.Node, t reflect.Type, path *field.Path, report func(int, *field.Path, string)) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(jsonUnmarshaler) {
+		return
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := jsonFields(t)
+		seen := make(map[string]bool)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			childPath := childOf(path, key.Value)
+			if seen[key.Value] {
+				report(key.Line, childPath, "duplicate field")
+				continue
+			}
+			seen[key.Value] = true
+			structField, ok := fields[key.Value]
+			if !ok {
+				report(key.Line, childPath, "unknown field")
+				continue
+			}
+			checkFields(node.Content[i+1], structField.Type, childPath, report)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		seen := make(map[string]bool)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if seen[key.Value] {
+				report(key.Line, path.Key(key.Value), "duplicate key")
+				continue
+			}
+			seen[key.Value] = true
+			checkFields(node.Content[i+1], t.Elem(), path.Key(key.Value), report)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			checkFields(item, t.Elem(), path.Index(i), report)
+		}
+	}
+}
+
+// childOf returns the path of a field, starting a new path at the root
+func childOf(path *field.Path, name string) *field.Path {
+	if path == nil {
+		return field.NewPath(name)
+	}
+	return path.Child(name)
+}
+
+// jsonFields returns the fields of a struct type by their json names,
+// including fields promoted from embedded structs
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for _, structField := range reflect.VisibleFields(t) {
+		if !structField.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			// Embedded structs without a name are flattened into the parent
+			if structField.Anonymous {
+				continue
+			}
+			name = structField.Name
+		}
+		fields[name] = structField
+	}
+	return fields
+}
+
+var pathElement = regexp.MustCompile(`[^.\[\]]+|\[[^\]]*\]`)
+
+// lineOf returns the line of the field at a path such as
+// envVars[0].valueFrom.secretKeyRef.name, or of its deepest ancestor present
+// in the document
+func lineOf(root *yaml.Node, path string) int {
+	node, line := root, root.Line
+	for _, element := range pathElement.FindAllString(path, -1) {
+		if node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+		element = strings.TrimSuffix(strings.TrimPrefix(element, "["), "]")
+
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == element {
+					line = node.Content[i].Line
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(element); err == nil && i >= 0 && i < len(node.Content) {
+				next = node.Content[i]
+				line = next.Line
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return line
+}
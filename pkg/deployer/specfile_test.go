@@ -0,0 +1,157 @@
+package deployer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSpecs(t *testing.T) {
+	specs, err := LoadSpecFile(filepath.Join("..", "..", "examples", "specs", "servers.yaml"), "default")
+	if err != nil {
+		t.Fatalf("LoadSpecFile() error = %v", err)
+	}
+	if len(specs) != 3 {
+		t.Fatalf("LoadSpecFile() returned %d specs, want 3", len(specs))
+	}
+
+	spec := specs[0]
+	if spec.Name != "github-mcp" || spec.Namespace != "mcp-servers" || spec.Port != 8080 {
+		t.Errorf("spec = %+v", spec)
+	}
+	if len(spec.EnvVars) != 2 || spec.EnvVars[1].ValueFrom == nil || spec.EnvVars[1].ValueFrom.SecretKeyRef.Key != "token" {
+		t.Errorf("env vars = %+v, want a secret reference", spec.EnvVars)
+	}
+	if len(spec.SecretMounts) != 1 || spec.SecretMounts[0].MountPath != "/etc/mcp" {
+		t.Errorf("secret mounts = %+v", spec.SecretMounts)
+	}
+	if spec.Resources == nil || spec.Resources.Limits.Memory().String() != "256Mi" || spec.Resources.Requests.Cpu().MilliValue() != 100 {
+		t.Errorf("resources = %+v", spec.Resources)
+	}
+	if specs[1].Name != "filesystem-mcp" || specs[1].Resources != nil {
+		t.Errorf("second spec = %+v", specs[1])
+	}
+	if pkg := specs[2].Package; pkg == nil || pkg.String() != "uvx:mcp-server-time@0.6.2" || specs[2].Bridge.Sessions != BridgeShared {
+		t.Errorf("third spec package = %+v, want a shared uvx package", pkg)
+	}
+}
+
+func TestLoadSpecsJSON(t *testing.T) {
+	input := `{
+  "apiVersion": "mcp.opendatahub.io/v1alpha1",
+  "kind": "MCPServerSpec",
+  "name": "json-server",
+  "image": "example/mcp-server:1.0",
+  "port": 8080
+}`
+	specs, err := LoadSpecs(strings.NewReader(input), "team-a")
+	if err != nil {
+		t.Fatalf("LoadSpecs() error = %v", err)
+	}
+	if len(specs) != 1 || specs[0].Name != "json-server" || specs[0].Namespace != "team-a" {
+		t.Errorf("LoadSpecs() = %+v, want json-server in the default namespace", specs)
+	}
+}
+
+func TestLoadSpecsErrors(t *testing.T) {
+	header := "apiVersion: mcp.opendatahub.io/v1alpha1\nkind: MCPServerSpec\n"
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "syntax error",
+			input: header + "name: test\n  image: bad\n",
+			want:  []string{"line 4: mapping values are not allowed"},
+		},
+		{
+			name:  "unknown field",
+			input: header + "name: test\nimage: example/mcp:1.0\nport: 8080\nenvVars:\n  - name: A\n    valu: b\n",
+			want:  []string{"line 8: envVars[0].valu: unknown field"},
+		},
+		{
+			name:  "duplicate field",
+			input: header + "name: test\nimage: example/mcp:1.0\nport: 8080\nport: 9090\n",
+			want:  []string{"line 6: port: duplicate field"},
+		},
+		{
+			name:  "wrong type",
+			input: header + "name: test\nimage: example/mcp:1.0\nport: http\n",
+			want:  []string{"line 5: port: cannot unmarshal string"},
+		},
+		{
+			name:  "bad quantity",
+			input: header + "name: test\nimage: example/mcp:1.0\nport: 8080\nresources:\n  limits:\n    memory: lots\n",
+			want:  []string{"line 6: resources: quantities must match"},
+		},
+		{
+			name:  "wrong kind",
+			input: "apiVersion: mcp.opendatahub.io/v1alpha1\nkind: MCPServer\nname: test\nimage: example/mcp:1.0\nport: 8080\n",
+			want:  []string{"line 2: kind: Unsupported value: \"MCPServer\""},
+		},
+		{
+			name:  "missing apiVersion",
+			input: "kind: MCPServerSpec\nname: test\nimage: example/mcp:1.0\nport: 8080\n",
+			want:  []string{"line 1: apiVersion: Unsupported value: \"\""},
+		},
+		{
+			name: "validation errors",
+			input: header + "name: test\nimage: example/mcp:1.0\nport: 8080\nenvVars:\n  - name: TOKEN\n    valueFrom:\n      secretKeyRef:\n        name: creds\n" +
+				"secretMounts:\n  - secretName: config\n    mountPath: etc/config\n",
+			want: []string{
+				"line 9: envVars[0].valueFrom.secretKeyRef.key: Required value",
+				"line 13: secretMounts[0].mountPath: Invalid value",
+			},
+		},
+		{
+			name:  "errors in several documents",
+			input: header + "name: test\nport: 8080\n---\n" + header + "name: Other\nimage: example/mcp:1.0\nport: 8080\n",
+			want:  []string{"line 1: image: Required value", "line 8: name: Invalid value"},
+		},
+		{
+			name:  "duplicate server",
+			input: header + "name: test\nimage: example/mcp:1.0\nport: 8080\n---\n" + header + "name: test\nimage: example/mcp:2.0\nport: 8080\n",
+			want:  []string{"line 7: duplicate MCP server default/test, first defined on line 1"},
+		},
+		{
+			name:  "not a mapping",
+			input: "- name: test\n",
+			want:  []string{"line 1: document must be a mapping"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specs, err := LoadSpecs(strings.NewReader(tt.input), "default")
+			var fileErr *SpecFileError
+			if !errors.As(err, &fileErr) {
+				t.Fatalf("LoadSpecs() = %v, %v, want *SpecFileError", specs, err)
+			}
+			lines := strings.Split(err.Error(), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("LoadSpecs() error =\n%v\nwant %d problems", err, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(lines[i], want) {
+					t.Errorf("problem %d = %q, want prefix %q", i, lines[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadSpecFileError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "servers.yaml")
+	if err := os.WriteFile(path, []byte("apiVersion: v1\nkind: MCPServerSpec\n"), 0o644); err != nil {
+		t.Fatalf("failed to write spec file: %v", err)
+	}
+
+	_, err := LoadSpecFile(path, "default")
+	if err == nil || !strings.HasPrefix(err.Error(), path+":1: apiVersion") {
+		t.Errorf("LoadSpecFile() error = %v, want it prefixed with %s:1", err, path)
+	}
+}
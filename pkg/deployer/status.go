@@ -0,0 +1,140 @@
+package deployer
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// buildServerStatus builds the status of an MCP server from its Deployment,
+// Service and pods. The service may be nil if it does not exist.
+func buildServerStatus(deployment *appsv1.Deployment, service *corev1.Service, pods []*corev1.Pod) MCPServerStatus {
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+
+	status := MCPServerStatus{
+		Name:               deployment.Name,
+		Namespace:          deployment.Namespace,
+		Available:          deployment.Status.AvailableReplicas > 0,
+		Labels:             deployment.Labels,
+		Annotations:        deployment.Annotations,
+		DesiredReplicas:    desired,
+		ReadyReplicas:      deployment.Status.ReadyReplicas,
+		UpdatedReplicas:    deployment.Status.UpdatedReplicas,
+		AvailableReplicas:  deployment.Status.AvailableReplicas,
+		CreationTimestamp:  deployment.CreationTimestamp,
+		ObservedGeneration: deployment.Status.ObservedGeneration,
+	}
+
+	// Extract image from the first container
+	if len(deployment.Spec.Template.Spec.Containers) > 0 {
+		status.Image = deployment.Spec.Template.Spec.Containers[0].Image
+	}
+
+	// Build the endpoint URL from the service (only if deployment is available)
+	transport, path := annotatedEndpoint(deployment.Annotations)
+	status.Transport = transport
+	status.Package = deployment.Annotations[PackageAnnotation]
+	if status.Available && service != nil {
+		status.Endpoint = endpointURL(service, path)
+	}
+
+	// Convert the deployment conditions
+	for _, condition := range deployment.Status.Conditions {
+		status.Conditions = append(status.Conditions, metav1.Condition{
+			Type:               string(condition.Type),
+			Status:             metav1.ConditionStatus(condition.Status),
+			ObservedGeneration: deployment.Status.ObservedGeneration,
+			LastTransitionTime: condition.LastTransitionTime,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
+	}
+
+	// Sum restarts and find the most recent container termination
+	var lastTerminated metav1.Time
+	for _, pod := range pods {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			status.RestartCount += containerStatus.RestartCount
+
+			terminated := containerStatus.LastTerminationState.Terminated
+			if terminated != nil && !terminated.FinishedAt.Before(&lastTerminated) {
+				lastTerminated = terminated.FinishedAt
+				status.LastTerminationReason = terminated.Reason
+			}
+		}
+	}
+
+	status.Phase = serverPhase(deployment, pods)
+
+	return status
+}
+
+// serverPhase computes the phase of an MCP server from its rollout state and
+// the state of its pods
+func serverPhase(deployment *appsv1.Deployment, pods []*corev1.Pod) MCPServerPhase {
+	progress, done, _ := rolloutProgress(deployment)
+	failure := rolloutFailure(deployment, pods)
+
+	switch {
+	case failure != nil && progress.AvailableReplicas > 0:
+		return MCPServerDegraded
+	case failure != nil:
+		return MCPServerFailed
+	case progress.AvailableReplicas == 0 && progress.DesiredReplicas > 0:
+		return MCPServerPending
+	case !done && rolloutComplete(deployment):
+		// Replicas were lost after the rollout had completed
+		return MCPServerDegraded
+	case !done:
+		return MCPServerProgressing
+	}
+	return MCPServerReady
+}
+
+// rolloutFailure returns the failure of the rollout or of one of the pods, or
+// nil if nothing has failed
+func rolloutFailure(deployment *appsv1.Deployment, pods []*corev1.Pod) *RolloutError {
+	if _, _, rolloutErr := rolloutProgress(deployment); rolloutErr != nil {
+		return rolloutErr
+	}
+	for _, pod := range pods {
+		if rolloutErr := podFailure(pod); rolloutErr != nil {
+			return rolloutErr
+		}
+	}
+	return nil
+}
+
+// rolloutComplete reports whether the Deployment controller considers the
+// latest rollout of the Deployment complete
+func rolloutComplete(deployment *appsv1.Deployment) bool {
+	if deployment.Generation > deployment.Status.ObservedGeneration {
+		return false
+	}
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing {
+			return condition.Reason == "NewReplicaSetAvailable"
+		}
+	}
+	return false
+}
+
+// serverPods returns the pods selected by the Deployment
+func serverPods(deployment *appsv1.Deployment, pods []*corev1.Pod) []*corev1.Pod {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil || selector.Empty() {
+		return nil
+	}
+
+	var selected []*corev1.Pod
+	for _, pod := range pods {
+		if pod.Namespace == deployment.Namespace && selector.Matches(labels.Set(pod.Labels)) {
+			selected = append(selected, pod)
+		}
+	}
+	return selected
+}
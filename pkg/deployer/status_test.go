@@ -0,0 +1,225 @@
+package deployer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// phaseDeployment returns a Deployment with the given replica counts and
+// Progressing condition reason
+func phaseDeployment(desired, updated, available int32, progressingReason string) *appsv1.Deployment {
+	deployment := rolloutDeployment(updated, available)
+	deployment.Spec.Replicas = &desired
+	deployment.Status.ReadyReplicas = available
+	if progressingReason != "" {
+		deployment.Status.Conditions = []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: progressingReason},
+		}
+	}
+	return deployment
+}
+
+func TestServerPhase(t *testing.T) {
+	crashing := testPod(corev1.ContainerStatus{
+		Name:  "mcp-server",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+	})
+	unobserved := phaseDeployment(1, 1, 1, "NewReplicaSetAvailable")
+	unobserved.Generation = 2
+	unobserved.Status.ObservedGeneration = 1
+	deadline := phaseDeployment(1, 1, 0, "ProgressDeadlineExceeded")
+
+	tests := []struct {
+		name       string
+		deployment *appsv1.Deployment
+		pods       []*corev1.Pod
+		want       MCPServerPhase
+	}{
+		{name: "no replicas available", deployment: phaseDeployment(1, 1, 0, "ReplicaSetUpdated"), want: MCPServerPending},
+		{name: "rollout in progress", deployment: phaseDeployment(3, 2, 1, "ReplicaSetUpdated"), want: MCPServerProgressing},
+		{name: "update not observed", deployment: unobserved, want: MCPServerProgressing},
+		{name: "all replicas available", deployment: phaseDeployment(2, 2, 2, "NewReplicaSetAvailable"), want: MCPServerReady},
+		{name: "replicas lost after rollout", deployment: phaseDeployment(3, 3, 2, "NewReplicaSetAvailable"), want: MCPServerDegraded},
+		{name: "crashing pod with replicas available", deployment: phaseDeployment(2, 2, 1, "ReplicaSetUpdated"), pods: []*corev1.Pod{crashing}, want: MCPServerDegraded},
+		{name: "crashing pod without replicas available", deployment: phaseDeployment(1, 1, 0, "ReplicaSetUpdated"), pods: []*corev1.Pod{crashing}, want: MCPServerFailed},
+		{name: "progress deadline exceeded", deployment: deadline, want: MCPServerFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serverPhase(tt.deployment, tt.pods); got != tt.want {
+				t.Errorf("serverPhase() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildServerStatus(t *testing.T) {
+	created := metav1.NewTime(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	deployment := phaseDeployment(2, 2, 2, "NewReplicaSetAvailable")
+	deployment.CreationTimestamp = created
+	deployment.Status.ObservedGeneration = 3
+	deployment.Generation = 3
+
+	earlier := metav1.NewTime(created.Add(time.Minute))
+	later := metav1.NewTime(created.Add(time.Hour))
+	pods := []*corev1.Pod{
+		testPod(corev1.ContainerStatus{
+			Name:         "mcp-server",
+			RestartCount: 2,
+			LastTerminationState: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{Reason: "Error", FinishedAt: earlier},
+			},
+		}),
+		testPod(corev1.ContainerStatus{
+			Name:         "mcp-server",
+			RestartCount: 1,
+			LastTerminationState: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", FinishedAt: later},
+			},
+		}),
+	}
+
+	status := buildServerStatus(deployment, testService("test-ns", "test-server", 8080), pods)
+
+	if status.Phase != MCPServerReady {
+		t.Errorf("Phase = %s, want Ready", status.Phase)
+	}
+	if status.DesiredReplicas != 2 || status.ReadyReplicas != 2 || status.UpdatedReplicas != 2 || status.AvailableReplicas != 2 {
+		t.Errorf("replicas = %d/%d/%d/%d, want 2/2/2/2", status.DesiredReplicas, status.ReadyReplicas, status.UpdatedReplicas, status.AvailableReplicas)
+	}
+	if status.RestartCount != 3 {
+		t.Errorf("RestartCount = %d, want 3", status.RestartCount)
+	}
+	if status.LastTerminationReason != "OOMKilled" {
+		t.Errorf("LastTerminationReason = %q, want OOMKilled", status.LastTerminationReason)
+	}
+	if !status.CreationTimestamp.Equal(&created) {
+		t.Errorf("CreationTimestamp = %v, want %v", status.CreationTimestamp, created)
+	}
+	if status.ObservedGeneration != 3 {
+		t.Errorf("ObservedGeneration = %d, want 3", status.ObservedGeneration)
+	}
+	if len(status.Conditions) != 1 || status.Conditions[0].Reason != "NewReplicaSetAvailable" || status.Conditions[0].ObservedGeneration != 3 {
+		t.Errorf("Conditions = %v, want the Progressing condition", status.Conditions)
+	}
+	if status.Endpoint != "http://test-server.test-ns.svc.cluster.local:8080/mcp" {
+		t.Errorf("Endpoint = %q, want http://test-server.test-ns.svc.cluster.local:8080/mcp", status.Endpoint)
+	}
+}
+
+func TestListMCPServersPodStatus(t *testing.T) {
+	other := testPod(corev1.ContainerStatus{Name: "mcp-server", RestartCount: 5})
+	other.Name = "other-pod"
+	other.Labels = map[string]string{MCPServerLabel: "true", "app": "other"}
+
+	deployment := testDeployment("test-ns", "test-server", 1)
+	deployment.Labels["app"] = "test-server"
+	deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: deployment.Labels}
+
+	pod := testPod(corev1.ContainerStatus{Name: "mcp-server", RestartCount: 2})
+	pod.Labels["app"] = "test-server"
+
+	d := NewSimpleDeployer(fake.NewSimpleClientset(deployment, pod, other))
+
+	list, err := d.ListMCPServers(context.Background(), ListOptions{Namespace: "test-ns"})
+	if err != nil {
+		t.Fatalf("ListMCPServers() error = %v", err)
+	}
+	servers := list.Items
+	if len(servers) != 1 || servers[0].RestartCount != 2 {
+		t.Errorf("ListMCPServers() = %+v, want one server with 2 restarts", servers)
+	}
+
+	server, err := d.GetMCPServer(context.Background(), "test-ns", "test-server")
+	if err != nil {
+		t.Fatalf("GetMCPServer() error = %v", err)
+	}
+	if server.RestartCount != 2 {
+		t.Errorf("GetMCPServer() RestartCount = %d, want 2", server.RestartCount)
+	}
+}
+
+func TestServerStatusSharedLabels(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	d := NewSimpleDeployer(clientset)
+	ctx := context.Background()
+
+	// Two servers with the same labels, one crashing
+	restarts := map[string]int32{"a": 0, "b": 7}
+	for _, name := range []string{"a", "b"} {
+		spec := testSpec()
+		spec.Name = name
+		if err := d.DeployMCPServer(ctx, spec); err != nil {
+			t.Fatalf("DeployMCPServer(%s) error = %v", name, err)
+		}
+
+		pod := testPod(corev1.ContainerStatus{Name: "mcp-server", RestartCount: restarts[name]})
+		pod.Name = name + "-abc"
+		pod.Labels = d.buildDeployment(spec).Spec.Template.Labels
+		if name == "b" {
+			pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}
+		}
+		if _, err := clientset.CoreV1().Pods("test-ns").Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create pod: %v", err)
+		}
+	}
+
+	a, err := d.GetMCPServer(ctx, "test-ns", "a")
+	if err != nil {
+		t.Fatalf("GetMCPServer() error = %v", err)
+	}
+	if a.RestartCount != 0 || a.Phase == MCPServerFailed {
+		t.Errorf("server a = %d restarts, phase %s, want no restarts and not failed", a.RestartCount, a.Phase)
+	}
+
+	list, err := d.ListMCPServers(ctx, ListOptions{Namespace: "test-ns"})
+	if err != nil {
+		t.Fatalf("ListMCPServers() error = %v", err)
+	}
+	for _, server := range list.Items {
+		if server.RestartCount != restarts[server.Name] {
+			t.Errorf("server %s RestartCount = %d, want %d", server.Name, server.RestartCount, restarts[server.Name])
+		}
+	}
+}
+
+func TestMCPServerStatusJSON(t *testing.T) {
+	status := buildServerStatus(testDeployment("test-ns", "test-server", 1), testService("test-ns", "test-server", 8080), nil)
+	data, err := json.Marshal(MCPServerList{Items: []MCPServerStatus{status}})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var decoded struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if len(decoded.Items) != 1 {
+		t.Fatalf("items = %d, want 1", len(decoded.Items))
+	}
+
+	// These field names are part of the output format of the wizard
+	item := decoded.Items[0]
+	for _, key := range []string{
+		"name", "namespace", "image", "available", "endpoint", "labels", "phase",
+		"desiredReplicas", "readyReplicas", "updatedReplicas", "availableReplicas",
+		"restartCount", "creationTimestamp", "observedGeneration",
+	} {
+		if _, ok := item[key]; !ok {
+			t.Errorf("JSON is missing %q: %s", key, data)
+		}
+	}
+	if item["endpoint"] != "http://test-server.test-ns.svc.cluster.local:8080/mcp" || item["phase"] != string(status.Phase) {
+		t.Errorf("JSON = %s", data)
+	}
+}
@@ -0,0 +1,81 @@
+package deployer
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// TransportAnnotation records the transport of an MCP server on its
+	// Deployment and Service
+	TransportAnnotation = "mcp.opendatahub.io/transport"
+
+	// PathAnnotation records the endpoint path of an MCP server on its
+	// Deployment and Service
+	PathAnnotation = "mcp.opendatahub.io/path"
+
+	// clusterDomain is the DNS domain of Services in endpoint URLs
+	clusterDomain = "cluster.local"
+)
+
+// DefaultPath returns the endpoint path used when the spec sets none: /sse for
+// SSE and /mcp otherwise, including the bridge of stdio servers
+func DefaultPath(transport Transport) string {
+	if transport == TransportSSE {
+		return "/sse"
+	}
+	return "/mcp"
+}
+
+// endpoint returns the transport and path of the spec, with defaults applied.
+// Packaged servers speak stdio.
+func (s *MCPServerSpec) endpoint() (Transport, string) {
+	transport := s.Transport
+	switch {
+	case transport != "":
+	case s.Package != nil:
+		transport = TransportStdio
+	default:
+		transport = TransportStreamableHTTP
+	}
+	path := s.Path
+	if path == "" {
+		path = DefaultPath(transport)
+	}
+	return transport, path
+}
+
+// mergeAnnotations returns the user annotations with the transport, path and
+// package of the server added
+func (d *SimpleDeployer) mergeAnnotations(spec *MCPServerSpec) map[string]string {
+	transport, path := spec.endpoint()
+	annotations := make(map[string]string, len(spec.Annotations)+3)
+	for k, v := range spec.Annotations {
+		annotations[k] = v
+	}
+	annotations[TransportAnnotation] = string(transport)
+	annotations[PathAnnotation] = path
+	if spec.Package != nil {
+		annotations[PackageAnnotation] = spec.Package.String()
+	}
+	return annotations
+}
+
+// annotatedEndpoint returns the transport and path recorded in annotations.
+// Servers deployed before they were recorded speak streamable HTTP on /mcp.
+func annotatedEndpoint(annotations map[string]string) (Transport, string) {
+	spec := MCPServerSpec{
+		Transport: Transport(annotations[TransportAnnotation]),
+		Path:      annotations[PathAnnotation],
+	}
+	return spec.endpoint()
+}
+
+// endpointURL returns the URL of an MCP server behind its Service
+func endpointURL(service *corev1.Service, path string) string {
+	if len(service.Spec.Ports) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("http://%s.%s.svc.%s:%d%s", service.Name, service.Namespace, clusterDomain, service.Spec.Ports[0].Port, path)
+}
@@ -0,0 +1,73 @@
+package deployer
+
+import "testing"
+
+func TestServerEndpoint(t *testing.T) {
+	tests := []struct {
+		name          string
+		transport     Transport
+		path          string
+		wantTransport Transport
+		wantEndpoint  string
+	}{
+		{
+			name:          "defaults",
+			wantTransport: TransportStreamableHTTP,
+			wantEndpoint:  "http://test-server.test-ns.svc.cluster.local:8080/mcp",
+		},
+		{
+			name:          "streamable http with a path",
+			transport:     TransportStreamableHTTP,
+			path:          "/v1/mcp",
+			wantTransport: TransportStreamableHTTP,
+			wantEndpoint:  "http://test-server.test-ns.svc.cluster.local:8080/v1/mcp",
+		},
+		{
+			name:          "sse",
+			transport:     TransportSSE,
+			wantTransport: TransportSSE,
+			wantEndpoint:  "http://test-server.test-ns.svc.cluster.local:8080/sse",
+		},
+		{
+			name:          "stdio behind the bridge",
+			transport:     TransportStdio,
+			wantTransport: TransportStdio,
+			wantEndpoint:  "http://test-server.test-ns.svc.cluster.local:8080/mcp",
+		},
+	}
+
+	var d SimpleDeployer
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := testSpec()
+			spec.Transport, spec.Path = tt.transport, tt.path
+
+			deployment := d.buildDeployment(spec)
+			service := d.buildService(spec)
+			if deployment.Annotations[TransportAnnotation] != string(tt.wantTransport) ||
+				service.Annotations[TransportAnnotation] != string(tt.wantTransport) {
+				t.Errorf("transport annotations = %q, %q; want %q",
+					deployment.Annotations[TransportAnnotation], service.Annotations[TransportAnnotation], tt.wantTransport)
+			}
+			if _, ok := deployment.Spec.Template.Annotations[TransportAnnotation]; ok {
+				t.Error("pod template carries the transport annotation, want it on the Deployment only")
+			}
+			if spec.Annotations[TransportAnnotation] != "" {
+				t.Error("buildDeployment() modified the spec annotations")
+			}
+
+			deployment.Status.AvailableReplicas = 1
+			status := buildServerStatus(deployment, service, nil)
+			if status.Transport != tt.wantTransport || status.Endpoint != tt.wantEndpoint {
+				t.Errorf("status transport %q, endpoint %q; want %q, %q", status.Transport, status.Endpoint, tt.wantTransport, tt.wantEndpoint)
+			}
+		})
+	}
+
+	t.Run("deployed without annotations", func(t *testing.T) {
+		status := buildServerStatus(testDeployment("test-ns", "test-server", 1), testService("test-ns", "test-server", 9000), nil)
+		if status.Transport != TransportStreamableHTTP || status.Endpoint != "http://test-server.test-ns.svc.cluster.local:9000/mcp" {
+			t.Errorf("status transport %q, endpoint %q; want streamable HTTP on /mcp", status.Transport, status.Endpoint)
+		}
+	})
+}
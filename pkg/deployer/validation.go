@@ -0,0 +1,465 @@
+package deployer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Validate checks the spec for problems that would otherwise only be reported
+// by the API server, or not at all. It returns a *ValidationError listing every
+// problem found, or nil if the spec is valid.
+func (s *MCPServerSpec) Validate() error {
+	var errs field.ErrorList
+
+	errs = append(errs, validateDNSLabel(field.NewPath("name"), s.Name, validation.IsDNS1035Label)...)
+	errs = append(errs, validateDNSLabel(field.NewPath("namespace"), s.Namespace, validation.IsDNS1123Label)...)
+
+	if s.Image == "" && s.Package == nil {
+		errs = append(errs, field.Required(field.NewPath("image"), "unless package is set"))
+	}
+	if s.Package != nil {
+		errs = append(errs, validatePackage(field.NewPath("package"), s.Package)...)
+	}
+
+	for _, msg := range validation.IsValidPortNum(int(s.Port)) {
+		errs = append(errs, field.Invalid(field.NewPath("port"), s.Port, msg))
+	}
+
+	errs = append(errs, validateTransport(s)...)
+
+	if len(s.Command) > 0 && s.Command[0] == "" {
+		errs = append(errs, field.Invalid(field.NewPath("command").Index(0), "", "must not be empty"))
+	}
+	if s.WorkingDir != "" && !path.IsAbs(s.WorkingDir) {
+		errs = append(errs, field.Invalid(field.NewPath("workingDir"), s.WorkingDir, "must be an absolute path"))
+	}
+	errs = append(errs, validateEnvVars(field.NewPath("envVars"), s.EnvVars)...)
+	errs = append(errs, validateSecretMounts(field.NewPath("secretMounts"), s.SecretMounts)...)
+
+	if s.ServiceAccount != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(s.ServiceAccount) {
+			errs = append(errs, field.Invalid(field.NewPath("serviceAccount"), s.ServiceAccount, msg))
+		}
+	}
+
+	errs = append(errs, validateLabels(field.NewPath("labels"), s.Labels)...)
+	errs = append(errs, validateAnnotationKeys(field.NewPath("annotations"), s.Annotations)...)
+
+	if s.Resources != nil {
+		errs = append(errs, validateResources(field.NewPath("resources"), s.Resources)...)
+	}
+
+	if s.Replicas != nil && *s.Replicas < 0 {
+		errs = append(errs, field.Invalid(field.NewPath("replicas"), *s.Replicas, "must be greater than or equal to 0"))
+	}
+	if s.Autoscaling != nil {
+		if s.Replicas != nil {
+			errs = append(errs, field.Forbidden(field.NewPath("replicas"), "must not be set with autoscaling"))
+		}
+		errs = append(errs, validateAutoscaling(field.NewPath("autoscaling"), s.Autoscaling, s.Resources)...)
+	}
+
+	if s.SessionAffinity != nil {
+		errs = append(errs, validateSessionAffinity(field.NewPath("sessionAffinity"), s)...)
+	}
+
+	transport, _ := s.endpoint()
+	errs = append(errs, validateProbe(field.NewPath("readinessProbe"), s.ReadinessProbe, transport)...)
+	errs = append(errs, validateProbe(field.NewPath("livenessProbe"), s.LivenessProbe, transport)...)
+	errs = append(errs, validateProbe(field.NewPath("startupProbe"), s.StartupProbe, transport)...)
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// validateDNSLabel checks a required name against a DNS label validator
+func validateDNSLabel(fldPath *field.Path, value string, validate func(string) []string) field.ErrorList {
+	if value == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+
+	var errs field.ErrorList
+	for _, msg := range validate(value) {
+		errs = append(errs, field.Invalid(fldPath, value, msg))
+	}
+	return errs
+}
+
+// validateEnvVars checks that environment variable names are valid and unique,
+// and that secret references name both a secret and a key
+func validateEnvVars(fldPath *field.Path, envVars []corev1.EnvVar) field.ErrorList {
+	var errs field.ErrorList
+
+	seen := make(map[string]bool)
+	for i, envVar := range envVars {
+		idxPath := fldPath.Index(i)
+
+		if envVar.Name == "" {
+			errs = append(errs, field.Required(idxPath.Child("name"), ""))
+		} else {
+			for _, msg := range validation.IsEnvVarName(envVar.Name) {
+				errs = append(errs, field.Invalid(idxPath.Child("name"), envVar.Name, msg))
+			}
+			if seen[envVar.Name] {
+				errs = append(errs, field.Duplicate(idxPath.Child("name"), envVar.Name))
+			}
+			seen[envVar.Name] = true
+		}
+
+		if envVar.ValueFrom != nil && envVar.ValueFrom.SecretKeyRef != nil {
+			refPath := idxPath.Child("valueFrom", "secretKeyRef")
+			if envVar.ValueFrom.SecretKeyRef.Name == "" {
+				errs = append(errs, field.Required(refPath.Child("name"), ""))
+			}
+			if envVar.ValueFrom.SecretKeyRef.Key == "" {
+				errs = append(errs, field.Required(refPath.Child("key"), ""))
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateSecretMounts checks that secret mounts name a secret and use
+// absolute mount paths that do not overlap each other
+func validateSecretMounts(fldPath *field.Path, secretMounts []SecretMount) field.ErrorList {
+	var errs field.ErrorList
+
+	var mountPaths []string
+	for i, secretMount := range secretMounts {
+		idxPath := fldPath.Index(i)
+
+		if secretMount.SecretName == "" {
+			errs = append(errs, field.Required(idxPath.Child("secretName"), ""))
+		} else {
+			for _, msg := range validation.IsDNS1123Subdomain(secretMount.SecretName) {
+				errs = append(errs, field.Invalid(idxPath.Child("secretName"), secretMount.SecretName, msg))
+			}
+		}
+
+		mountPath := path.Clean(secretMount.MountPath)
+		switch {
+		case secretMount.MountPath == "":
+			errs = append(errs, field.Required(idxPath.Child("mountPath"), ""))
+		case !path.IsAbs(mountPath):
+			errs = append(errs, field.Invalid(idxPath.Child("mountPath"), secretMount.MountPath, "must be an absolute path"))
+		default:
+			for j, other := range mountPaths {
+				if pathsOverlap(mountPath, other) {
+					errs = append(errs, field.Invalid(idxPath.Child("mountPath"), secretMount.MountPath,
+						fmt.Sprintf("overlaps with %s", fldPath.Index(j).Child("mountPath"))))
+				}
+			}
+		}
+		mountPaths = append(mountPaths, mountPath)
+	}
+
+	return errs
+}
+
+// pathsOverlap reports whether two cleaned absolute paths are equal or one
+// contains the other
+func pathsOverlap(a, b string) bool {
+	if a == b || a == "/" || b == "/" {
+		return true
+	}
+	return strings.HasPrefix(a, b+"/") || strings.HasPrefix(b, a+"/")
+}
+
+// validateLabels checks that label keys are qualified names and values are
+// valid label values
+func validateLabels(fldPath *field.Path, labels map[string]string) field.ErrorList {
+	var errs field.ErrorList
+	for _, key := range sortedKeys(labels) {
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = append(errs, field.Invalid(fldPath, key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(labels[key]) {
+			errs = append(errs, field.Invalid(fldPath.Key(key), labels[key], msg))
+		}
+	}
+	return errs
+}
+
+// validateAnnotationKeys checks that annotation keys are qualified names
+func validateAnnotationKeys(fldPath *field.Path, annotations map[string]string) field.ErrorList {
+	var errs field.ErrorList
+	for _, key := range sortedKeys(annotations) {
+		for _, msg := range validation.IsQualifiedName(strings.ToLower(key)) {
+			errs = append(errs, field.Invalid(fldPath, key, msg))
+		}
+	}
+	return errs
+}
+
+// validateResources checks that no resource request exceeds its limit
+func validateResources(fldPath *field.Path, resources *corev1.ResourceRequirements) field.ErrorList {
+	var errs field.ErrorList
+
+	names := make([]string, 0, len(resources.Requests))
+	for name := range resources.Requests {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		request := resources.Requests[corev1.ResourceName(name)]
+		limit, ok := resources.Limits[corev1.ResourceName(name)]
+		if ok && request.Cmp(limit) > 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("requests").Key(name), request.String(),
+				fmt.Sprintf("must be less than or equal to %s limit of %s", name, limit.String())))
+		}
+	}
+
+	return errs
+}
+
+// validateTransport checks the transport, that the path is an absolute URL
+// path, and that stdio servers, and only they, configure a bridge. The bridge
+// needs the command of the server, from the spec, the bridge or a package.
+func validateTransport(s *MCPServerSpec) field.ErrorList {
+	var errs field.ErrorList
+
+	switch s.Transport {
+	case "", TransportStreamableHTTP, TransportSSE, TransportStdio:
+	default:
+		errs = append(errs, field.NotSupported(field.NewPath("transport"), s.Transport,
+			[]string{string(TransportStreamableHTTP), string(TransportSSE), string(TransportStdio)}))
+	}
+
+	if s.Path != "" && !strings.HasPrefix(s.Path, "/") {
+		errs = append(errs, field.Invalid(field.NewPath("path"), s.Path, "must start with /"))
+	}
+
+	transport, _ := s.endpoint()
+	if s.Package != nil && transport != TransportStdio {
+		errs = append(errs, field.Invalid(field.NewPath("transport"), s.Transport, "must be stdio for a package"))
+	}
+
+	bridgePath := field.NewPath("bridge")
+	var bridgeCommand []string
+	if s.Bridge != nil {
+		bridgeCommand = s.Bridge.Command
+	}
+	switch {
+	case transport != TransportStdio:
+		if s.Bridge != nil {
+			errs = append(errs, field.Forbidden(bridgePath, "only applies to the stdio transport"))
+		}
+	case s.Package != nil:
+		if len(s.Command) > 0 {
+			errs = append(errs, field.Forbidden(field.NewPath("command"), "is the command of the package"))
+		}
+		if len(bridgeCommand) > 0 {
+			errs = append(errs, field.Forbidden(bridgePath.Child("command"), "is the command of the package"))
+		}
+	case len(s.Command) > 0 && len(bridgeCommand) > 0:
+		errs = append(errs, field.Forbidden(bridgePath.Child("command"), "must be empty when command is set"))
+	case len(s.Command) == 0 && len(bridgeCommand) == 0:
+		errs = append(errs, field.Required(field.NewPath("command"), "the stdio transport needs the command of the server"))
+	}
+
+	if s.Bridge != nil && transport == TransportStdio {
+		switch s.Bridge.Sessions {
+		case "", BridgePerSession, BridgeShared:
+		default:
+			errs = append(errs, field.NotSupported(bridgePath.Child("sessions"), s.Bridge.Sessions,
+				[]string{string(BridgePerSession), string(BridgeShared)}))
+		}
+	}
+
+	return errs
+}
+
+// validatePackage checks the runtime of a package, and that its name and
+// version cannot be mistaken for options of the runner
+func validatePackage(fldPath *field.Path, pkg *Package) field.ErrorList {
+	var errs field.ErrorList
+
+	switch pkg.Runtime {
+	case RuntimeNPX, RuntimeUVX:
+	case "":
+		errs = append(errs, field.Required(fldPath.Child("runtime"), ""))
+	default:
+		errs = append(errs, field.NotSupported(fldPath.Child("runtime"), pkg.Runtime,
+			[]string{string(RuntimeNPX), string(RuntimeUVX)}))
+	}
+
+	switch {
+	case pkg.Name == "":
+		errs = append(errs, field.Required(fldPath.Child("name"), ""))
+	case strings.HasPrefix(pkg.Name, "-") || strings.ContainsAny(pkg.Name, " \t\n="):
+		errs = append(errs, field.Invalid(fldPath.Child("name"), pkg.Name, "must be a package name"))
+	case strings.Contains(strings.TrimPrefix(pkg.Name, "@"), "@"):
+		// npm scopes start with @, anything after that would be a version
+		errs = append(errs, field.Invalid(fldPath.Child("name"), pkg.Name, "must not include a version; set version instead"))
+	}
+
+	if strings.HasPrefix(pkg.Version, "-") || strings.ContainsAny(pkg.Version, " \t\n") {
+		errs = append(errs, field.Invalid(fldPath.Child("version"), pkg.Version, "must be a package version"))
+	}
+
+	if pkg.CacheClaim != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(pkg.CacheClaim) {
+			errs = append(errs, field.Invalid(fldPath.Child("cacheClaim"), pkg.CacheClaim, msg))
+		}
+	}
+
+	return errs
+}
+
+// validateAutoscaling checks the replica bounds and targets of autoscaling.
+// Utilization is measured against the resource requests of the pods, so the
+// targeted resources must be requested, or limited, which implies a request.
+func validateAutoscaling(fldPath *field.Path, autoscaling *Autoscaling, resources *corev1.ResourceRequirements) field.ErrorList {
+	var errs field.ErrorList
+
+	if autoscaling.Min < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("min"), autoscaling.Min, "must be greater than or equal to 0"))
+	}
+	switch {
+	case autoscaling.Max == 0:
+		errs = append(errs, field.Required(fldPath.Child("max"), ""))
+	case autoscaling.Max < autoscaling.minReplicas():
+		errs = append(errs, field.Invalid(fldPath.Child("max"), autoscaling.Max,
+			fmt.Sprintf("must be greater than or equal to min (%d)", autoscaling.minReplicas())))
+	}
+
+	if autoscaling.TargetCPU == 0 && autoscaling.TargetMemory == 0 {
+		errs = append(errs, field.Required(fldPath, "targetCPU or targetMemory is required"))
+	}
+	for _, target := range []struct {
+		name     string
+		value    int32
+		resource corev1.ResourceName
+	}{
+		{"targetCPU", autoscaling.TargetCPU, corev1.ResourceCPU},
+		{"targetMemory", autoscaling.TargetMemory, corev1.ResourceMemory},
+	} {
+		switch {
+		case target.value < 0:
+			errs = append(errs, field.Invalid(fldPath.Child(target.name), target.value, "must be greater than 0"))
+		case target.value > 0 && !requestsResource(resources, target.resource):
+			errs = append(errs, field.Required(field.NewPath("resources", "requests").Key(string(target.resource)),
+				fmt.Sprintf("%s needs a %s request", fldPath.Child(target.name), target.resource)))
+		}
+	}
+
+	return errs
+}
+
+// requestsResource reports whether pods with the resource requirements request
+// a resource. A limit without a request is also the request.
+func requestsResource(resources *corev1.ResourceRequirements, name corev1.ResourceName) bool {
+	if resources == nil {
+		return false
+	}
+	if request, ok := resources.Requests[name]; ok {
+		return !request.IsZero()
+	}
+	limit, ok := resources.Limits[name]
+	return ok && !limit.IsZero()
+}
+
+// validateSessionAffinity checks the mode of session affinity and that its
+// settings apply to the mode. The router reads the session from the header of
+// the streamable HTTP transport, listens on RouterPort beside the server and
+// adds a Service named after the server.
+func validateSessionAffinity(fldPath *field.Path, s *MCPServerSpec) field.ErrorList {
+	var errs field.ErrorList
+	affinity := s.SessionAffinity
+
+	switch affinity.Mode {
+	case AffinityNone, AffinityClientIP, AffinityRouter:
+	case "":
+		errs = append(errs, field.Required(fldPath.Child("mode"), ""))
+	default:
+		errs = append(errs, field.NotSupported(fldPath.Child("mode"), affinity.Mode,
+			[]string{string(AffinityNone), string(AffinityClientIP), string(AffinityRouter)}))
+	}
+
+	switch {
+	case affinity.TimeoutSeconds != 0 && affinity.Mode != AffinityClientIP:
+		errs = append(errs, field.Forbidden(fldPath.Child("timeoutSeconds"), fmt.Sprintf("only applies to the %s mode", AffinityClientIP)))
+	case affinity.TimeoutSeconds < 0 || affinity.TimeoutSeconds > maxAffinityTimeout:
+		errs = append(errs, field.Invalid(fldPath.Child("timeoutSeconds"), affinity.TimeoutSeconds,
+			fmt.Sprintf("must be between 1 and %d", maxAffinityTimeout)))
+	}
+	if affinity.RouterImage != "" && affinity.Mode != AffinityRouter {
+		errs = append(errs, field.Forbidden(fldPath.Child("routerImage"), fmt.Sprintf("only applies to the %s mode", AffinityRouter)))
+	}
+
+	if affinity.Mode == AffinityRouter {
+		if transport, _ := s.endpoint(); transport == TransportSSE {
+			errs = append(errs, field.Invalid(fldPath.Child("mode"), affinity.Mode,
+				fmt.Sprintf("requires the %s or %s transport", TransportStreamableHTTP, TransportStdio)))
+		}
+		if s.Port == RouterPort {
+			errs = append(errs, field.Invalid(field.NewPath("port"), s.Port, "must not be the port of mcp-router with router session affinity"))
+		}
+		if maxLength := validation.DNS1035LabelMaxLength - len(peersSuffix); len(s.Name) > maxLength {
+			errs = append(errs, field.Invalid(field.NewPath("name"), s.Name,
+				fmt.Sprintf("must be no more than %d characters with router session affinity", maxLength)))
+		}
+	}
+
+	return errs
+}
+
+// validateProbe checks the type, path and timings of a probe. MCP probes speak
+// streamable HTTP, so they need a server that does, directly or through the
+// bridge of a stdio server.
+func validateProbe(fldPath *field.Path, probe *Probe, transport Transport) field.ErrorList {
+	if probe == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	switch probe.Type {
+	case "", ProbeTCP, ProbeMCP, ProbeNone:
+	default:
+		errs = append(errs, field.NotSupported(fldPath.Child("type"), probe.Type,
+			[]string{string(ProbeTCP), string(ProbeMCP), string(ProbeNone)}))
+	}
+	if probe.Type == ProbeMCP && transport == TransportSSE {
+		errs = append(errs, field.Invalid(fldPath.Child("type"), probe.Type,
+			fmt.Sprintf("requires the %s or %s transport", TransportStreamableHTTP, TransportStdio)))
+	}
+	if probe.Path != "" && !strings.HasPrefix(probe.Path, "/") {
+		errs = append(errs, field.Invalid(fldPath.Child("path"), probe.Path, "must start with /"))
+	}
+
+	for _, setting := range []struct {
+		name  string
+		value int32
+	}{
+		{"initialDelaySeconds", probe.InitialDelaySeconds},
+		{"periodSeconds", probe.PeriodSeconds},
+		{"timeoutSeconds", probe.TimeoutSeconds},
+		{"failureThreshold", probe.FailureThreshold},
+	} {
+		if setting.value < 0 {
+			errs = append(errs, field.Invalid(fldPath.Child(setting.name), setting.value, "must be greater than or equal to 0"))
+		}
+	}
+	return errs
+}
+
+// sortedKeys returns the keys of a map in sorted order so that errors are
+// reported deterministically
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,336 @@
+package deployer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		modify     func(spec *MCPServerSpec)
+		wantFields []string
+	}{
+		{
+			name:   "valid spec",
+			modify: func(spec *MCPServerSpec) {},
+		},
+		{
+			name:       "missing name and image",
+			modify:     func(spec *MCPServerSpec) { spec.Name, spec.Image = "", "" },
+			wantFields: []string{"name", "image"},
+		},
+		{
+			name:       "name is not a DNS-1035 label",
+			modify:     func(spec *MCPServerSpec) { spec.Name = "1-Server" },
+			wantFields: []string{"name"},
+		},
+		{
+			name:       "invalid namespace",
+			modify:     func(spec *MCPServerSpec) { spec.Namespace = "Test_NS" },
+			wantFields: []string{"namespace"},
+		},
+		{
+			name:       "port out of range",
+			modify:     func(spec *MCPServerSpec) { spec.Port = 70000 },
+			wantFields: []string{"port"},
+		},
+		{
+			name:       "zero port",
+			modify:     func(spec *MCPServerSpec) { spec.Port = 0 },
+			wantFields: []string{"port"},
+		},
+		{
+			name: "duplicate env var names",
+			modify: func(spec *MCPServerSpec) {
+				spec.EnvVars = append(spec.EnvVars, corev1.EnvVar{Name: "LOG_LEVEL", Value: "info"})
+			},
+			wantFields: []string{"envVars[1].name"},
+		},
+		{
+			name: "empty secret key",
+			modify: func(spec *MCPServerSpec) {
+				spec.EnvVars = append(spec.EnvVars, corev1.EnvVar{
+					Name: "API_KEY",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"},
+						},
+					},
+				})
+			},
+			wantFields: []string{"envVars[1].valueFrom.secretKeyRef.key"},
+		},
+		{
+			name: "overlapping mount paths",
+			modify: func(spec *MCPServerSpec) {
+				spec.SecretMounts = append(spec.SecretMounts,
+					SecretMount{SecretName: "nested", MountPath: "/etc/config/nested"},
+					SecretMount{SecretName: "other", MountPath: "/etc/other"},
+					SecretMount{SecretName: "same", MountPath: "/etc/config/"},
+				)
+			},
+			wantFields: []string{"secretMounts[1].mountPath", "secretMounts[3].mountPath", "secretMounts[3].mountPath"},
+		},
+		{
+			name: "relative mount path",
+			modify: func(spec *MCPServerSpec) {
+				spec.SecretMounts[0].MountPath = "etc/config"
+			},
+			wantFields: []string{"secretMounts[0].mountPath"},
+		},
+		{
+			name: "request greater than limit",
+			modify: func(spec *MCPServerSpec) {
+				spec.Resources.Requests = corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				}
+			},
+			wantFields: []string{"resources.requests[memory]"},
+		},
+		{
+			name: "invalid label key and value",
+			modify: func(spec *MCPServerSpec) {
+				spec.Labels["not a key"] = "ok"
+				spec.Labels["team"] = "has spaces"
+			},
+			wantFields: []string{"labels", "labels[team]"},
+		},
+		{
+			name: "invalid annotation key",
+			modify: func(spec *MCPServerSpec) {
+				spec.Annotations["bad/key/name"] = "value"
+			},
+			wantFields: []string{"annotations"},
+		},
+		{
+			name: "invalid probes",
+			modify: func(spec *MCPServerSpec) {
+				spec.ReadinessProbe = &Probe{Type: "http"}
+				spec.LivenessProbe = &Probe{Type: ProbeMCP, Path: "mcp", PeriodSeconds: -1}
+				spec.StartupProbe = &Probe{Type: ProbeNone}
+			},
+			wantFields: []string{"readinessProbe.type", "livenessProbe.path", "livenessProbe.periodSeconds"},
+		},
+		{
+			name:       "unknown transport and relative path",
+			modify:     func(spec *MCPServerSpec) { spec.Transport, spec.Path = "websocket", "mcp" },
+			wantFields: []string{"transport", "path"},
+		},
+		{
+			name: "stdio server with a path and an mcp probe",
+			modify: func(spec *MCPServerSpec) {
+				spec.Transport, spec.Path = TransportStdio, "/v1/mcp"
+				spec.Bridge = &StdioBridge{Command: []string{"mcp-server"}, Sessions: BridgeShared}
+				spec.ReadinessProbe = &Probe{Type: ProbeMCP}
+			},
+		},
+		{
+			name:       "stdio server without a command",
+			modify:     func(spec *MCPServerSpec) { spec.Transport = TransportStdio },
+			wantFields: []string{"command"},
+		},
+		{
+			name: "stdio server with the command in the spec",
+			modify: func(spec *MCPServerSpec) {
+				spec.Transport, spec.Command = TransportStdio, []string{"python", "-m", "server"}
+			},
+		},
+		{
+			name: "stdio server with two commands",
+			modify: func(spec *MCPServerSpec) {
+				spec.Transport, spec.Command = TransportStdio, []string{"python", "-m", "server"}
+				spec.Bridge = &StdioBridge{Command: []string{"node", "server.js"}}
+			},
+			wantFields: []string{"bridge.command"},
+		},
+		{
+			name: "empty command and relative working directory",
+			modify: func(spec *MCPServerSpec) {
+				spec.Command, spec.WorkingDir = []string{"", "server"}, "srv/app"
+			},
+			wantFields: []string{"command[0]", "workingDir"},
+		},
+		{
+			name: "invalid bridge session mode",
+			modify: func(spec *MCPServerSpec) {
+				spec.Transport = TransportStdio
+				spec.Bridge = &StdioBridge{Command: []string{"mcp-server"}, Sessions: "pooled"}
+			},
+			wantFields: []string{"bridge.sessions"},
+		},
+		{
+			name: "bridge for an http server",
+			modify: func(spec *MCPServerSpec) {
+				spec.Bridge = &StdioBridge{Command: []string{"mcp-server"}}
+			},
+			wantFields: []string{"bridge"},
+		},
+		{
+			name: "package without an image",
+			modify: func(spec *MCPServerSpec) {
+				spec.Image = ""
+				spec.Package = &Package{Runtime: RuntimeNPX, Name: "@modelcontextprotocol/server-everything", Version: "latest"}
+				spec.Bridge = &StdioBridge{Sessions: BridgeShared}
+			},
+		},
+		{
+			name: "invalid package",
+			modify: func(spec *MCPServerSpec) {
+				spec.Package = &Package{Runtime: "pip", Name: "@scope/server@1.0", Version: "1.0 --force", CacheClaim: "Cache_Claim"}
+			},
+			wantFields: []string{"package.runtime", "package.name", "package.version", "package.cacheClaim"},
+		},
+		{
+			name: "package with an http transport and a bridge command",
+			modify: func(spec *MCPServerSpec) {
+				spec.Package = &Package{Runtime: RuntimeUVX, Name: "--from"}
+				spec.Transport = TransportStreamableHTTP
+				spec.Bridge = &StdioBridge{Command: []string{"uvx", "server"}}
+			},
+			wantFields: []string{"package.name", "transport", "bridge"},
+		},
+		{
+			name: "stdio package with a command",
+			modify: func(spec *MCPServerSpec) {
+				spec.Package = &Package{Runtime: RuntimeUVX, Name: "mcp-server-time"}
+				spec.Command = []string{"python"}
+				spec.Bridge = &StdioBridge{Command: []string{"uvx", "server"}}
+			},
+			wantFields: []string{"command", "bridge.command"},
+		},
+		{
+			name:       "negative replicas",
+			modify:     func(spec *MCPServerSpec) { spec.Replicas = int32Ptr(-1) },
+			wantFields: []string{"replicas"},
+		},
+		{
+			name: "autoscaling on the memory limit",
+			modify: func(spec *MCPServerSpec) {
+				spec.Autoscaling = &Autoscaling{Min: 2, Max: 5, TargetMemory: 80}
+			},
+		},
+		{
+			name: "autoscaling with replicas and without a cpu request",
+			modify: func(spec *MCPServerSpec) {
+				spec.Replicas = int32Ptr(2)
+				spec.Autoscaling = &Autoscaling{Max: 3, TargetCPU: 70}
+			},
+			wantFields: []string{"replicas", "resources.requests[cpu]"},
+		},
+		{
+			name: "autoscaling without max or targets",
+			modify: func(spec *MCPServerSpec) {
+				spec.Autoscaling = &Autoscaling{Min: -1, TargetMemory: -5}
+			},
+			wantFields: []string{"autoscaling.min", "autoscaling.max", "autoscaling.targetMemory"},
+		},
+		{
+			name: "autoscaling with max below min",
+			modify: func(spec *MCPServerSpec) {
+				spec.Autoscaling = &Autoscaling{Min: 4, Max: 2}
+			},
+			wantFields: []string{"autoscaling.max", "autoscaling"},
+		},
+		{
+			name: "client ip affinity with a timeout",
+			modify: func(spec *MCPServerSpec) {
+				spec.SessionAffinity = &SessionAffinity{Mode: AffinityClientIP, TimeoutSeconds: 600}
+			},
+		},
+		{
+			name: "session affinity without a mode and with a router image",
+			modify: func(spec *MCPServerSpec) {
+				spec.SessionAffinity = &SessionAffinity{RouterImage: "example/router"}
+			},
+			wantFields: []string{"sessionAffinity.mode", "sessionAffinity.routerImage"},
+		},
+		{
+			name: "client ip affinity with a timeout out of range",
+			modify: func(spec *MCPServerSpec) {
+				spec.SessionAffinity = &SessionAffinity{Mode: AffinityClientIP, TimeoutSeconds: 90000}
+			},
+			wantFields: []string{"sessionAffinity.timeoutSeconds"},
+		},
+		{
+			name: "router affinity with a timeout",
+			modify: func(spec *MCPServerSpec) {
+				spec.SessionAffinity = &SessionAffinity{Mode: AffinityRouter, TimeoutSeconds: 600}
+			},
+			wantFields: []string{"sessionAffinity.timeoutSeconds"},
+		},
+		{
+			name: "router affinity for an sse server on the router port",
+			modify: func(spec *MCPServerSpec) {
+				spec.Transport = TransportSSE
+				spec.Port = RouterPort
+				spec.Name = strings.Repeat("a", 60)
+				spec.SessionAffinity = &SessionAffinity{Mode: AffinityRouter}
+			},
+			wantFields: []string{"sessionAffinity.mode", "port", "name"},
+		},
+		{
+			name: "mcp probe for an sse server",
+			modify: func(spec *MCPServerSpec) {
+				spec.Transport = TransportSSE
+				spec.ReadinessProbe = &Probe{Type: ProbeMCP}
+			},
+			wantFields: []string{"readinessProbe.type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := testSpec()
+			tt.modify(spec)
+
+			err := spec.Validate()
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() error = %v, want ValidationError", err)
+			}
+
+			var fields []string
+			for _, fieldErr := range validationErr.Errors {
+				fields = append(fields, fieldErr.Field)
+			}
+			if len(fields) != len(tt.wantFields) {
+				t.Fatalf("Validate() fields = %v, want %v", fields, tt.wantFields)
+			}
+			for i := range fields {
+				if fields[i] != tt.wantFields[i] {
+					t.Errorf("Validate() fields = %v, want %v", fields, tt.wantFields)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestDeployMCPServerValidatesSpec(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	d := NewSimpleDeployer(clientset)
+	spec := testSpec()
+	spec.Port = -1
+
+	var validationErr *ValidationError
+	if err := d.DeployMCPServer(context.Background(), spec); !errors.As(err, &validationErr) {
+		t.Fatalf("DeployMCPServer() error = %v, want ValidationError", err)
+	}
+	if len(clientset.Actions()) != 0 {
+		t.Errorf("DeployMCPServer() made %d API calls for an invalid spec, want 0", len(clientset.Actions()))
+	}
+}
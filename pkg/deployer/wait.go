@@ -0,0 +1,250 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// revisionAnnotation is set by the Deployment controller on a Deployment and
+	// its ReplicaSets to track rollout revisions
+	revisionAnnotation = "deployment.kubernetes.io/revision"
+)
+
+// podFailureReasons are container waiting reasons that mean a rollout will not
+// succeed without intervention
+var podFailureReasons = map[string]bool{
+	"ImagePullBackOff":           true,
+	"ErrImageNeverPull":          true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CrashLoopBackOff":           true,
+}
+
+// WaitOptions configures WaitForMCPServerReady
+type WaitOptions struct {
+	// Timeout bounds how long to wait. Zero waits until the context is done.
+	Timeout time.Duration
+
+	// OnProgress, if set, is called each time the rollout progress changes
+	OnProgress func(ProgressEvent)
+}
+
+// ProgressEvent describes the progress of an MCP server rollout
+type ProgressEvent struct {
+	DesiredReplicas   int32
+	UpdatedReplicas   int32
+	AvailableReplicas int32
+	Message           string
+}
+
+// WaitForMCPServerReady waits until the rollout of an MCP server has completed
+// and all replicas are available. It returns a *RolloutError as soon as the
+// rollout or one of its pods fails in a way that needs intervention.
+func (d *SimpleDeployer) WaitForMCPServerReady(ctx context.Context, namespace, name string, opts WaitOptions) error {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	deployment, err := d.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get deployment: %w", mapAPIError(err, namespace, name))
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid deployment selector: %w", err)
+	}
+
+	// The Deployment, its ReplicaSets and its pods all carry the selector labels
+	factory := informers.NewSharedInformerFactoryWithOptions(d.clientset, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = selector.String()
+		}))
+	deployments := factory.Apps().V1().Deployments()
+	replicaSets := factory.Apps().V1().ReplicaSets()
+	pods := factory.Core().V1().Pods()
+
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notify() },
+		UpdateFunc: func(interface{}, interface{}) { notify() },
+		DeleteFunc: func(interface{}) { notify() },
+	}
+	for _, informer := range []cache.SharedIndexInformer{deployments.Informer(), replicaSets.Informer(), pods.Informer()} {
+		if _, err := informer.AddEventHandler(handler); err != nil {
+			return fmt.Errorf("failed to watch MCP server: %w", err)
+		}
+	}
+
+	informerCtx, stopInformers := context.WithCancel(ctx)
+	defer func() {
+		stopInformers()
+		factory.Shutdown()
+	}()
+	factory.Start(informerCtx.Done())
+	factory.WaitForCacheSync(informerCtx.Done())
+
+	var last ProgressEvent
+	for {
+		if ctx.Err() != nil {
+			return fmt.Errorf("MCP server %s/%s did not become ready (%s): %w", namespace, name, last.Message, ctx.Err())
+		}
+
+		deployment, err := deployments.Lister().Deployments(namespace).Get(name)
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%w: %s/%s was deleted while waiting for it to become ready", ErrServerNotFound, namespace, name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get deployment: %w", err)
+		}
+
+		progress, done, rolloutErr := rolloutProgress(deployment)
+		if rolloutErr == nil && !done {
+			rsList, _ := replicaSets.Lister().ReplicaSets(namespace).List(labels.Everything())
+			podList, _ := pods.Lister().Pods(namespace).List(labels.Everything())
+			for _, pod := range currentPods(deployment, rsList, podList) {
+				if rolloutErr = podFailure(pod); rolloutErr != nil {
+					break
+				}
+			}
+		}
+		if rolloutErr != nil {
+			rolloutErr.Namespace, rolloutErr.Name = namespace, name
+			return rolloutErr
+		}
+
+		if progress != last && opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+		last = progress
+
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-changed:
+		}
+	}
+}
+
+// rolloutProgress reports how far the rollout of a Deployment has got, whether
+// it is complete, and whether it has exceeded its progress deadline
+func rolloutProgress(deployment *appsv1.Deployment) (ProgressEvent, bool, *RolloutError) {
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+
+	progress := ProgressEvent{
+		DesiredReplicas:   desired,
+		UpdatedReplicas:   deployment.Status.UpdatedReplicas,
+		AvailableReplicas: deployment.Status.AvailableReplicas,
+	}
+
+	if deployment.Generation > deployment.Status.ObservedGeneration {
+		progress.Message = "waiting for the deployment update to be observed"
+		return progress, false, nil
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			return progress, false, &RolloutError{Reason: condition.Reason, Message: condition.Message}
+		}
+	}
+
+	status := deployment.Status
+	switch {
+	case status.UpdatedReplicas < desired:
+		progress.Message = fmt.Sprintf("%d of %d replicas updated", status.UpdatedReplicas, desired)
+	case status.Replicas > status.UpdatedReplicas:
+		progress.Message = fmt.Sprintf("%d old replicas pending termination", status.Replicas-status.UpdatedReplicas)
+	case status.AvailableReplicas < status.UpdatedReplicas:
+		progress.Message = fmt.Sprintf("%d of %d updated replicas available", status.AvailableReplicas, status.UpdatedReplicas)
+	default:
+		progress.Message = fmt.Sprintf("%d of %d replicas available", status.AvailableReplicas, desired)
+		return progress, true, nil
+	}
+
+	return progress, false, nil
+}
+
+// currentPods returns the pods of the Deployment's current ReplicaSet, or all
+// of the given pods if the current ReplicaSet cannot be determined
+func currentPods(deployment *appsv1.Deployment, replicaSets []*appsv1.ReplicaSet, pods []*corev1.Pod) []*corev1.Pod {
+	revision := deployment.Annotations[revisionAnnotation]
+	if revision == "" {
+		return pods
+	}
+
+	for _, replicaSet := range replicaSets {
+		if !metav1.IsControlledBy(replicaSet, deployment) || replicaSet.Annotations[revisionAnnotation] != revision {
+			continue
+		}
+
+		hash := replicaSet.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+		var current []*corev1.Pod
+		for _, pod := range pods {
+			if pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey] == hash {
+				current = append(current, pod)
+			}
+		}
+		return current
+	}
+
+	return pods
+}
+
+// podFailure returns a *RolloutError if a container of the pod is failing in
+// a way that will not resolve itself
+func podFailure(pod *corev1.Pod) *RolloutError {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if terminated := status.State.Terminated; terminated != nil && terminated.Reason == "OOMKilled" {
+			return &RolloutError{
+				Reason:  terminated.Reason,
+				Message: fmt.Sprintf("pod %s container %s was killed for exceeding its memory limit", pod.Name, status.Name),
+			}
+		}
+
+		waiting := status.State.Waiting
+		if waiting == nil || !podFailureReasons[waiting.Reason] {
+			continue
+		}
+
+		// A crash loop caused by the memory limit is reported as such
+		if last := status.LastTerminationState.Terminated; waiting.Reason == "CrashLoopBackOff" && last != nil && last.Reason == "OOMKilled" {
+			return &RolloutError{
+				Reason:  last.Reason,
+				Message: fmt.Sprintf("pod %s container %s is repeatedly killed for exceeding its memory limit", pod.Name, status.Name),
+			}
+		}
+
+		return &RolloutError{
+			Reason:  waiting.Reason,
+			Message: fmt.Sprintf("pod %s container %s: %s", pod.Name, status.Name, waiting.Message),
+		}
+	}
+
+	return nil
+}
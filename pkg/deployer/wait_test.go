@@ -0,0 +1,233 @@
+package deployer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// rolloutDeployment returns a Deployment whose rollout has updatedReplicas
+// updated and availableReplicas available
+func rolloutDeployment(updatedReplicas, availableReplicas int32) *appsv1.Deployment {
+	replicas := int32(1)
+	deployment := testDeployment("test-ns", "test-server", availableReplicas)
+	deployment.Spec.Replicas = &replicas
+	deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: deployment.Labels}
+	deployment.Status.Replicas = updatedReplicas
+	deployment.Status.UpdatedReplicas = updatedReplicas
+	deployment.Status.Conditions = nil
+	return deployment
+}
+
+// testPod returns a pod of the test server with the given container status
+func testPod(status corev1.ContainerStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-server-abc",
+			Namespace: "test-ns",
+			Labels:    map[string]string{MCPServerLabel: "true"},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{status},
+		},
+	}
+}
+
+func TestWaitForMCPServerReady(t *testing.T) {
+	clientset := fake.NewSimpleClientset(rolloutDeployment(1, 0))
+	d := NewSimpleDeployer(clientset)
+
+	var mu sync.Mutex
+	var events []ProgressEvent
+	onProgress := func(event ProgressEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- d.WaitForMCPServerReady(context.Background(), "test-ns", "test-server", WaitOptions{
+			Timeout:    10 * time.Second,
+			OnProgress: onProgress,
+		})
+	}()
+
+	// Wait for the initial progress report before making the server available
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		mu.Lock()
+		n := len(events)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("no progress reported")
+		}
+	}
+
+	if _, err := clientset.AppsV1().Deployments("test-ns").UpdateStatus(context.Background(), rolloutDeployment(1, 1), metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update deployment status: %v", err)
+	}
+
+	if err := <-result; err != nil {
+		t.Fatalf("WaitForMCPServerReady() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 2 {
+		t.Fatalf("progress events = %v, want 2", events)
+	}
+	if events[0].Message != "0 of 1 updated replicas available" {
+		t.Errorf("first event message = %q", events[0].Message)
+	}
+	if events[1].AvailableReplicas != 1 || events[1].Message != "1 of 1 replicas available" {
+		t.Errorf("last event = %+v, want 1 available replica", events[1])
+	}
+}
+
+func TestWaitForMCPServerReadyFailures(t *testing.T) {
+	progressDeadline := rolloutDeployment(1, 0)
+	progressDeadline.Status.Conditions = []appsv1.DeploymentCondition{
+		{
+			Type:    appsv1.DeploymentProgressing,
+			Status:  corev1.ConditionFalse,
+			Reason:  "ProgressDeadlineExceeded",
+			Message: `ReplicaSet "test-server-abc" has timed out progressing.`,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		deployment *appsv1.Deployment
+		pod        *corev1.Pod
+		wantReason string
+	}{
+		{
+			name:       "progress deadline exceeded",
+			deployment: progressDeadline,
+			wantReason: "ProgressDeadlineExceeded",
+		},
+		{
+			name:       "image pull back-off",
+			deployment: rolloutDeployment(1, 0),
+			pod: testPod(corev1.ContainerStatus{
+				Name: "mcp-server",
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"},
+				},
+			}),
+			wantReason: "ImagePullBackOff",
+		},
+		{
+			name:       "crash loop",
+			deployment: rolloutDeployment(1, 0),
+			pod: testPod(corev1.ContainerStatus{
+				Name: "mcp-server",
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+				},
+			}),
+			wantReason: "CrashLoopBackOff",
+		},
+		{
+			name:       "out of memory",
+			deployment: rolloutDeployment(1, 0),
+			pod: testPod(corev1.ContainerStatus{
+				Name: "mcp-server",
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+				},
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+				},
+			}),
+			wantReason: "OOMKilled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(tt.deployment)
+			if tt.pod != nil {
+				if err := clientset.Tracker().Add(tt.pod); err != nil {
+					t.Fatalf("failed to add pod: %v", err)
+				}
+			}
+			d := NewSimpleDeployer(clientset)
+
+			err := d.WaitForMCPServerReady(context.Background(), "test-ns", "test-server", WaitOptions{Timeout: 5 * time.Second})
+
+			var rolloutErr *RolloutError
+			if !errors.As(err, &rolloutErr) {
+				t.Fatalf("WaitForMCPServerReady() error = %v, want RolloutError", err)
+			}
+			if rolloutErr.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", rolloutErr.Reason, tt.wantReason)
+			}
+			if rolloutErr.Namespace != "test-ns" || rolloutErr.Name != "test-server" {
+				t.Errorf("RolloutError server = %s/%s, want test-ns/test-server", rolloutErr.Namespace, rolloutErr.Name)
+			}
+		})
+	}
+}
+
+func TestWaitForMCPServerReadyTimeout(t *testing.T) {
+	d := NewSimpleDeployer(fake.NewSimpleClientset(rolloutDeployment(0, 0)))
+
+	err := d.WaitForMCPServerReady(context.Background(), "test-ns", "test-server", WaitOptions{Timeout: 100 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForMCPServerReady() error = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestWaitForMCPServerReadyNotFound(t *testing.T) {
+	d := NewSimpleDeployer(fake.NewSimpleClientset())
+
+	err := d.WaitForMCPServerReady(context.Background(), "test-ns", "test-server", WaitOptions{Timeout: time.Second})
+	if !errors.Is(err, ErrServerNotFound) {
+		t.Fatalf("WaitForMCPServerReady() error = %v, want ErrServerNotFound", err)
+	}
+}
+
+func TestCurrentPods(t *testing.T) {
+	deployment := rolloutDeployment(1, 0)
+	deployment.UID = "deployment-uid"
+	deployment.Annotations = map[string]string{revisionAnnotation: "2"}
+
+	controller := true
+	replicaSet := func(name, revision, hash string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{revisionAnnotation: revision},
+				Labels:      map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: hash},
+				OwnerReferences: []metav1.OwnerReference{
+					{UID: deployment.UID, Controller: &controller},
+				},
+			},
+		}
+	}
+	pod := func(name, hash string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: hash},
+		}}
+	}
+
+	pods := currentPods(deployment,
+		[]*appsv1.ReplicaSet{replicaSet("old", "1", "aaa"), replicaSet("new", "2", "bbb")},
+		[]*corev1.Pod{pod("old-pod", "aaa"), pod("new-pod", "bbb")},
+	)
+	if len(pods) != 1 || pods[0].Name != "new-pod" {
+		t.Errorf("currentPods() = %v, want only new-pod", pods)
+	}
+}
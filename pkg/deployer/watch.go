@@ -0,0 +1,194 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// WatchEventType is the type of change reported by a WatchEvent
+type WatchEventType string
+
+const (
+	// WatchEventAdded reports an MCP server seen for the first time
+	WatchEventAdded WatchEventType = "Added"
+
+	// WatchEventModified reports a change to the status of an MCP server
+	WatchEventModified WatchEventType = "Modified"
+
+	// WatchEventDeleted reports that an MCP server was deleted. The event
+	// carries the last known status.
+	WatchEventDeleted WatchEventType = "Deleted"
+)
+
+// WatchEvent is a change to an MCP server reported by WatchMCPServers
+type WatchEvent struct {
+	Type   WatchEventType
+	Server MCPServerStatus
+}
+
+// WatchMCPServers watches the MCP servers in a namespace, or in all namespaces
+// if namespace is empty. It first reports every existing server as Added, then
+// reports changes until the context is done, at which point the channel is
+// closed. The watch is backed by shared informers, which relist and resume
+// after disconnects. Rapid successive changes to a server may be coalesced into
+// a single event, but the latest status is always delivered.
+func (d *SimpleDeployer) WatchMCPServers(ctx context.Context, namespace string) (<-chan WatchEvent, error) {
+	factory := newServerInformerFactory(d.clientset, namespace)
+	deployments := factory.Apps().V1().Deployments()
+	services := factory.Core().V1().Services()
+	pods := factory.Core().V1().Pods()
+
+	// Deployments and Services share a name, so a change to either is queued
+	// under the same key and handled by a single worker. Pod changes are queued
+	// under the keys of the Deployments selecting them.
+	queue := workqueue.New()
+	enqueue := func(obj interface{}) {
+		if key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+			queue.Add(key)
+		}
+	}
+	enqueueForPod := func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			return
+		}
+		candidates, err := deployments.Lister().Deployments(pod.Namespace).List(labels.Everything())
+		if err != nil {
+			return
+		}
+		for _, deployment := range candidates {
+			if len(serverPods(deployment, []*corev1.Pod{pod})) > 0 {
+				enqueue(deployment)
+			}
+		}
+	}
+	handlers := map[cache.SharedIndexInformer]cache.ResourceEventHandlerFuncs{
+		deployments.Informer(): {
+			AddFunc:    enqueue,
+			UpdateFunc: func(_, obj interface{}) { enqueue(obj) },
+			DeleteFunc: enqueue,
+		},
+		services.Informer(): {
+			AddFunc:    enqueue,
+			UpdateFunc: func(_, obj interface{}) { enqueue(obj) },
+			DeleteFunc: enqueue,
+		},
+		pods.Informer(): {
+			AddFunc:    enqueueForPod,
+			UpdateFunc: func(_, obj interface{}) { enqueueForPod(obj) },
+			DeleteFunc: enqueueForPod,
+		},
+	}
+	for informer, handler := range handlers {
+		if _, err := informer.AddEventHandler(handler); err != nil {
+			queue.ShutDown()
+			return nil, fmt.Errorf("failed to watch MCP servers: %w", err)
+		}
+	}
+
+	factory.Start(ctx.Done())
+	for informerType, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			queue.ShutDown()
+			factory.Shutdown()
+			return nil, fmt.Errorf("failed to sync %v cache: %w", informerType, ctx.Err())
+		}
+	}
+
+	lookup := func(namespace, name string) (MCPServerStatus, error) {
+		deployment, err := deployments.Lister().Deployments(namespace).Get(name)
+		if err != nil {
+			return MCPServerStatus{}, err
+		}
+		var service *corev1.Service
+		if svc, err := services.Lister().Services(namespace).Get(name); err == nil {
+			service = svc
+		}
+		podList, _ := pods.Lister().Pods(namespace).List(labels.Everything())
+		return buildServerStatus(deployment, service, serverPods(deployment, podList)), nil
+	}
+
+	return runWatch(ctx, queue, lookup, factory.Shutdown), nil
+}
+
+// serverLookup returns the current status of a server, or an error satisfying
+// apierrors.IsNotFound if the server no longer exists
+type serverLookup func(namespace, name string) (MCPServerStatus, error)
+
+// runWatch reports changes to the servers whose keys are added to the queue
+// until the context is done, then closes the channel and calls stop
+func runWatch(ctx context.Context, queue workqueue.Interface, lookup serverLookup, stop func()) <-chan WatchEvent {
+	go func() {
+		<-ctx.Done()
+		queue.ShutDown()
+	}()
+
+	events := make(chan WatchEvent)
+	go func() {
+		defer close(events)
+		defer stop()
+
+		known := make(map[string]MCPServerStatus)
+		for {
+			item, shutdown := queue.Get()
+			if shutdown {
+				return
+			}
+			key := item.(string)
+
+			event, ok := watchEvent(key, known, lookup)
+			queue.Done(item)
+			if !ok {
+				continue
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events
+}
+
+// watchEvent computes the event for a queued server key by comparing the
+// current state with the last status reported. It updates known and returns
+// false if there is nothing to report.
+func watchEvent(key string, known map[string]MCPServerStatus, lookup serverLookup) (WatchEvent, bool) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return WatchEvent{}, false
+	}
+
+	status, err := lookup(namespace, name)
+	if err != nil {
+		previous, ok := known[key]
+		if !ok || !apierrors.IsNotFound(err) {
+			return WatchEvent{}, false
+		}
+		delete(known, key)
+		return WatchEvent{Type: WatchEventDeleted, Server: previous}, true
+	}
+
+	previous, ok := known[key]
+	known[key] = status
+	switch {
+	case !ok:
+		return WatchEvent{Type: WatchEventAdded, Server: status}, true
+	case !reflect.DeepEqual(previous, status):
+		return WatchEvent{Type: WatchEventModified, Server: status}, true
+	}
+	return WatchEvent{}, false
+}
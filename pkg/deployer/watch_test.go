@@ -0,0 +1,88 @@
+package deployer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// nextEvent returns the next event from the channel or fails the test
+func nextEvent(t *testing.T, events <-chan WatchEvent) WatchEvent {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("watch channel closed unexpectedly")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for watch event")
+	}
+	return WatchEvent{}
+}
+
+func TestWatchMCPServers(t *testing.T) {
+	unlabeled := testDeployment("test-ns", "not-mcp", 1)
+	unlabeled.Labels = nil
+	clientset := fake.NewSimpleClientset(testDeployment("test-ns", "existing", 1), unlabeled)
+	d := NewSimpleDeployer(clientset)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := d.WatchMCPServers(ctx, "test-ns")
+	if err != nil {
+		t.Fatalf("WatchMCPServers() error = %v", err)
+	}
+
+	event := nextEvent(t, events)
+	if event.Type != WatchEventAdded || event.Server.Name != "existing" {
+		t.Fatalf("first event = %s %s, want Added existing", event.Type, event.Server.Name)
+	}
+
+	deployments := clientset.AppsV1().Deployments("test-ns")
+	if _, err := deployments.Create(ctx, testDeployment("test-ns", "new", 0), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create deployment: %v", err)
+	}
+	event = nextEvent(t, events)
+	if event.Type != WatchEventAdded || event.Server.Name != "new" || event.Server.Available {
+		t.Fatalf("event = %s %s available=%t, want Added new unavailable", event.Type, event.Server.Name, event.Server.Available)
+	}
+
+	// The Service and the Deployment becoming available both change the status
+	if _, err := clientset.CoreV1().Services("test-ns").Create(ctx, testService("test-ns", "new", 8080), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	if _, err := deployments.UpdateStatus(ctx, testDeployment("test-ns", "new", 1), metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update deployment: %v", err)
+	}
+	for event = nextEvent(t, events); event.Server.Endpoint == ""; event = nextEvent(t, events) {
+		if event.Type != WatchEventModified {
+			t.Fatalf("event = %s, want Modified", event.Type)
+		}
+	}
+	if event.Type != WatchEventModified || !event.Server.Available || event.Server.Endpoint != "http://new.test-ns.svc.cluster.local:8080/mcp" {
+		t.Fatalf("event = %s available=%t endpoint=%q, want Modified with endpoint", event.Type, event.Server.Available, event.Server.Endpoint)
+	}
+
+	if err := deployments.Delete(ctx, "new", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete deployment: %v", err)
+	}
+	event = nextEvent(t, events)
+	if event.Type != WatchEventDeleted || event.Server.Name != "new" || event.Server.Endpoint != "http://new.test-ns.svc.cluster.local:8080/mcp" {
+		t.Fatalf("event = %s %s, want Deleted new with last known status", event.Type, event.Server.Name)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("received event after cancel, want channel closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("watch channel not closed after cancel")
+	}
+}
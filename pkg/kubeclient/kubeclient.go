@@ -0,0 +1,153 @@
+// Package kubeclient loads the configuration for connecting to a Kubernetes
+// cluster the way kubectl does: from the kubeconfig files named by KUBECONFIG
+// or ~/.kube/config, with optional context and namespace overrides, falling
+// back to the service account credentials when running in a pod.
+package kubeclient
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// DefaultNamespace is used when neither the options nor the context set a
+// namespace
+const DefaultNamespace = "default"
+
+var (
+	// inClusterConfig and serviceAccountNamespaceFile are variables so that
+	// tests can simulate running in a pod
+	inClusterConfig             = rest.InClusterConfig
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// Options selects the cluster to connect to
+type Options struct {
+	// Kubeconfig is the path of a kubeconfig file. If empty, the files listed
+	// in KUBECONFIG are merged, or ~/.kube/config is used.
+	Kubeconfig string
+
+	// Context overrides the current context of the kubeconfig
+	Context string
+
+	// Namespace overrides the namespace of the context
+	Namespace string
+}
+
+// Config is the loaded configuration for a cluster
+type Config struct {
+	// REST is the configuration for creating clients
+	REST *rest.Config
+
+	// Context is the name of the kubeconfig context in use, or empty when
+	// running in a pod
+	Context string
+
+	// Namespace is the namespace from the options, the context or the service
+	// account, or DefaultNamespace
+	Namespace string
+
+	// InCluster reports whether the service account credentials of the pod are
+	// used
+	InCluster bool
+}
+
+// Load loads the configuration selected by the options. The in-cluster
+// configuration is used only if no kubeconfig is found and no kubeconfig or
+// context was requested explicitly.
+func Load(opts Options) (*Config, error) {
+	clientConfig := clientConfig(opts)
+	raw, err := clientConfig.RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	if len(raw.Contexts) == 0 && opts.Kubeconfig == "" && opts.Context == "" {
+		restConfig, err := inClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("no kubeconfig found and not running in a cluster: %w", err)
+		}
+		return &Config{
+			REST:      restConfig,
+			Namespace: inClusterNamespace(opts.Namespace),
+			InCluster: true,
+		}, nil
+	}
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config: %w", err)
+	}
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace: %w", err)
+	}
+
+	context := opts.Context
+	if context == "" {
+		context = raw.CurrentContext
+	}
+
+	return &Config{
+		REST:      restConfig,
+		Context:   context,
+		Namespace: namespace,
+	}, nil
+}
+
+// Contexts returns the names of the contexts in the kubeconfig, sorted, and
+// the name of the current one
+func Contexts(opts Options) ([]string, string, error) {
+	raw, err := clientConfig(Options{Kubeconfig: opts.Kubeconfig}).RawConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	names := make([]string, 0, len(raw.Contexts))
+	for name := range raw.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	current := raw.CurrentContext
+	if opts.Context != "" {
+		current = opts.Context
+	}
+	return names, current, nil
+}
+
+// clientConfig returns the kubeconfig loader for the options
+func clientConfig(opts Options) clientcmd.ClientConfig {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = opts.Kubeconfig
+
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: opts.Context,
+		Context: clientcmdapi.Context{
+			Namespace: opts.Namespace,
+		},
+	}
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+}
+
+// inClusterNamespace returns the namespace override, or the namespace of the
+// pod's service account
+func inClusterNamespace(override string) string {
+	if override != "" {
+		return override
+	}
+	data, err := os.ReadFile(serviceAccountNamespaceFile)
+	if err != nil {
+		return DefaultNamespace
+	}
+	if namespace := strings.TrimSpace(string(data)); namespace != "" {
+		return namespace
+	}
+	return DefaultNamespace
+}
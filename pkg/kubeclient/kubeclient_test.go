@@ -0,0 +1,175 @@
+package kubeclient
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+// writeKubeconfig writes a kubeconfig with one cluster per context, each
+// context using the namespace of the same name unless it is empty
+func writeKubeconfig(t *testing.T, dir, name, current string, contexts map[string]string) string {
+	t.Helper()
+
+	var b strings.Builder
+	b.WriteString("apiVersion: v1\nkind: Config\n")
+	b.WriteString("current-context: " + current + "\n")
+	b.WriteString("clusters:\n")
+	for context := range contexts {
+		b.WriteString("- name: " + context + "\n  cluster:\n    server: https://" + context + ".example.com\n")
+	}
+	b.WriteString("users:\n- name: user\n  user:\n    token: secret\n")
+	b.WriteString("contexts:\n")
+	for context, namespace := range contexts {
+		b.WriteString("- name: " + context + "\n  context:\n    cluster: " + context + "\n    user: user\n")
+		if namespace != "" {
+			b.WriteString("    namespace: " + namespace + "\n")
+		}
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	return path
+}
+
+// isolate points the default kubeconfig locations at an empty directory
+func isolate(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("KUBECONFIG", "")
+	return dir
+}
+
+func TestLoad(t *testing.T) {
+	dir := isolate(t)
+	path := writeKubeconfig(t, dir, "config", "dev", map[string]string{"dev": "team-a", "prod": ""})
+
+	tests := []struct {
+		name          string
+		opts          Options
+		wantHost      string
+		wantContext   string
+		wantNamespace string
+	}{
+		{
+			name:          "current context",
+			opts:          Options{Kubeconfig: path},
+			wantHost:      "https://dev.example.com",
+			wantContext:   "dev",
+			wantNamespace: "team-a",
+		},
+		{
+			name:          "context override",
+			opts:          Options{Kubeconfig: path, Context: "prod"},
+			wantHost:      "https://prod.example.com",
+			wantContext:   "prod",
+			wantNamespace: DefaultNamespace,
+		},
+		{
+			name:          "namespace override",
+			opts:          Options{Kubeconfig: path, Namespace: "team-b"},
+			wantHost:      "https://dev.example.com",
+			wantContext:   "dev",
+			wantNamespace: "team-b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := Load(tt.opts)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if config.REST.Host != tt.wantHost || config.Context != tt.wantContext || config.Namespace != tt.wantNamespace || config.InCluster {
+				t.Errorf("Load() = host %q, context %q, namespace %q, in cluster %t; want %q, %q, %q, false",
+					config.REST.Host, config.Context, config.Namespace, config.InCluster, tt.wantHost, tt.wantContext, tt.wantNamespace)
+			}
+		})
+	}
+
+	if _, err := Load(Options{Kubeconfig: path, Context: "missing"}); err == nil {
+		t.Error("Load() with a missing context error = nil, want error")
+	}
+}
+
+func TestLoadMergesKubeconfigFiles(t *testing.T) {
+	dir := isolate(t)
+	first := writeKubeconfig(t, dir, "first", "dev", map[string]string{"dev": "team-a"})
+	second := writeKubeconfig(t, dir, "second", "prod", map[string]string{"prod": "team-b"})
+	t.Setenv("KUBECONFIG", first+string(os.PathListSeparator)+second)
+
+	// The first file that sets the current context wins
+	config, err := Load(Options{})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if config.Context != "dev" || config.Namespace != "team-a" {
+		t.Errorf("Load() = context %q, namespace %q; want dev, team-a", config.Context, config.Namespace)
+	}
+
+	names, current, err := Contexts(Options{})
+	if err != nil {
+		t.Fatalf("Contexts() error = %v", err)
+	}
+	if strings.Join(names, ",") != "dev,prod" || current != "dev" {
+		t.Errorf("Contexts() = %v, %q; want [dev prod], dev", names, current)
+	}
+
+	config, err = Load(Options{Context: "prod"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if config.REST.Host != "https://prod.example.com" || config.Namespace != "team-b" {
+		t.Errorf("Load(prod) = host %q, namespace %q", config.REST.Host, config.Namespace)
+	}
+}
+
+func TestLoadInCluster(t *testing.T) {
+	dir := isolate(t)
+	namespaceFile := filepath.Join(dir, "namespace")
+	if err := os.WriteFile(namespaceFile, []byte("mcp-system\n"), 0o600); err != nil {
+		t.Fatalf("failed to write namespace file: %v", err)
+	}
+
+	restoreConfig, restoreFile := inClusterConfig, serviceAccountNamespaceFile
+	t.Cleanup(func() { inClusterConfig, serviceAccountNamespaceFile = restoreConfig, restoreFile })
+	inClusterConfig = func() (*rest.Config, error) {
+		return &rest.Config{Host: "https://10.0.0.1:443"}, nil
+	}
+	serviceAccountNamespaceFile = namespaceFile
+
+	config, err := Load(Options{})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !config.InCluster || config.REST.Host != "https://10.0.0.1:443" || config.Namespace != "mcp-system" || config.Context != "" {
+		t.Errorf("Load() = %+v, want the in-cluster config in mcp-system", config)
+	}
+
+	config, err = Load(Options{Namespace: "other"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if config.Namespace != "other" {
+		t.Errorf("Load() namespace = %q, want other", config.Namespace)
+	}
+
+	// An explicitly requested context is never replaced by the in-cluster config
+	if _, err := Load(Options{Context: "dev"}); err == nil {
+		t.Error("Load() with a context and no kubeconfig error = nil, want error")
+	}
+
+	inClusterConfig = func() (*rest.Config, error) {
+		return nil, rest.ErrNotInCluster
+	}
+	if _, err := Load(Options{}); !errors.Is(err, rest.ErrNotInCluster) {
+		t.Errorf("Load() outside a cluster error = %v, want ErrNotInCluster", err)
+	}
+}
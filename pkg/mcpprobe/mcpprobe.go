@@ -0,0 +1,160 @@
+// Package mcpprobe checks that an MCP server is serving by performing the
+// initialize handshake of the streamable HTTP transport.
+package mcpprobe
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ProtocolVersion is the MCP protocol version offered in the handshake
+const ProtocolVersion = "2025-03-26"
+
+// sessionHeader carries the session assigned by the server
+const sessionHeader = "Mcp-Session-Id"
+
+// ServerInfo is what the server reports about itself in the handshake
+type ServerInfo struct {
+	Name            string
+	Version         string
+	ProtocolVersion string
+}
+
+// response is a JSON-RPC response to the initialize request
+type response struct {
+	ID     json.RawMessage `json:"id"`
+	Result *struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ServerInfo      struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"serverInfo"`
+	} `json:"result"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Check sends an initialize request to the MCP endpoint at url and returns the
+// server info from the response. The answer may be a JSON body or an event
+// stream. A session opened by the handshake is closed again.
+func Check(ctx context.Context, client *http.Client, url string) (*ServerInfo, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "initialize",
+		"params": map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]interface{}{},
+			"clientInfo":      map[string]string{"name": "mcp-probe", "version": "1.0"},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode initialize request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("initialize request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if session := resp.Header.Get(sessionHeader); session != "" {
+		defer closeSession(ctx, client, url, session)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("initialize request failed: %s", resp.Status)
+	}
+
+	var result response
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/event-stream":
+		err = readEvent(resp.Body, &result)
+	case "application/json":
+		err = json.NewDecoder(resp.Body).Decode(&result)
+	default:
+		return nil, fmt.Errorf("unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid initialize response: %w", err)
+	}
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("initialize failed: %s (code %d)", result.Error.Message, result.Error.Code)
+	}
+	if result.Result == nil || result.Result.ProtocolVersion == "" {
+		return nil, errors.New("invalid initialize response: no protocol version")
+	}
+	return &ServerInfo{
+		Name:            result.Result.ServerInfo.Name,
+		Version:         result.Result.ServerInfo.Version,
+		ProtocolVersion: result.Result.ProtocolVersion,
+	}, nil
+}
+
+// readEvent reads server-sent events until one carries the response to the
+// initialize request. Requests and notifications from the server are skipped.
+func readEvent(r io.Reader, result *response) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var data []string
+	for {
+		more := scanner.Scan()
+		line := scanner.Text()
+		if !more || line == "" {
+			// A blank line, or the end of the stream, ends the event
+			if len(data) > 0 {
+				*result = response{}
+				if err := json.Unmarshal([]byte(strings.Join(data, "\n")), result); err != nil {
+					return err
+				}
+				if string(result.ID) == "1" {
+					return nil
+				}
+				data = nil
+			}
+			if !more {
+				if err := scanner.Err(); err != nil {
+					return err
+				}
+				return errors.New("stream ended without a response")
+			}
+			continue
+		}
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data = append(data, strings.TrimPrefix(value, " "))
+		}
+	}
+}
+
+// closeSession ends the session opened by the handshake, so that probes do not
+// accumulate sessions on the server. Servers may refuse, which is ignored.
+func closeSession(ctx context.Context, client *http.Client, url, session string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set(sessionHeader, session)
+	if resp, err := client.Do(req); err == nil {
+		resp.Body.Close()
+	}
+}
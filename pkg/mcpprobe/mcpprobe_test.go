@@ -0,0 +1,140 @@
+package mcpprobe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const initializeResult = `{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-03-26","capabilities":{},"serverInfo":{"name":"test-server","version":"1.2.3"}}}`
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		status      int
+		body        string
+		wantErr     string
+	}{
+		{
+			name:        "json response",
+			contentType: "application/json",
+			status:      http.StatusOK,
+			body:        initializeResult,
+		},
+		{
+			name:        "event stream with a notification first",
+			contentType: "text/event-stream; charset=utf-8",
+			status:      http.StatusOK,
+			body: "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/message\",\"params\":{}}\n\n" +
+				"event: message\ndata: " + initializeResult + "\n\n",
+		},
+		{
+			name:        "json-rpc error",
+			contentType: "application/json",
+			status:      http.StatusOK,
+			body:        `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"unsupported protocol version"}}`,
+			wantErr:     "initialize failed: unsupported protocol version (code -32602)",
+		},
+		{
+			name:        "missing protocol version",
+			contentType: "application/json",
+			status:      http.StatusOK,
+			body:        `{"jsonrpc":"2.0","id":1,"result":{}}`,
+			wantErr:     "no protocol version",
+		},
+		{
+			name:        "stream without a response",
+			contentType: "text/event-stream",
+			status:      http.StatusOK,
+			body:        "data: {\"jsonrpc\":\"2.0\",\"method\":\"ping\",\"id\":7}\n\n",
+			wantErr:     "stream ended without a response",
+		},
+		{
+			name:        "http error",
+			contentType: "text/plain",
+			status:      http.StatusNotFound,
+			body:        "not found",
+			wantErr:     "404 Not Found",
+		},
+		{
+			name:        "not an mcp endpoint",
+			contentType: "text/html",
+			status:      http.StatusOK,
+			body:        "<html></html>",
+			wantErr:     "unexpected content type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var request struct {
+					Method string `json:"method"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Method != "initialize" {
+					t.Errorf("request method = %q, %v; want initialize", request.Method, err)
+				}
+				if accept := r.Header.Get("Accept"); !strings.Contains(accept, "text/event-stream") {
+					t.Errorf("Accept = %q, want event streams accepted", accept)
+				}
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			info, err := Check(context.Background(), server.Client(), server.URL+"/mcp")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Check() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if *info != (ServerInfo{Name: "test-server", Version: "1.2.3", ProtocolVersion: "2025-03-26"}) {
+				t.Errorf("Check() = %+v", info)
+			}
+		})
+	}
+}
+
+func TestCheckClosesSession(t *testing.T) {
+	var deleted atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted.Store(r.Header.Get("Mcp-Session-Id"))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Mcp-Session-Id", "session-1")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, initializeResult)
+	}))
+	defer server.Close()
+
+	if _, err := Check(context.Background(), server.Client(), server.URL); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if got, _ := deleted.Load().(string); got != "session-1" {
+		t.Errorf("deleted session = %q, want session-1", got)
+	}
+}
+
+func TestCheckUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	if _, err := Check(context.Background(), http.DefaultClient, url); err == nil {
+		t.Error("Check() error = nil, want a connection error")
+	}
+}
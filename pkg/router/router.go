@@ -0,0 +1,240 @@
+// Package router keeps the sessions of a replicated MCP server on the replica
+// that holds them. A router runs beside each replica and receives the requests
+// that the Service spreads over the pods. It tags the session ids issued by its
+// replica with the address of its pod, and sends requests carrying a tagged id
+// to the router of that pod, which serves them from its replica. The routers
+// share no state, and sessions stay on their replica as others come and go.
+//
+// Only the streamable HTTP transport carries the session in a header the
+// router can see; SSE sessions are not routed.
+package router
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// sessionHeader carries the session id in the streamable HTTP transport
+	sessionHeader = "Mcp-Session-Id"
+
+	// forwardedHeader marks a request forwarded by the router of another
+	// replica. It is served by the local replica or not at all, so routers
+	// that disagree about their peers cannot pass it around.
+	forwardedHeader = "Mcp-Router-Forwarded"
+
+	// tagSeparator separates the session id issued by the server from the tag
+	// of its pod
+	tagSeparator = "."
+
+	// peersTTL is how long the addresses of the peers are cached
+	peersTTL = 5 * time.Second
+
+	// missInterval is how long a lookup of the peers is trusted for tags it
+	// did not find, so that stale sessions do not cause a lookup each
+	missInterval = time.Second
+)
+
+// Resolver looks up the addresses of a host
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Options configures a Router
+type Options struct {
+	// Backend is the URL of the MCP server of this replica
+	Backend *url.URL
+
+	// Self is the address of this pod
+	Self net.IP
+
+	// Peers is a host resolving to the addresses of the pods of every
+	// replica, such as a headless Service
+	Peers string
+
+	// Port is the port the routers listen on
+	Port int
+
+	// Resolver looks up Peers, net.DefaultResolver if nil
+	Resolver Resolver
+
+	// Logger reports failures to reach the backend or the peers, the
+	// standard logger if nil
+	Logger *log.Logger
+}
+
+// Router is an http.Handler routing the requests of a session to the replica
+// holding it
+type Router struct {
+	opts  Options
+	tag   string
+	local *httputil.ReverseProxy
+
+	mu        sync.Mutex
+	peers     map[string]string
+	refreshed time.Time
+}
+
+// New returns a router for the options
+func New(opts Options) (*Router, error) {
+	if opts.Backend == nil {
+		return nil, errors.New("a backend URL is required")
+	}
+	if opts.Self == nil {
+		return nil, errors.New("the address of the pod is required")
+	}
+	if opts.Peers == "" {
+		return nil, errors.New("a peers host is required")
+	}
+	if opts.Port <= 0 || opts.Port > 65535 {
+		return nil, errors.New("a valid router port is required")
+	}
+	if opts.Resolver == nil {
+		opts.Resolver = net.DefaultResolver
+	}
+	if opts.Logger == nil {
+		opts.Logger = log.Default()
+	}
+
+	rt := &Router{opts: opts, tag: podTag(opts.Self)}
+	rt.local = &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(opts.Backend)
+			pr.Out.Host = pr.In.Host
+			// The server only knows the id it issued
+			if id, tag, ok := splitSessionID(pr.Out.Header.Get(sessionHeader)); ok && tag == rt.tag {
+				pr.Out.Header.Set(sessionHeader, id)
+			}
+			pr.Out.Header.Del(forwardedHeader)
+		},
+		ModifyResponse: rt.tagResponse,
+		FlushInterval:  -1,
+		ErrorLog:       opts.Logger,
+	}
+	return rt, nil
+}
+
+// ServeHTTP implements http.Handler
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, tag, tagged := splitSessionID(r.Header.Get(sessionHeader))
+	switch {
+	case !tagged || tag == rt.tag:
+		// New sessions start on this replica, as do requests with ids that
+		// no router issued
+		rt.local.ServeHTTP(w, r)
+	case r.Header.Get(forwardedHeader) != "":
+		http.Error(w, "session not found", http.StatusNotFound)
+	default:
+		rt.forward(w, r, tag)
+	}
+}
+
+// forward sends a request to the router of the pod with the tag
+func (rt *Router) forward(w http.ResponseWriter, r *http.Request, tag string) {
+	addr, ok := rt.peer(r.Context(), tag)
+	if !ok {
+		// The replica holding the session is gone. Clients start a new
+		// session when theirs is not found.
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+
+	target := &url.URL{Scheme: "http", Host: addr}
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.Out.Host = pr.In.Host
+			pr.Out.Header.Set(forwardedHeader, rt.tag)
+		},
+		FlushInterval: -1,
+		ErrorLog:      rt.opts.Logger,
+	}
+	proxy.ServeHTTP(w, r)
+}
+
+// tagResponse tags the session id issued by the local server
+func (rt *Router) tagResponse(resp *http.Response) error {
+	if id := resp.Header.Get(sessionHeader); id != "" {
+		resp.Header.Set(sessionHeader, id+tagSeparator+rt.tag)
+	}
+	return nil
+}
+
+// peer returns the address of the router of the pod with the tag. The peers
+// are looked up again once the cached addresses expire, or sooner for a tag
+// that is missing, since it may belong to a pod that has just started.
+func (rt *Router) peer(ctx context.Context, tag string) (string, bool) {
+	rt.mu.Lock()
+	addr, ok := rt.peers[tag]
+	age := time.Since(rt.refreshed)
+	rt.mu.Unlock()
+	if (ok && age < peersTTL) || (!ok && age < missInterval) {
+		return addr, ok
+	}
+
+	peers, err := rt.lookupPeers(ctx)
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.refreshed = time.Now()
+	if err != nil {
+		// Keep routing to the peers found last
+		rt.opts.Logger.Printf("failed to look up peers: %v", err)
+		return addr, ok
+	}
+	rt.peers = peers
+	addr, ok = peers[tag]
+	return addr, ok
+}
+
+// lookupPeers returns the addresses of the routers of the replicas by tag
+func (rt *Router) lookupPeers(ctx context.Context) (map[string]string, error) {
+	addrs, err := rt.opts.Resolver.LookupHost(ctx, rt.opts.Peers)
+	if err != nil {
+		return nil, err
+	}
+
+	peers := make(map[string]string, len(addrs))
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			continue
+		}
+		peers[podTag(ip)] = net.JoinHostPort(addr, strconv.Itoa(rt.opts.Port))
+	}
+	return peers, nil
+}
+
+// podTag returns the tag of the pod with an address: the address in hex
+func podTag(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return hex.EncodeToString(ip4)
+	}
+	return hex.EncodeToString(ip.To16())
+}
+
+// splitSessionID splits a tagged session id into the id issued by the server
+// and the tag of its pod. It returns false for ids that are not tagged.
+func splitSessionID(sessionID string) (string, string, bool) {
+	i := strings.LastIndex(sessionID, tagSeparator)
+	if i <= 0 {
+		return "", "", false
+	}
+	id, tag := sessionID[:i], sessionID[i+len(tagSeparator):]
+	if len(tag) != 2*net.IPv4len && len(tag) != 2*net.IPv6len {
+		return "", "", false
+	}
+	if _, err := hex.DecodeString(tag); err != nil {
+		return "", "", false
+	}
+	return id, tag, true
+}
@@ -0,0 +1,222 @@
+package router
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// staticResolver resolves every host to the same addresses
+type staticResolver []string
+
+func (r staticResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return r, nil
+}
+
+// newReplica serves a toy MCP server that issues a session id, prefixed with
+// its name, to requests without one and answers others with its name and the
+// session id it received
+func newReplica(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+	var sessions atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(sessionHeader)
+		if id == "" {
+			w.Header().Set(sessionHeader, name+"-"+strconv.Itoa(int(sessions.Add(1))))
+			io.WriteString(w, name+" initialized")
+			return
+		}
+		io.WriteString(w, name+" "+id)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestRouters starts a router for each replica, each on its own loopback
+// address and all on the same port, as pods of a server would be
+func newTestRouters(t *testing.T, replicas ...*httptest.Server) []string {
+	t.Helper()
+
+	first, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	port := first.Addr().(*net.TCPAddr).Port
+
+	var addrs []string
+	for i := range replicas {
+		addrs = append(addrs, "127.0.0."+strconv.Itoa(i+1))
+	}
+
+	var urls []string
+	for i, replica := range replicas {
+		listener := first
+		if i > 0 {
+			listener, err = net.Listen("tcp", net.JoinHostPort(addrs[i], strconv.Itoa(port)))
+			if err != nil {
+				t.Skipf("cannot listen on %s: %v", addrs[i], err)
+			}
+		}
+
+		backend, _ := url.Parse(replica.URL)
+		rt, err := New(Options{
+			Backend:  backend,
+			Self:     net.ParseIP(addrs[i]),
+			Peers:    "test-server-peers",
+			Port:     port,
+			Resolver: staticResolver(addrs),
+			Logger:   log.New(io.Discard, "", 0),
+		})
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+
+		server := httptest.NewUnstartedServer(rt)
+		server.Listener.Close()
+		server.Listener = listener
+		server.Start()
+		t.Cleanup(server.Close)
+		urls = append(urls, server.URL)
+	}
+	return urls
+}
+
+// request posts to a router with a session id, returning the status, session
+// id and body of the response
+func request(t *testing.T, url, sessionID string, header http.Header) (int, string, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url+"/mcp", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if sessionID != "" {
+		req.Header.Set(sessionHeader, sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, resp.Header.Get(sessionHeader), strings.TrimSpace(string(body))
+}
+
+func TestRouterKeepsSessionsOnTheirReplica(t *testing.T) {
+	routers := newTestRouters(t, newReplica(t, "a"), newReplica(t, "b"))
+
+	_, sessionA, body := request(t, routers[0], "", nil)
+	if body != "a initialized" || sessionA != "a-1.7f000001" {
+		t.Fatalf("initialize through router a = %q with session %q, want a tagged session of replica a", body, sessionA)
+	}
+	_, sessionB, _ := request(t, routers[1], "", nil)
+	if sessionB != "b-1.7f000002" {
+		t.Fatalf("initialize through router b gave session %q, want a tagged session of replica b", sessionB)
+	}
+
+	for _, router := range routers {
+		for session, want := range map[string]string{sessionA: "a a-1", sessionB: "b b-1"} {
+			status, _, body := request(t, router, session, nil)
+			if status != http.StatusOK || body != want {
+				t.Errorf("request with session %q through %s = %d %q, want %q", session, router, status, body, want)
+			}
+		}
+	}
+}
+
+func TestRouterSessionNotFound(t *testing.T) {
+	routers := newTestRouters(t, newReplica(t, "a"), newReplica(t, "b"))
+
+	tests := []struct {
+		name      string
+		sessionID string
+		header    http.Header
+		want      int
+		wantBody  string
+	}{
+		{name: "untagged session", sessionID: "legacy", want: http.StatusOK, wantBody: "a legacy"},
+		{name: "tag of a missing pod", sessionID: "a-1.7f000009", want: http.StatusNotFound},
+		{
+			name:      "forwarded to the wrong pod",
+			sessionID: "b-1.7f000002",
+			header:    http.Header{forwardedHeader: []string{"7f000002"}},
+			want:      http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, _, body := request(t, routers[0], tt.sessionID, tt.header)
+			if status != tt.want {
+				t.Errorf("status = %d, want %d", status, tt.want)
+			}
+			if tt.wantBody != "" && body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestSplitSessionID(t *testing.T) {
+	tests := []struct {
+		sessionID string
+		wantID    string
+		wantTag   string
+		wantOK    bool
+	}{
+		{sessionID: "abc.0a000105", wantID: "abc", wantTag: "0a000105", wantOK: true},
+		{sessionID: "a.b.0a000105", wantID: "a.b", wantTag: "0a000105", wantOK: true},
+		{sessionID: "abc.fd000000000000000000000000000001", wantID: "abc", wantTag: "fd000000000000000000000000000001", wantOK: true},
+		{sessionID: "abc"},
+		{sessionID: ".0a000105"},
+		{sessionID: "abc.0a0001"},
+		{sessionID: "abc.zzzzzzzz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sessionID, func(t *testing.T) {
+			id, tag, ok := splitSessionID(tt.sessionID)
+			if id != tt.wantID || tag != tt.wantTag || ok != tt.wantOK {
+				t.Errorf("splitSessionID() = %q, %q, %v, want %q, %q, %v", id, tag, ok, tt.wantID, tt.wantTag, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	backend, _ := url.Parse("http://127.0.0.1:8080")
+	valid := Options{Backend: backend, Self: net.ParseIP("10.0.0.1"), Peers: "test-server-peers", Port: 8090}
+
+	tests := []struct {
+		name   string
+		modify func(opts *Options)
+	}{
+		{name: "no backend", modify: func(opts *Options) { opts.Backend = nil }},
+		{name: "no address", modify: func(opts *Options) { opts.Self = nil }},
+		{name: "no peers", modify: func(opts *Options) { opts.Peers = "" }},
+		{name: "no port", modify: func(opts *Options) { opts.Port = 0 }},
+	}
+
+	if _, err := New(valid); err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := valid
+			tt.modify(&opts)
+			if _, err := New(opts); err == nil {
+				t.Error("New() succeeded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Package selfinstall lets the helper binaries of this repo copy themselves
+// into a volume shared with the MCP server container. Their images have no
+// shell or cp, so an init container runs the binary itself to do the copy.
+package selfinstall
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Install copies the running binary to dest
+func Install(dest string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find executable: %w", err)
+	}
+	src, err := os.Open(self)
+	if err != nil {
+		return fmt.Errorf("failed to open executable: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dest, err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return fmt.Errorf("failed to copy to %s: %w", dest, err)
+	}
+	return dst.Close()
+}